@@ -0,0 +1,286 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package archive keeps every search result it is given in an embedded
+// database, keyed by MatchedObjectID, with the times each posting was first
+// and last seen and each version of it that was archived.
+package archive
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+	bolt "go.etcd.io/bbolt"
+)
+
+// ErrNotFound is returned when a posting is not in the archive.
+var ErrNotFound = errors.New("posting not found in archive")
+
+var (
+	postingsBucket = []byte("postings")
+	versionsBucket = []byte("versions")
+)
+
+// Posting is an archived search result.
+type Posting struct {
+	MatchedObjectID string    `json:"matchedObjectId"`
+	FirstSeen       time.Time `json:"firstSeen"`
+	LastSeen        time.Time `json:"lastSeen"`
+	// Versions is the number of different versions archived.
+	Versions int `json:"versions"`
+	// Item is the latest version.
+	Item usajobs.SearchResultItem `json:"item"`
+}
+
+// Version is the content of a posting from when it was first seen until the
+// next version was.
+type Version struct {
+	// Number counts the versions of a posting from 1.
+	Number int                      `json:"version"`
+	SeenAt time.Time                `json:"seenAt"`
+	Item   usajobs.SearchResultItem `json:"item"`
+}
+
+// AddResult counts the postings an Add archived.
+type AddResult struct {
+	// New postings were not in the archive.
+	New int `json:"new"`
+	// Updated postings were archived with a new version.
+	Updated int `json:"updated"`
+	// Unchanged postings only had their last seen time updated.
+	Unchanged int `json:"unchanged"`
+}
+
+// Archive is a posting archive stored in a single file.
+type Archive struct {
+	db *bolt.DB
+}
+
+// DefaultPath returns the file the archive is kept in when one is not
+// provided, usually ~/.config/usajobs/archive.db.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "usajobs", "archive.db"), nil
+}
+
+// Open opens the archive at path, creating it if needed. Only one process
+// can have an archive open at a time.
+func Open(path string) (*Archive, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0o700)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("could not open archive %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, b := range [][]byte{postingsBucket, versionsBucket} {
+			_, err := tx.CreateBucketIfNotExists(b)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Archive{db: db}, nil
+}
+
+// Close closes the archive.
+func (a *Archive) Close() error {
+	return a.db.Close()
+}
+
+// Add archives search results seen at seenAt. A posting whose content
+// differs from its latest version is archived as a new version. Results
+// without a MatchedObjectID are skipped.
+func (a *Archive) Add(items []usajobs.SearchResultItem, seenAt time.Time) (AddResult, error) {
+	var result AddResult
+	err := a.db.Update(func(tx *bolt.Tx) error {
+		postings := tx.Bucket(postingsBucket)
+		versions := tx.Bucket(versionsBucket)
+
+		for _, item := range items {
+			id := strings.TrimSpace(item.MatchedObjectID)
+			if id == "" {
+				continue
+			}
+			item = normalize(item)
+
+			var p Posting
+			data := postings.Get([]byte(id))
+			if data != nil {
+				err := json.Unmarshal(data, &p)
+				if err != nil {
+					return fmt.Errorf("%s: %w", id, err)
+				}
+			}
+
+			changed, err := differs(p.Item, item)
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case data == nil:
+				p = Posting{MatchedObjectID: id, FirstSeen: seenAt, LastSeen: seenAt}
+				result.New++
+			case changed:
+				result.Updated++
+			default:
+				result.Unchanged++
+			}
+
+			if seenAt.After(p.LastSeen) {
+				p.LastSeen = seenAt
+			}
+			if seenAt.Before(p.FirstSeen) {
+				p.FirstSeen = seenAt
+			}
+
+			if data == nil || changed {
+				p.Versions++
+				p.Item = item
+				err := putJSON(versions, versionKey(id, p.Versions), Version{Number: p.Versions, SeenAt: seenAt, Item: item})
+				if err != nil {
+					return err
+				}
+			}
+
+			err = putJSON(postings, []byte(id), p)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+// Get returns the archived posting with the MatchedObjectID id.
+func (a *Archive) Get(id string) (Posting, error) {
+	var p Posting
+	err := a.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(postingsBucket).Get([]byte(strings.TrimSpace(id)))
+		if data == nil {
+			return fmt.Errorf("%s: %w", id, ErrNotFound)
+		}
+		return json.Unmarshal(data, &p)
+	})
+	return p, err
+}
+
+// Versions returns every version of the posting with the MatchedObjectID
+// id, oldest first.
+func (a *Archive) Versions(id string) ([]Version, error) {
+	id = strings.TrimSpace(id)
+
+	var all []Version
+	err := a.db.View(func(tx *bolt.Tx) error {
+		prefix := versionKey(id, 0)[:len(id)+1]
+		c := tx.Bucket(versionsBucket).Cursor()
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			var version Version
+			err := json.Unmarshal(v, &version)
+			if err != nil {
+				return fmt.Errorf("%s: %w", k, err)
+			}
+			all = append(all, version)
+		}
+		return nil
+	})
+	if err == nil && len(all) == 0 {
+		err = fmt.Errorf("%s: %w", id, ErrNotFound)
+	}
+	return all, err
+}
+
+// Each calls fn with every archived posting in MatchedObjectID order until
+// fn returns an error.
+func (a *Archive) Each(fn func(Posting) error) error {
+	return a.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(postingsBucket).ForEach(func(k, v []byte) error {
+			var p Posting
+			err := json.Unmarshal(v, &p)
+			if err != nil {
+				return fmt.Errorf("%s: %w", k, err)
+			}
+			return fn(p)
+		})
+	})
+}
+
+// Len returns the number of archived postings.
+func (a *Archive) Len() (int, error) {
+	var n int
+	err := a.db.View(func(tx *bolt.Tx) error {
+		n = tx.Bucket(postingsBucket).Stats().KeyN
+		return nil
+	})
+	return n, err
+}
+
+// normalize clears the fields of a search result that depend on the search
+// that returned it rather than on the posting, so they do not create new
+// versions.
+func normalize(item usajobs.SearchResultItem) usajobs.SearchResultItem {
+	item.RelevanceRank = 0
+	item.MatchedObjectDescriptor.UserArea.IsRadialSearch = false
+	item.MatchedObjectDescriptor.UserArea.Details.WithinArea = ""
+	item.MatchedObjectDescriptor.UserArea.Details.CommuteDistance = ""
+	return item
+}
+
+func differs(a, b usajobs.SearchResultItem) (bool, error) {
+	ja, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+	jb, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+	return !bytes.Equal(ja, jb), nil
+}
+
+// versionKey orders the versions of a posting after its MatchedObjectID.
+func versionKey(id string, n int) []byte {
+	return []byte(fmt.Sprintf("%s/%08d", id, n))
+}
+
+func putJSON(b *bolt.Bucket, key []byte, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return b.Put(key, data)
+}
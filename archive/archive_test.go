@@ -0,0 +1,170 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package archive_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/JeffRDay/go-usajobs/archive"
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+)
+
+// loadItems returns the postings of the search test data.
+func loadItems(t *testing.T) []usajobs.SearchResultItem {
+	t.Helper()
+
+	data, err := os.ReadFile("../testdata/search-testdata.json")
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	var resp usajobs.SearchResponse
+	err = json.Unmarshal(data, &resp)
+	if err != nil {
+		t.Fatalf("could not unmarshal test data: %v", err)
+	}
+	return resp.SearchResult.SearchResultItems
+}
+
+func openArchive(t *testing.T) *archive.Archive {
+	t.Helper()
+
+	a, err := archive.Open(filepath.Join(t.TempDir(), "archive", "archive.db"))
+	if err != nil {
+		t.Fatalf("could not open archive: %v", err)
+	}
+	t.Cleanup(func() { a.Close() })
+	return a
+}
+
+func TestArchiveAdd(t *testing.T) {
+	a := openArchive(t)
+	items := loadItems(t)
+	first := time.Date(2024, 7, 6, 0, 0, 0, 0, time.UTC)
+
+	result, err := a.Add(items, first)
+	if err != nil {
+		t.Fatalf("could not add postings: %v", err)
+	}
+	if result != (archive.AddResult{New: 3}) {
+		t.Errorf("expected 3 new postings, got %+v", result)
+	}
+
+	// a search specific field does not create a version, a changed close
+	// date does
+	items[0].RelevanceRank = 99
+	items[1].MatchedObjectDescriptor.ApplicationCloseDate = "2024-08-30T23:59:59.9970"
+	second := first.Add(24 * time.Hour)
+	result, err = a.Add(items, second)
+	if err != nil {
+		t.Fatalf("could not add postings: %v", err)
+	}
+	if result != (archive.AddResult{Updated: 1, Unchanged: 2}) {
+		t.Errorf("expected 1 updated and 2 unchanged postings, got %+v", result)
+	}
+
+	p, err := a.Get(items[1].MatchedObjectID)
+	if err != nil {
+		t.Fatalf("could not get posting: %v", err)
+	}
+	if !p.FirstSeen.Equal(first) || !p.LastSeen.Equal(second) || p.Versions != 2 {
+		t.Errorf("expected first seen %v, last seen %v, and 2 versions, got %+v", first, second, p)
+	}
+	if p.Item.MatchedObjectDescriptor.ApplicationCloseDate != "2024-08-30T23:59:59.9970" {
+		t.Errorf("expected the latest version, got %s", p.Item.MatchedObjectDescriptor.ApplicationCloseDate)
+	}
+
+	versions, err := a.Versions(items[1].MatchedObjectID)
+	if err != nil {
+		t.Fatalf("could not get versions: %v", err)
+	}
+	if len(versions) != 2 || versions[0].Number != 1 || !versions[1].SeenAt.Equal(second) {
+		t.Errorf("expected 2 versions in order, got %+v", versions)
+	}
+
+	versions, err = a.Versions(items[0].MatchedObjectID)
+	if err != nil || len(versions) != 1 || versions[0].Item.RelevanceRank != 0 {
+		t.Errorf("expected 1 version without a relevance rank, got %+v %v", versions, err)
+	}
+
+	n, err := a.Len()
+	if err != nil || n != 3 {
+		t.Errorf("expected 3 postings, got %d %v", n, err)
+	}
+
+	_, err = a.Get("missing")
+	if !errors.Is(err, archive.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	_, err = a.Versions("80000000")
+	if !errors.Is(err, archive.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for an id that prefixes another, got %v", err)
+	}
+}
+
+func TestArchiveQuery(t *testing.T) {
+	a := openArchive(t)
+	seen := time.Date(2024, 7, 6, 0, 0, 0, 0, time.UTC)
+	_, err := a.Add(loadItems(t), seen)
+	if err != nil {
+		t.Fatalf("could not add postings: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		query archive.Query
+		want  []string
+	}{
+		{"all, most recently opened first", archive.Query{}, []string{"800000001", "800000003", "800000002"}},
+		{"department", archive.Query{Department: "army"}, []string{"800000002"}},
+		{"agency", archive.Query{Agency: "veterans health"}, []string{"800000003"}},
+		{"series", archive.Query{Series: []string{"0854", "2210"}}, []string{"800000001", "800000002"}},
+		{"state", archive.Query{State: "texas"}, []string{"800000001", "800000002"}},
+		{"remote", archive.Query{Remote: true}, []string{"800000002"}},
+		{"salary", archive.Query{MinSalary: 140000}, []string{"800000001", "800000003"}},
+		{"opened", archive.Query{OpenedSince: time.Date(2024, 7, 2, 0, 0, 0, 0, time.UTC), OpenedBefore: time.Date(2024, 7, 5, 0, 0, 0, 0, time.UTC)}, []string{"800000003"}},
+		{"seen since", archive.Query{SeenSince: seen.Add(time.Hour)}, nil},
+		{"seen before", archive.Query{SeenBefore: seen.Add(time.Hour)}, []string{"800000001", "800000003", "800000002"}},
+		{"limit", archive.Query{Limit: 1}, []string{"800000001"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postings, err := a.Query(tt.query)
+			if err != nil {
+				t.Fatalf("could not query: %v", err)
+			}
+
+			var got []string
+			for _, p := range postings {
+				got = append(got, p.MatchedObjectID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("expected %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+}
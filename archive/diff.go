@@ -0,0 +1,318 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package archive
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+)
+
+// listFields are the search item fields whose values are lists, which
+// changes report as the values added and removed.
+var listFields = map[string]bool{
+	"LOCATIONS":          true,
+	"CITIES":             true,
+	"STATES":             true,
+	"COUNTRIES":          true,
+	"COORDINATES":        true,
+	"JOB_CATEGORIES":     true,
+	"JOB_CATEGORY_CODES": true,
+	"PAY_PLANS":          true,
+	"SCHEDULES":          true,
+	"OFFERING_TYPES":     true,
+	"HIRING_PATHS":       true,
+	"APPLY_URL":          true,
+	"MAJOR_DUTIES":       true,
+	"KEY_REQUIREMENTS":   true,
+	"ADJUDICATION_TYPES": true,
+}
+
+// listValues returns the values of list fields whose values are prose that
+// can contain the field separator, so they are compared as the lists usajobs
+// returns rather than split from the field value.
+var listValues = map[string]func(usajobs.SearchResultItem) []string{
+	"MAJOR_DUTIES": func(i usajobs.SearchResultItem) []string {
+		return i.MatchedObjectDescriptor.UserArea.Details.MajorDuties
+	},
+	"KEY_REQUIREMENTS": func(i usajobs.SearchResultItem) []string {
+		var s []string
+		for _, r := range i.MatchedObjectDescriptor.UserArea.Details.KeyRequirements {
+			s = append(s, fmt.Sprint(r))
+		}
+		return s
+	},
+}
+
+// locationFields are the search item fields derived from the locations of
+// a posting, which are only reported as changed when LOCATIONS is not.
+var locationFields = map[string]bool{
+	"CITIES":      true,
+	"STATES":      true,
+	"COUNTRIES":   true,
+	"COORDINATES": true,
+}
+
+// Change is a field that differs between two versions of a posting.
+type Change struct {
+	// Field is the name of the search item field (ex., CLOSE_DATE), or the
+	// path to the changed value for changes to values without one.
+	Field string `json:"field"`
+	Old   string `json:"old,omitempty"`
+	New   string `json:"new,omitempty"`
+	// Added and Removed are the values added to and removed from list
+	// fields such as LOCATIONS.
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// Amendment is a version of a posting and how it differs from the version
+// before it.
+type Amendment struct {
+	Version int       `json:"version"`
+	SeenAt  time.Time `json:"seenAt"`
+	// Changes is empty for the first version.
+	Changes []Change `json:"changes,omitempty"`
+}
+
+// Diff returns the fields that differ between two versions of a posting, in
+// the order of usajobs.SearchItemFields, followed by changes to values
+// without a search item field, named by their path. Changes to the cities,
+// states, countries and coordinates of changed locations are left out.
+func Diff(before, after usajobs.SearchResultItem) []Change {
+	var locationsChanged bool
+	for _, f := range usajobs.SearchItemFields {
+		if f.Name == "LOCATIONS" {
+			locationsChanged = f.Value(before) != f.Value(after)
+		}
+	}
+
+	var changes []Change
+	for _, f := range usajobs.SearchItemFields {
+		if f.Name == "MATCHED_OBJECT_ID" || f.Name == "RELEVANCE_RANK" || (locationsChanged && locationFields[f.Name]) {
+			continue
+		}
+
+		o, n := f.Value(before), f.Value(after)
+		if o == n {
+			continue
+		}
+
+		c := Change{Field: f.Name, Old: o, New: n}
+		if values, ok := listValues[f.Name]; ok {
+			c.Added, c.Removed = listDiff(values(before), values(after))
+		} else if listFields[f.Name] {
+			c.Added, c.Removed = listDiff(splitField(o), splitField(n))
+		}
+		changes = append(changes, c)
+	}
+
+	return append(changes, valueDiff(normalize(before), normalize(after))...)
+}
+
+// History returns each version of the posting with the MatchedObjectID id
+// and how it differs from the version before it, oldest first.
+func (a *Archive) History(id string) ([]Amendment, error) {
+	versions, err := a.Versions(id)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]Amendment, 0, len(versions))
+	for i, v := range versions {
+		amendment := Amendment{Version: v.Number, SeenAt: v.SeenAt}
+		if i > 0 {
+			amendment.Changes = Diff(versions[i-1].Item, v.Item)
+		}
+		history = append(history, amendment)
+	}
+	return history, nil
+}
+
+// splitField splits the value of a list field into its values.
+func splitField(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, usajobs.FieldSeparator)
+}
+
+// listDiff returns the values of the after list that are not in the before
+// list, and the values of the before list that are not in the after list.
+func listDiff(o, n []string) (added, removed []string) {
+	in := func(values []string, v string) bool {
+		for _, s := range values {
+			if s == v {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, v := range n {
+		if !in(o, v) {
+			added = append(added, v)
+		}
+	}
+	for _, v := range o {
+		if !in(n, v) {
+			removed = append(removed, v)
+		}
+	}
+	return added, removed
+}
+
+// fieldValues returns the value of every search item field of an item.
+func fieldValues(item usajobs.SearchResultItem) []string {
+	values := make([]string, 0, len(usajobs.SearchItemFields))
+	for _, f := range usajobs.SearchItemFields {
+		values = append(values, f.Value(item))
+	}
+	return values
+}
+
+// valueDiff compares the json encoding of two versions value by value,
+// naming each change by its path (ex.,
+// MatchedObjectDescriptor.UserArea.Details.LowGrade). Changes to values
+// that a search item field shows are left out, since Diff reports them by
+// the field name.
+func valueDiff(before, after usajobs.SearchResultItem) []Change {
+	var o, n any
+	for _, v := range []struct {
+		item usajobs.SearchResultItem
+		dst  *any
+	}{{before, &o}, {after, &n}} {
+		data, err := json.Marshal(v.item)
+		if err != nil {
+			return nil
+		}
+		err = json.Unmarshal(data, v.dst)
+		if err != nil {
+			return nil
+		}
+	}
+
+	// a value is shown by a search item field when replacing it in the
+	// before version changes the value of a field
+	root, fields := o, fieldValues(before)
+	shown := func() bool {
+		data, err := json.Marshal(root)
+		if err != nil {
+			return false
+		}
+		var item usajobs.SearchResultItem
+		err = json.Unmarshal(data, &item)
+		if err != nil {
+			return false
+		}
+		return !reflect.DeepEqual(fieldValues(item), fields)
+	}
+
+	// set replaces a value of the before version and returns a function
+	// restoring it
+	type set func(v any) (undo func())
+
+	var changes []Change
+	var walk func(path string, o, n any, replace set)
+	walk = func(path string, o, n any, replace set) {
+		if reflect.DeepEqual(o, n) {
+			return
+		}
+
+		om, oIsMap := o.(map[string]any)
+		nm, nIsMap := n.(map[string]any)
+		if oIsMap && nIsMap {
+			keys := map[string]bool{}
+			for k := range om {
+				keys[k] = true
+			}
+			for k := range nm {
+				keys[k] = true
+			}
+			sorted := make([]string, 0, len(keys))
+			for k := range keys {
+				sorted = append(sorted, k)
+			}
+			sort.Strings(sorted)
+
+			for _, k := range sorted {
+				p := k
+				if path != "" {
+					p = path + "." + k
+				}
+				walk(p, om[k], nm[k], func(v any) func() {
+					old, ok := om[k]
+					om[k] = v
+					return func() {
+						if ok {
+							om[k] = old
+						} else {
+							delete(om, k)
+						}
+					}
+				})
+			}
+			return
+		}
+
+		ol, oIsList := o.([]any)
+		nl, nIsList := n.([]any)
+		if oIsList && nIsList && len(ol) == len(nl) {
+			for i := range ol {
+				walk(fmt.Sprintf("%s[%d]", path, i), ol[i], nl[i], func(v any) func() {
+					old := ol[i]
+					ol[i] = v
+					return func() { ol[i] = old }
+				})
+			}
+			return
+		}
+
+		undo := replace(n)
+		covered := shown()
+		undo()
+		if !covered {
+			changes = append(changes, Change{Field: path, Old: jsonValue(o), New: jsonValue(n)})
+		}
+	}
+	walk("", o, n, func(v any) func() {
+		old := root
+		root = v
+		return func() { root = old }
+	})
+	return changes
+}
+
+// jsonValue formats a decoded json value, or returns "" when it is missing.
+func jsonValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,134 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package archive_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/JeffRDay/go-usajobs/archive"
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+)
+
+func TestDiff(t *testing.T) {
+	before := loadItems(t)[1]
+
+	// copy the slices the amendment changes so before is left unchanged
+	after := before
+	after.MatchedObjectDescriptor.ApplicationCloseDate = "2024-08-30T23:59:59.9970"
+	after.MatchedObjectDescriptor.UserArea.Details.HighGrade = "13"
+	after.MatchedObjectDescriptor.PositionLocation = append([]usajobs.PositionLocation{}, before.MatchedObjectDescriptor.PositionLocation...)
+	after.MatchedObjectDescriptor.PositionLocation = append(after.MatchedObjectDescriptor.PositionLocation, usajobs.PositionLocation{
+		LocationName: "Fort Liberty, North Carolina",
+	})
+	after.RelevanceRank = 99
+
+	var fields []string
+	var locations archive.Change
+	for _, c := range archive.Diff(before, after) {
+		fields = append(fields, c.Field)
+		if c.Field == "LOCATIONS" {
+			locations = c
+		}
+	}
+
+	want := []string{"LOCATIONS", "HIGH_GRADE", "CLOSE_DATE"}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("got changes to %v, want %v", fields, want)
+	}
+	if !reflect.DeepEqual(locations.Added, []string{"Fort Liberty, North Carolina"}) || locations.Removed != nil {
+		t.Errorf("expected an added location, got %+v", locations)
+	}
+
+	// values without a search item field are compared by path
+	after = before
+	after.MatchedObjectDescriptor.PositionOfferingType = append(after.MatchedObjectDescriptor.PositionOfferingType[:0:0], after.MatchedObjectDescriptor.PositionOfferingType...)
+	after.MatchedObjectDescriptor.PositionOfferingType[0].Code = "15318"
+	got := archive.Diff(before, after)
+	wantChange := archive.Change{Field: "MatchedObjectDescriptor.PositionOfferingType[0].Code", Old: "15317", New: "15318"}
+	if len(got) != 1 || !reflect.DeepEqual(got[0], wantChange) {
+		t.Errorf("got %+v, want %+v", got, wantChange)
+	}
+
+	// a change without a search item field is reported alongside named ones
+	after.MatchedObjectDescriptor.ApplicationCloseDate = "2024-08-30T23:59:59.9970"
+	got = archive.Diff(before, after)
+	if len(got) != 2 || got[0].Field != "CLOSE_DATE" || !reflect.DeepEqual(got[1], wantChange) {
+		t.Errorf("got %+v, want CLOSE_DATE and %+v", got, wantChange)
+	}
+
+	// duties are compared as whole sentences, even when they contain the
+	// field separator
+	before.MatchedObjectDescriptor.UserArea.Details.MajorDuties = []string{"Design systems; deploy them", "Mentor staff"}
+	after = before
+	after.MatchedObjectDescriptor.UserArea.Details.MajorDuties = []string{"Design systems; operate them", "Mentor staff"}
+	got = archive.Diff(before, after)
+	if len(got) != 1 || got[0].Field != "MAJOR_DUTIES" ||
+		!reflect.DeepEqual(got[0].Added, []string{"Design systems; operate them"}) ||
+		!reflect.DeepEqual(got[0].Removed, []string{"Design systems; deploy them"}) {
+		t.Errorf("expected the changed duty to be replaced, got %+v", got)
+	}
+
+	if got := archive.Diff(before, before); got != nil {
+		t.Errorf("expected no changes, got %+v", got)
+	}
+}
+
+func TestArchiveHistory(t *testing.T) {
+	a := openArchive(t)
+	items := loadItems(t)
+	first := time.Date(2024, 7, 6, 0, 0, 0, 0, time.UTC)
+
+	_, err := a.Add(items, first)
+	if err != nil {
+		t.Fatalf("could not add postings: %v", err)
+	}
+
+	items[1].MatchedObjectDescriptor.ApplicationCloseDate = "2024-08-30T23:59:59.9970"
+	_, err = a.Add(items, first.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("could not add postings: %v", err)
+	}
+
+	history, err := a.History(items[1].MatchedObjectID)
+	if err != nil {
+		t.Fatalf("could not get history: %v", err)
+	}
+
+	want := []archive.Amendment{
+		{Version: 1, SeenAt: first},
+		{Version: 2, SeenAt: first.Add(24 * time.Hour), Changes: []archive.Change{{
+			Field: "CLOSE_DATE",
+			Old:   "2024-07-26T23:59:59.9970",
+			New:   "2024-08-30T23:59:59.9970",
+		}}},
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected 2 versions, got %+v", history)
+	}
+	for i := range want {
+		if history[i].Version != want[i].Version || !history[i].SeenAt.Equal(want[i].SeenAt) || !reflect.DeepEqual(history[i].Changes, want[i].Changes) {
+			t.Errorf("version %d: got %+v, want %+v", i+1, history[i], want[i])
+		}
+	}
+
+	_, err = a.History("missing")
+	if !errors.Is(err, archive.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
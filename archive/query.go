@@ -0,0 +1,178 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package archive
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+)
+
+// dateLayouts are the formats usajobs uses for announcement dates.
+var dateLayouts = []string{"2006-01-02T15:04:05.9999999", "2006-01-02"}
+
+// Query selects archived postings by the fields of their latest version.
+// The zero value selects every posting.
+type Query struct {
+	// Title selects postings whose title contains it, ignoring case.
+	Title string
+	// Department selects postings whose department contains it, ignoring
+	// case (ex., "army").
+	Department string
+	// Agency selects postings whose sub-agency or organization contains it,
+	// ignoring case.
+	Agency string
+	// Series selects postings with any of the occupational series codes.
+	Series []string
+	// State selects postings with a location whose state or country
+	// subdivision contains it, ignoring case.
+	State string
+	// Remote selects postings offering remote work.
+	Remote bool
+	// MinSalary selects postings whose pay range reaches it, in dollars a
+	// year.
+	MinSalary float64
+	// OpenedSince and OpenedBefore select postings whose announcement opened
+	// in the range, when they are not the zero time.
+	OpenedSince  time.Time
+	OpenedBefore time.Time
+	// SeenSince selects postings last seen at or after it, and SeenBefore
+	// postings first seen before it, when they are not the zero time.
+	SeenSince  time.Time
+	SeenBefore time.Time
+	// Limit is the maximum number of postings selected, or no limit when 0.
+	Limit int
+}
+
+// Match reports whether the query selects p, without regard to Limit.
+func (q Query) Match(p Posting) bool {
+	d := p.Item.MatchedObjectDescriptor
+	details := d.UserArea.Details
+
+	if !contains(d.PositionTitle, q.Title) || !contains(d.DepartmentName, q.Department) {
+		return false
+	}
+
+	if q.Agency != "" && !contains(details.SubAgencyName, q.Agency) && !contains(d.OrganizationName, q.Agency) {
+		return false
+	}
+
+	if len(q.Series) > 0 {
+		found := false
+		for _, c := range d.JobCategory {
+			for _, s := range q.Series {
+				if strings.EqualFold(strings.TrimSpace(c.Code), strings.TrimSpace(s)) {
+					found = true
+				}
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if q.State != "" {
+		found := false
+		for _, l := range d.PositionLocation {
+			if contains(l.CountrySubDivisionCode, q.State) {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if q.Remote && !details.RemoteIndicator {
+		return false
+	}
+
+	if q.MinSalary > 0 {
+		paid := false
+		for _, r := range d.PositionRemuneration {
+			_, high, ok := usajobs.AnnualSalary(r)
+			if ok && high >= q.MinSalary {
+				paid = true
+			}
+		}
+		if !paid {
+			return false
+		}
+	}
+
+	if !q.OpenedSince.IsZero() || !q.OpenedBefore.IsZero() {
+		opened := p.Opened()
+		if opened.IsZero() || opened.Before(q.OpenedSince) || (!q.OpenedBefore.IsZero() && !opened.Before(q.OpenedBefore)) {
+			return false
+		}
+	}
+
+	if p.LastSeen.Before(q.SeenSince) {
+		return false
+	}
+	if !q.SeenBefore.IsZero() && !p.FirstSeen.Before(q.SeenBefore) {
+		return false
+	}
+
+	return true
+}
+
+// Query returns the archived postings the query selects, most recently
+// opened first.
+func (a *Archive) Query(q Query) ([]Posting, error) {
+	var postings []Posting
+	err := a.Each(func(p Posting) error {
+		if q.Match(p) {
+			postings = append(postings, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(postings, func(i, j int) bool {
+		oi, oj := postings[i].Opened(), postings[j].Opened()
+		if !oi.Equal(oj) {
+			return oi.After(oj)
+		}
+		return postings[i].MatchedObjectID < postings[j].MatchedObjectID
+	})
+
+	if q.Limit > 0 && len(postings) > q.Limit {
+		postings = postings[:q.Limit]
+	}
+	return postings, nil
+}
+
+// Opened returns when the announcement of the posting opened, or the zero time
+// when it cannot be parsed.
+func (p Posting) Opened() time.Time {
+	s := strings.TrimSpace(p.Item.MatchedObjectDescriptor.PublicationStartDate)
+	for _, l := range dateLayouts {
+		t, err := time.Parse(l, s)
+		if err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func contains(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(strings.TrimSpace(substr)))
+}
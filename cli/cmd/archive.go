@@ -0,0 +1,713 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/JeffRDay/go-usajobs/archive"
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+	"github.com/JeffRDay/go-usajobs/fulltext"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// archiveCmd represents the archive command
+var archiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "Keep every posting your searches return in a local archive",
+	Long: `
+Keep every posting your searches return in a local archive, with when each posting was
+first and last seen and each version of it, and query or export the archive later.
+The archive is a single file, ~/.config/usajobs/archive.db unless --archive is set.
+
+Example Usage:
+
+    Archive every IT posting, then see what the Army posted last quarter:
+    usajobs archive add --job-catagory=2210
+    usajobs archive query --department=army --opened-since=2024-07-01 --opened-before=2024-10-01
+
+    Archive the results of an earlier search:
+    usajobs search --keyword=cyber --display=ndjson > cyber.ndjson
+    usajobs archive add --from=cyber.ndjson
+
+    Search the text of archived postings:
+    usajobs archive search "kubernetes AND NOT contractor"
+
+    See how a posting was amended since it was first archived:
+    usajobs history 800000001
+
+    Export the archive, with every version of each posting:
+    usajobs archive export --history -o archive.ndjson
+
+    `,
+}
+
+// archiveAddCmd represents the archive add command
+var archiveAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Archive the postings a search returns, or search results read from a file",
+	Long: `
+Archive the postings a search returns. Every search flag is supported, and every page of
+results is archived unless --max-results is set. With --from, search results are read
+from a JSON or NDJSON file written by 'usajobs search --display=json|ndjson' instead,
+or from stdin when the file is -. --near and --within keep only the postings with a
+location within the radius, from a search or a file.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := executeArchiveAdd()
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to execute archive add command")
+		}
+	},
+}
+
+// archiveQueryCmd represents the archive query command
+var archiveQueryCmd = &cobra.Command{
+	Use:   "query",
+	Short: "List archived postings, most recently opened first",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := executeArchiveQuery()
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to execute archive query command")
+		}
+	},
+}
+
+// archiveSearchCmd represents the archive search command
+var archiveSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search the text of archived postings, best match first",
+	Long: `
+Search the title, summary, duties, qualifications and requirements of archived postings,
+best match first. Words in the title count more than words elsewhere. The archive query
+flags narrow the postings searched.
+
+The index is built in memory from the postings searched each time the command runs and
+is not kept in the archive, so searching a large archive is faster when the query flags
+narrow it first.
+
+Query syntax:
+
+    kubernetes terraform        postings with both words
+    kubernetes OR openshift     postings with either word
+    kubernetes AND NOT contractor, kubernetes -contractor
+                                postings with kubernetes but not contractor
+    "site reliability"          the words next to each other
+    devsec*                     words starting with devsec
+    (go OR golang) -intern      parentheses group
+
+AND, OR and NOT are only operators in upper case, and AND binds tighter than OR.
+
+Example Usage:
+
+    usajobs archive search "kubernetes AND NOT contractor"
+    usajobs archive search '"site reliability" OR sre' --department=army --limit=10
+
+    `,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := executeArchiveSearch(args[0])
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to execute archive search command")
+		}
+	},
+}
+
+// archiveExportCmd represents the archive export command
+var archiveExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export archived postings as NDJSON, JSON, or CSV",
+	Long: `
+Export archived postings as NDJSON, one posting per line, or as a JSON array with
+--display=json or every field as CSV with --display=csv. Each posting has when it was
+first and last seen, its number of versions, and its latest version, plus every version
+with --history.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := executeArchiveExport()
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to execute archive export command")
+		}
+	},
+}
+
+var (
+	archivePath    string
+	archiveFrom    string
+	archiveOutput  string
+	archiveHistory bool
+
+	archiveTitle        string
+	archiveDepartment   string
+	archiveAgency       string
+	archiveSeries       []string
+	archiveState        string
+	archiveRemote       bool
+	archiveMinSalary    float64
+	archiveOpenedSince  string
+	archiveOpenedBefore string
+	archiveSeenSince    string
+	archiveSeenBefore   string
+	archiveLimit        int
+)
+
+// archiveTemplateItem is the value a --format template is executed with for
+// each archived posting.
+type archiveTemplateItem struct {
+	searchTemplateItem
+	FirstSeen time.Time
+	LastSeen  time.Time
+	Versions  int
+	// Score is the rank of the posting in archive search results, or 0.
+	Score float64
+}
+
+// archiveSearchHit is an archived posting matching a full-text search.
+type archiveSearchHit struct {
+	Score float64 `json:"score"`
+	archive.Posting
+}
+
+// archiveExportItem is an exported posting, with its versions when
+// --history is set.
+type archiveExportItem struct {
+	archive.Posting
+	History []archive.Version `json:"history,omitempty"`
+}
+
+func init() {
+	rootCmd.AddCommand(archiveCmd)
+	archiveCmd.AddCommand(archiveAddCmd)
+	archiveCmd.AddCommand(archiveQueryCmd)
+	archiveCmd.AddCommand(archiveSearchCmd)
+	archiveCmd.AddCommand(archiveExportCmd)
+
+	archiveCmd.PersistentFlags().StringVar(&archivePath, "archive", "", "[optional] archive file (default ~/.config/usajobs/archive.db)")
+	archiveAddCmd.Flags().AddFlagSet(searchFlags)
+	archiveAddCmd.Flags().StringVar(&archiveFrom, "from", "", "[optional] JSON or NDJSON file of search results to archive instead of searching, - reads stdin")
+	addArchiveQueryFlags(archiveQueryCmd.Flags())
+	addArchiveQueryFlags(archiveSearchCmd.Flags())
+	addArchiveQueryFlags(archiveExportCmd.Flags())
+	archiveExportCmd.Flags().StringVarP(&archiveOutput, "output", "o", "", "[optional] file to export to (default stdout)")
+	archiveExportCmd.Flags().BoolVar(&archiveHistory, "history", false, "[optional] include every version of each posting")
+
+	for _, cmd := range []*cobra.Command{archiveQueryCmd, archiveSearchCmd, archiveExportCmd} {
+		registerCompletions(cmd, map[string]completionFunc{
+			"series": completeCodeList("occupationalseries", true),
+		})
+	}
+}
+
+// addArchiveQueryFlags adds the flags that filter archived postings.
+func addArchiveQueryFlags(f *pflag.FlagSet) {
+	f.StringVar(&archiveTitle, "title", "", "[optional] postings whose title contains the text, ignoring case")
+	f.StringVar(&archiveDepartment, "department", "", "[optional] postings whose department contains the text, ignoring case (ex., army)")
+	f.StringVar(&archiveAgency, "agency", "", "[optional] postings whose sub-agency contains the text, ignoring case")
+	f.StringSliceVar(&archiveSeries, "series", []string{}, "[optional][Comma Separated List] postings with any of the occupational series codes or names (ex., 2210,0854)")
+	f.StringVar(&archiveState, "state", "", "[optional] postings with a location in the state or country subdivision (ex., Texas)")
+	f.BoolVar(&archiveRemote, "remote", false, "[optional] only postings offering remote work")
+	f.Float64Var(&archiveMinSalary, "min-salary", 0, "[optional] postings whose pay range reaches the salary in dollars a year")
+	f.StringVar(&archiveOpenedSince, "opened-since", "", "[optional][YYYY-MM-DD] postings whose announcement opened on or after the date")
+	f.StringVar(&archiveOpenedBefore, "opened-before", "", "[optional][YYYY-MM-DD] postings whose announcement opened before the date")
+	f.StringVar(&archiveSeenSince, "seen-since", "", "[optional][YYYY-MM-DD or RFC3339] postings last seen on or after the time")
+	f.StringVar(&archiveSeenBefore, "seen-before", "", "[optional][YYYY-MM-DD or RFC3339] postings first seen before the time")
+	f.IntVar(&archiveLimit, "limit", 0, "[optional] maximum number of postings")
+}
+
+func openArchive() (*archive.Archive, error) {
+	path := archivePath
+	if path == "" {
+		var err error
+		path, err = archive.DefaultPath()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return archive.Open(path)
+}
+
+// archiveQuery builds the archive query from the query flags.
+func archiveQuery() (archive.Query, error) {
+	q := archive.Query{
+		Title:      archiveTitle,
+		Department: archiveDepartment,
+		Agency:     archiveAgency,
+		State:      archiveState,
+		Remote:     archiveRemote,
+		MinSalary:  archiveMinSalary,
+		Limit:      archiveLimit,
+	}
+
+	var err error
+	if len(archiveSeries) > 0 {
+		q.Series, err = resolveCodeNames("series", "occupationalseries", archiveSeries)
+		if err != nil {
+			return q, err
+		}
+	}
+
+	for _, d := range []struct {
+		flag  string
+		value string
+		t     *time.Time
+	}{
+		{"opened-since", archiveOpenedSince, &q.OpenedSince},
+		{"opened-before", archiveOpenedBefore, &q.OpenedBefore},
+		{"seen-since", archiveSeenSince, &q.SeenSince},
+		{"seen-before", archiveSeenBefore, &q.SeenBefore},
+	} {
+		if d.value == "" {
+			continue
+		}
+
+		*d.t, err = time.ParseInLocation(time.DateOnly, d.value, time.Local)
+		if err != nil {
+			*d.t, err = time.Parse(time.RFC3339, d.value)
+		}
+		if err != nil {
+			return q, fmt.Errorf("invalid --%s %q, expected YYYY-MM-DD or RFC3339", d.flag, d.value)
+		}
+	}
+
+	if archiveLimit < 0 {
+		return q, errors.New("--limit must not be negative")
+	}
+	return q, nil
+}
+
+func executeArchiveAdd() error {
+	var items []usajobs.SearchResultItem
+	var err error
+	if archiveFrom != "" {
+		items, err = readSearchResults(archiveFrom)
+	} else {
+		items, err = archiveSearch()
+	}
+	if err != nil {
+		return err
+	}
+
+	if Near != "" {
+		items, err = archiveNear(items)
+		if err != nil {
+			return err
+		}
+	}
+
+	a, err := openArchive()
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	result, err := a.Add(items, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if display == "json" {
+		return displayJSON(result)
+	}
+	fmt.Printf("archived %d postings: %d new, %d updated, %d unchanged\n", len(items), result.New, result.Updated, result.Unchanged)
+	return nil
+}
+
+// archiveSearch returns the results of the search flags, or of each --query
+// file.
+func archiveSearch() ([]usajobs.SearchResultItem, error) {
+	opt, err := setSearchOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	err = initClient(true)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []usajobs.SearchOptions{opt}
+	if len(QueryFiles) > 0 {
+		opts, err = loadSearchQueries(opt, QueryFiles)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var items []usajobs.SearchResultItem
+	for _, o := range opts {
+		results, err := searchEveryPage(&o)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, results...)
+	}
+	return items, nil
+}
+
+// archiveNear keeps the search results with a location within --within miles
+// of --near.
+func archiveNear(items []usajobs.SearchResultItem) ([]usajobs.SearchResultItem, error) {
+	// postal codes are looked up without an api token
+	err := initClient(false)
+	if err != nil {
+		return nil, err
+	}
+
+	origin, err := Client.ResolvePoint(Near)
+	if err != nil {
+		return nil, err
+	}
+
+	var near []usajobs.SearchResultItem
+	for _, d := range usajobs.WithinRadius(items, origin, Within) {
+		near = append(near, d.Item)
+	}
+	return near, nil
+}
+
+// readSearchResults reads search results from a JSON array or NDJSON file,
+// or stdin when path is -.
+func readSearchResults(path string) ([]usajobs.SearchResultItem, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	br := bufio.NewReader(r)
+	first, err := firstNonSpace(br)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var items []usajobs.SearchResultItem
+	dec := json.NewDecoder(br)
+	if first == '[' {
+		err = dec.Decode(&items)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return items, nil
+	}
+
+	for {
+		var item usajobs.SearchResultItem
+		err := dec.Decode(&item)
+		if err == io.EOF {
+			return items, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		items = append(items, item)
+	}
+}
+
+// firstNonSpace returns the first byte of r that is not white space without
+// consuming it.
+func firstNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		if !bytes.ContainsAny(b, " \t\r\n") {
+			return b[0], nil
+		}
+		_, err = r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
+func executeArchiveQuery() error {
+	q, err := archiveQuery()
+	if err != nil {
+		return err
+	}
+
+	a, err := openArchive()
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	postings, err := a.Query(q)
+	if err != nil {
+		return err
+	}
+	return displayPostings(postings, nil)
+}
+
+func executeArchiveSearch(query string) error {
+	// the filters narrow the postings searched, so the limit applies to the
+	// ranked matches instead
+	q, err := archiveQuery()
+	if err != nil {
+		return err
+	}
+	limit := q.Limit
+	q.Limit = 0
+
+	a, err := openArchive()
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	postings, err := a.Query(q)
+	if err != nil {
+		return err
+	}
+
+	// the index is rebuilt for every search rather than kept in the archive,
+	// which keeps archive.Add simple at the cost of reading every posting
+	ix := fulltext.New()
+	byID := make(map[string]archive.Posting, len(postings))
+	for _, p := range postings {
+		ix.Add(p.Item)
+		byID[p.MatchedObjectID] = p
+	}
+
+	hits, err := ix.Search(query)
+	if err != nil {
+		return err
+	}
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	matched := make([]archive.Posting, 0, len(hits))
+	scores := make([]float64, 0, len(hits))
+	for _, h := range hits {
+		matched = append(matched, byID[h.ID])
+		scores = append(scores, h.Score)
+	}
+	return displayPostings(matched, scores)
+}
+
+// displayPostings prints archived postings, with the score of each posting
+// when they are full-text search results.
+func displayPostings(postings []archive.Posting, scores []float64) error {
+	if format != "" {
+		records := make([]archiveTemplateItem, 0, len(postings))
+		for i, p := range postings {
+			r := archiveTemplateItem{
+				searchTemplateItem: searchTemplateItem{
+					MatchedObjectDescriptor: p.Item.MatchedObjectDescriptor,
+					SearchItemDetails:       p.Item.MatchedObjectDescriptor.UserArea.Details,
+					MatchedObjectID:         p.MatchedObjectID,
+				},
+				FirstSeen: p.FirstSeen,
+				LastSeen:  p.LastSeen,
+				Versions:  p.Versions,
+			}
+			if scores != nil {
+				r.Score = scores[i]
+			}
+			records = append(records, r)
+		}
+		return displayTemplate(records)
+	}
+
+	switch display {
+	case "json", "ndjson":
+		if scores == nil {
+			if postings == nil {
+				postings = []archive.Posting{}
+			}
+			if display == "json" {
+				return displayJSON(postings)
+			}
+			return displayNDJSON(postings)
+		}
+
+		hits := make([]archiveSearchHit, 0, len(postings))
+		for i, p := range postings {
+			hits = append(hits, archiveSearchHit{Score: scores[i], Posting: p})
+		}
+		if display == "json" {
+			return displayJSON(hits)
+		}
+		return displayNDJSON(hits)
+	case "csv":
+		headers, data := archiveRows(postings, scores)
+		return displayCSV(headers, data)
+	case "detail":
+		headers, data := archiveRows(postings, scores)
+		headers, data, err := selectColumns(headers, data)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range data {
+			var fieldData [][]string
+			for i, value := range row {
+				if value == "" {
+					continue
+				}
+				fieldData = append(fieldData, []string{headers[i], wrapField(value, 0)})
+			}
+
+			err = renderTable([]string{"FIELD", "VALUE"}, fieldData)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if len(columns) > 0 {
+		headers, data := archiveRows(postings, scores)
+		return displayTable(headers, data)
+	}
+
+	headers := []string{"ID", "DEPARTMENT", "JOB_TITLE", "OPENED", "FIRST_SEEN", "LAST_SEEN", "VERSIONS"}
+	if scores != nil {
+		headers = append([]string{"SCORE"}, headers...)
+	}
+	widths := map[string]int{"DEPARTMENT": 10, "JOB_TITLE": 20}
+	var data [][]string
+	for i, p := range postings {
+		row := []string{
+			p.MatchedObjectID,
+			p.Item.MatchedObjectDescriptor.DepartmentName,
+			p.Item.MatchedObjectDescriptor.PositionTitle,
+			templateDate(time.DateOnly, p.Item.MatchedObjectDescriptor.PublicationStartDate),
+			p.FirstSeen.Local().Format(time.DateOnly),
+			p.LastSeen.Local().Format(time.DateOnly),
+			strconv.Itoa(p.Versions),
+		}
+		if scores != nil {
+			row = append([]string{strconv.FormatFloat(scores[i], 'f', 2, 64)}, row...)
+		}
+		for i, value := range row {
+			row[i] = wrapField(value, widths[headers[i]])
+		}
+		data = append(data, row)
+	}
+	return displayTable(headers, data)
+}
+
+// archiveRows returns the score of each posting when scores is not nil, when
+// it was seen and its number of versions, followed by every field of its
+// latest version.
+func archiveRows(postings []archive.Posting, scores []float64) ([]string, [][]string) {
+	headers := append([]string{"FIRST_SEEN", "LAST_SEEN", "VERSIONS"}, usajobs.SearchItemFieldNames()...)
+	if scores != nil {
+		headers = append([]string{"SCORE"}, headers...)
+	}
+
+	var data [][]string
+	for i, p := range postings {
+		var row []string
+		if scores != nil {
+			row = append(row, strconv.FormatFloat(scores[i], 'f', 3, 64))
+		}
+		row = append(row, p.FirstSeen.Format(time.RFC3339), p.LastSeen.Format(time.RFC3339), strconv.Itoa(p.Versions))
+		data = append(data, append(row, p.Item.Row(usajobs.SearchItemFields)...))
+	}
+	return headers, data
+}
+
+func executeArchiveExport() error {
+	q, err := archiveQuery()
+	if err != nil {
+		return err
+	}
+
+	a, err := openArchive()
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	postings, err := a.Query(q)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if archiveOutput != "" {
+		f, err := os.OpenFile(archiveOutput, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if display == "csv" {
+		headers, data := archiveRows(postings, nil)
+		writer := csv.NewWriter(w)
+		err = writer.WriteAll(append([][]string{headers}, data...))
+		if err != nil {
+			return err
+		}
+		log.Info().Msgf("exported %d postings", len(postings))
+		return nil
+	}
+
+	exported := make([]archiveExportItem, 0, len(postings))
+	for _, p := range postings {
+		e := archiveExportItem{Posting: p}
+		if archiveHistory {
+			e.History, err = a.Versions(p.MatchedObjectID)
+			if err != nil {
+				return err
+			}
+		}
+		exported = append(exported, e)
+	}
+
+	enc := json.NewEncoder(w)
+	if display == "json" {
+		enc.SetIndent("", "  ")
+		err = enc.Encode(exported)
+	} else {
+		for _, e := range exported {
+			err = enc.Encode(e)
+			if err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Info().Msgf("exported %d postings", len(postings))
+	return nil
+}
@@ -0,0 +1,190 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JeffRDay/go-usajobs/archive"
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+	"github.com/JeffRDay/go-usajobs/fulltext"
+)
+
+func TestArchive(t *testing.T) {
+	data, err := os.ReadFile(searchTestDataPath)
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	var full usajobs.SearchResponse
+	err = json.Unmarshal(data, &full)
+	if err != nil {
+		t.Fatalf("could not decode test data: %v", err)
+	}
+	items := full.SearchResult.SearchResultItems
+
+	dir := t.TempDir()
+	archivePath = filepath.Join(dir, "archive.db")
+	defer func() {
+		display = "summary"
+		archivePath, archiveFrom, archiveOutput, archiveHistory = "", "", "", false
+		archiveDepartment, archiveSeries, archiveOpenedSince = "", []string{}, ""
+		Near, Within = "", 50
+	}()
+
+	// the first import is a json array, the second ndjson with one posting
+	// changed
+	array, err := json.Marshal(items)
+	if err != nil {
+		t.Fatalf("failed to encode items: %v", err)
+	}
+	first := filepath.Join(dir, "first.json")
+	err = os.WriteFile(first, array, 0o600)
+	if err != nil {
+		t.Fatalf("failed to write items: %v", err)
+	}
+
+	changed := append([]usajobs.SearchResultItem{}, items...)
+	changed[0].MatchedObjectDescriptor.ApplicationCloseDate = "2024-09-30"
+	var ndjson strings.Builder
+	for _, item := range changed {
+		json.NewEncoder(&ndjson).Encode(item)
+	}
+	second := filepath.Join(dir, "second.ndjson")
+	err = os.WriteFile(second, []byte(ndjson.String()), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write items: %v", err)
+	}
+
+	add := func(path string) archive.AddResult {
+		t.Helper()
+
+		archiveFrom, display = path, "json"
+		out, err := captureStdout(t, executeArchiveAdd)
+		if err != nil {
+			t.Fatalf("failed to execute: %v", err)
+		}
+
+		var result archive.AddResult
+		err = json.Unmarshal([]byte(out), &result)
+		if err != nil {
+			t.Fatalf("could not decode %q: %v", out, err)
+		}
+		return result
+	}
+
+	if got, want := add(first), (archive.AddResult{New: 3}); got != want {
+		t.Errorf("first add: got %+v, want %+v", got, want)
+	}
+	if got, want := add(second), (archive.AddResult{Updated: 1, Unchanged: 2}); got != want {
+		t.Errorf("second add: got %+v, want %+v", got, want)
+	}
+
+	// only the austin posting is within 50 miles, and it is unchanged
+	Near, Within = "30.26715,-97.74306", 50
+	got := add(first)
+	Near = ""
+	if want := (archive.AddResult{Unchanged: 1}); got != want {
+		t.Errorf("near add: got %+v, want %+v", got, want)
+	}
+
+	display = "json"
+	archiveDepartment = "homeland"
+	out, err := captureStdout(t, executeArchiveQuery)
+	if err != nil {
+		t.Fatalf("failed to execute: %v", err)
+	}
+
+	var postings []archive.Posting
+	err = json.Unmarshal([]byte(out), &postings)
+	if err != nil {
+		t.Fatalf("could not decode query: %v", err)
+	}
+	if len(postings) != 1 || postings[0].MatchedObjectID != "800000001" || postings[0].Versions != 2 {
+		t.Fatalf("unexpected query results: %+v", postings)
+	}
+
+	display = "csv"
+	archiveDepartment, archiveOpenedSince = "", "2024-07-02"
+	out, err = captureStdout(t, executeArchiveQuery)
+	if err != nil {
+		t.Fatalf("failed to execute: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "FIRST_SEEN,LAST_SEEN,VERSIONS,") {
+		t.Fatalf("unexpected csv:\n%s", out)
+	}
+	if !strings.Contains(lines[1], "800000001") || !strings.Contains(lines[2], "800000003") {
+		t.Errorf("expected postings opened since 2024-07-02, most recent first:\n%s", out)
+	}
+
+	display = "ndjson"
+	archiveOpenedSince = ""
+	archiveOutput = filepath.Join(dir, "export.ndjson")
+	archiveHistory = true
+	_, err = captureStdout(t, executeArchiveExport)
+	if err != nil {
+		t.Fatalf("failed to execute: %v", err)
+	}
+
+	exported, err := os.ReadFile(archiveOutput)
+	if err != nil {
+		t.Fatalf("could not read export: %v", err)
+	}
+	lines = strings.Split(strings.TrimSpace(string(exported)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 exported postings, got %d", len(lines))
+	}
+
+	var e archiveExportItem
+	err = json.Unmarshal([]byte(lines[0]), &e)
+	if err != nil {
+		t.Fatalf("could not decode export: %v", err)
+	}
+	if e.MatchedObjectID != "800000001" || len(e.History) != 2 {
+		t.Fatalf("expected both versions of 800000001, got %s with %d versions", e.MatchedObjectID, len(e.History))
+	}
+	if e.History[1].Item.MatchedObjectDescriptor.ApplicationCloseDate != "2024-09-30" {
+		t.Errorf("expected the latest version last, got %+v", e.History[1])
+	}
+
+	display = "json"
+	archiveOutput, archiveHistory, archiveLimit = "", false, 1
+	defer func() { archiveLimit = 0 }()
+	out, err = captureStdout(t, func() error { return executeArchiveSearch("engineer OR nurse") })
+	if err != nil {
+		t.Fatalf("failed to execute: %v", err)
+	}
+
+	var hits []archiveSearchHit
+	err = json.Unmarshal([]byte(out), &hits)
+	if err != nil {
+		t.Fatalf("could not decode search: %v", err)
+	}
+	if len(hits) != 1 || hits[0].MatchedObjectID != "800000003" || hits[0].Score <= 0 {
+		t.Errorf("expected the best of two matches, got %+v", hits)
+	}
+
+	_, err = captureStdout(t, func() error { return executeArchiveSearch("engineer AND") })
+	if !errors.Is(err, fulltext.ErrInvalidQuery) {
+		t.Errorf("expected ErrInvalidQuery, got %v", err)
+	}
+}
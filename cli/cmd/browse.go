@@ -0,0 +1,719 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// browseCmd represents the browse command
+var browseCmd = &cobra.Command{
+	Use:   "browse",
+	Short: "Browse search results in an interactive terminal UI",
+	Long: `
+Browse search results in an interactive terminal UI. Enter filters, page through the
+results, open the full announcement of a posting, narrow the results by the facets
+usajobs returns (agency, grade, salary, and more), and mark postings to export.
+
+Marked postings are printed in the --display or --format output when browse exits,
+so they can be redirected to a file:
+
+    usajobs browse --keyword=cyber --display=csv > marked.csv
+
+Keys:
+
+    filters   tab/shift+tab move between fields, enter searches, esc returns to results
+    results   up/down select, left/right change page, enter opens, space marks,
+              f facets, / filters, q quits
+    detail    up/down/pgup/pgdn scroll, space marks, esc returns to results
+    facets    up/down select, space toggles, enter applies, esc returns to results
+
+    `,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := executeBrowse()
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to execute browse command")
+		}
+	},
+}
+
+var (
+	browseKeyword  string
+	browseLocation string
+	browseCategory []string
+	browseSalary   string
+	browsePageSize int
+)
+
+func init() {
+	rootCmd.AddCommand(browseCmd)
+	browseCmd.Flags().StringVarP(&browseKeyword, "keyword", "k", "", "[optional] initial keyword filter")
+	browseCmd.Flags().StringVar(&browseLocation, "location", "", "[optional] initial location filter, separate locations with ; (ex., Austin, Texas; Portland, Oregon)")
+	browseCmd.Flags().StringSliceVarP(&browseCategory, "job-catagory", "j", []string{}, "[optional] initial comma separated list of job codes (ex., 2210,0854)")
+	browseCmd.Flags().StringVar(&browseSalary, "min-salary", "", "[optional] initial minimum salary filter (ex., 80000)")
+	browseCmd.Flags().IntVar(&browsePageSize, "page-size", 25, "[optional][1-500] number of results on each page")
+	registerCompletions(browseCmd, map[string]completionFunc{
+		"job-catagory": completeCodeList("occupationalseries", true),
+	})
+}
+
+func executeBrowse() error {
+	if browsePageSize < 1 || browsePageSize > 500 {
+		return errors.New("--page-size must be between 1 and 500")
+	}
+
+	err := initClient(true)
+	if err != nil {
+		return err
+	}
+
+	// the ui is drawn on stderr when stdout is redirected so the marked
+	// postings can be written to a file
+	output := os.Stdout
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		output = os.Stderr
+	}
+
+	m := newBrowseModel()
+	result, err := tea.NewProgram(m, tea.WithAltScreen(), tea.WithOutput(output)).Run()
+	if err != nil {
+		return err
+	}
+
+	marked := result.(browseModel).markedItems()
+	if len(marked) == 0 {
+		return nil
+	}
+	return displaySearch(marked, nil)
+}
+
+// browseMode is the pane of the browse ui that has focus.
+type browseMode int
+
+const (
+	browseFilters browseMode = iota
+	browseResults
+	browseDetail
+	browseFacets
+)
+
+// browse filter fields, in the order they are shown
+const (
+	browseFieldKeyword = iota
+	browseFieldLocation
+	browseFieldCategory
+	browseFieldSalary
+)
+
+var (
+	browseTitleStyle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("99"))
+	browseSelectedStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
+	browseFaintStyle    = lipgloss.NewStyle().Faint(true)
+	browseErrorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+)
+
+// browseFacet is a refinement returned by usajobs that can narrow the search.
+type browseFacet struct {
+	Group      string
+	Refinement usajobs.Refinement
+	Selected   bool
+}
+
+// browseResultMsg is the result of a search started by the browse ui.
+type browseResultMsg struct {
+	response *usajobs.SearchResponse
+	err      error
+}
+
+// browseSeriesMsg holds the occupational series names used to describe the
+// job category filter.
+type browseSeriesMsg map[string]string
+
+type browseModel struct {
+	mode   browseMode
+	inputs []textinput.Model
+	focus  int
+
+	page     int
+	response *usajobs.SearchResponse
+	cursor   int
+	offset   int
+	loading  bool
+	err      error
+
+	facets      []browseFacet
+	facetCursor int
+	facetOffset int
+
+	// open is the posting shown in the detail pane, and pending a search
+	// result that arrived while it was open and is applied when it closes, so
+	// the results never change under the posting being read
+	detail  viewport.Model
+	open    usajobs.SearchResultItem
+	pending *browseResultMsg
+	series  map[string]string
+
+	marked map[string]usajobs.SearchResultItem
+	order  []string
+
+	width  int
+	height int
+}
+
+func newBrowseModel() browseModel {
+	m := browseModel{
+		page:   1,
+		marked: map[string]usajobs.SearchResultItem{},
+		width:  defaultTerminalWidth,
+		height: 24,
+	}
+
+	for i, f := range []struct{ label, value, placeholder string }{
+		{"Keyword", browseKeyword, "ex., cyber security"},
+		{"Location", browseLocation, "ex., Austin, Texas; Portland, Oregon"},
+		{"Job category", strings.Join(browseCategory, ","), "ex., 2210,0854"},
+		{"Min salary", browseSalary, "ex., 80000"},
+	} {
+		input := textinput.New()
+		input.Prompt = fmt.Sprintf("%-14s", f.label)
+		input.Placeholder = f.placeholder
+		input.SetValue(f.value)
+		if i == 0 {
+			input.Focus()
+		}
+		m.inputs = append(m.inputs, input)
+	}
+
+	// start with results when the search was described by flags
+	if browseKeyword != "" || browseLocation != "" || len(browseCategory) > 0 || browseSalary != "" {
+		m.mode = browseResults
+		m.loading = true
+	}
+
+	return m
+}
+
+func (m browseModel) Init() tea.Cmd {
+	cmds := []tea.Cmd{textinput.Blink, browseLoadSeries}
+	if m.loading {
+		cmds = append(cmds, m.search())
+	}
+	return tea.Batch(cmds...)
+}
+
+// searchOptions returns the search described by the filters, the selected
+// facets, and the current page.
+func (m browseModel) searchOptions() (usajobs.SearchOptions, error) {
+	opt := usajobs.SearchOptions{
+		Keyword:                   strings.TrimSpace(m.inputs[browseFieldKeyword].Value()),
+		RemunerationMinimumAmount: strings.TrimSpace(m.inputs[browseFieldSalary].Value()),
+		Fields:                    usajobs.SearchFieldsFull,
+		Page:                      m.page,
+		ResultsPerPage:            browsePageSize,
+	}
+
+	for _, l := range strings.Split(m.inputs[browseFieldLocation].Value(), ";") {
+		if l = strings.TrimSpace(l); l != "" {
+			opt.LocationName = append(opt.LocationName, l)
+		}
+	}
+	opt.JobCategoryCode = splitList(m.inputs[browseFieldCategory].Value())
+
+	for _, f := range m.facets {
+		if !f.Selected {
+			continue
+		}
+
+		token := f.Refinement.RefinementToken
+		switch f.Group {
+		case "Organization":
+			opt.Organization = append(opt.Organization, token)
+		case "GradeBucket":
+			opt.GradeBucket = append(opt.GradeBucket, usajobs.GradeBucket(token))
+		case "SalaryBucket":
+			opt.SalaryBucket = append(opt.SalaryBucket, usajobs.SalaryBucket(token))
+		case "PositionOfferingTypeCode":
+			opt.PositionOfferingTypeCode = append(opt.PositionOfferingTypeCode, token)
+		case "PositionScheduleTypeCode":
+			code, err := strconv.Atoi(token)
+			if err != nil {
+				return opt, fmt.Errorf("invalid schedule refinement %q", token)
+			}
+			opt.PositionScheduleTypeCode = append(opt.PositionScheduleTypeCode, code)
+		case "JobCategoryCode":
+			opt.JobCategoryCode = append(opt.JobCategoryCode, token)
+		}
+	}
+
+	return opt, nil
+}
+
+// search returns a command that runs the current search.
+func (m browseModel) search() tea.Cmd {
+	opt, err := m.searchOptions()
+	return func() tea.Msg {
+		if err != nil {
+			return browseResultMsg{err: err}
+		}
+
+		r, data, err := Client.Search.WithOptions(&opt)
+		if err != nil {
+			return browseResultMsg{err: err}
+		}
+
+		if r.StatusCode != http.StatusOK {
+			return browseResultMsg{err: errors.New("bad response from usajobs: " + r.Status)}
+		}
+
+		return browseResultMsg{response: &data}
+	}
+}
+
+// browseLoadSeries fetches the occupational series codelist to name the job
+// categories entered as filters. Failing to load it only loses the names.
+func browseLoadSeries() tea.Msg {
+	r, data, err := Client.CodeList("occupationalseries")
+	if err != nil || r.StatusCode != http.StatusOK {
+		return browseSeriesMsg(nil)
+	}
+
+	names := browseSeriesMsg{}
+	for _, v := range data.Values() {
+		names[v.Code] = v.Value
+	}
+	return names
+}
+
+func (m browseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.detail.Width, m.detail.Height = msg.Width, m.bodyHeight()
+		return m, nil
+
+	case browseSeriesMsg:
+		m.series = msg
+		return m, nil
+
+	case browseResultMsg:
+		if m.mode == browseDetail {
+			m.pending = &msg
+			return m, nil
+		}
+		m.applyResult(msg)
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+
+		switch m.mode {
+		case browseFilters:
+			return m.updateFilters(msg)
+		case browseResults:
+			return m.updateResults(msg)
+		case browseDetail:
+			return m.updateDetail(msg)
+		case browseFacets:
+			return m.updateFacets(msg)
+		}
+	}
+
+	if m.mode == browseFilters {
+		var cmd tea.Cmd
+		m.inputs[m.focus], cmd = m.inputs[m.focus].Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// applyResult shows the results of a finished search.
+func (m *browseModel) applyResult(msg browseResultMsg) {
+	m.loading = false
+	m.err = msg.err
+	if msg.err != nil {
+		return
+	}
+
+	m.response = msg.response
+	m.cursor, m.offset = 0, 0
+	m.mergeFacets()
+}
+
+func (m browseModel) updateFilters(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "tab", "down":
+		return m, m.focusInput((m.focus + 1) % len(m.inputs))
+	case "shift+tab", "up":
+		return m, m.focusInput((m.focus + len(m.inputs) - 1) % len(m.inputs))
+	case "enter":
+		m.mode = browseResults
+		m.page = 1
+		m.loading = true
+		return m, m.search()
+	case "esc":
+		if m.response != nil {
+			m.mode = browseResults
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.inputs[m.focus], cmd = m.inputs[m.focus].Update(msg)
+	return m, cmd
+}
+
+// focusInput moves the focus to the filter field i.
+func (m *browseModel) focusInput(i int) tea.Cmd {
+	m.inputs[m.focus].Blur()
+	m.focus = i
+	return m.inputs[m.focus].Focus()
+}
+
+func (m browseModel) updateResults(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	items := m.items()
+
+	switch msg.String() {
+	case "q", "esc":
+		return m, tea.Quit
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(items)-1 {
+			m.cursor++
+		}
+	case "right", "l", "n":
+		if !m.loading && m.response != nil && m.page < m.response.PageCount() {
+			m.page++
+			m.loading = true
+			return m, m.search()
+		}
+	case "left", "h", "p":
+		if !m.loading && m.page > 1 {
+			m.page--
+			m.loading = true
+			return m, m.search()
+		}
+	case " ", "x":
+		if len(items) > 0 {
+			m.toggleMark(items[m.cursor])
+		}
+	case "enter":
+		if len(items) > 0 {
+			m.mode = browseDetail
+			m.open = items[m.cursor]
+			m.detail = viewport.New(m.width, m.bodyHeight())
+			m.detail.SetContent(renderPosting(m.open, min(m.width, showMaxWidth)))
+		}
+	case "f":
+		if len(m.facets) > 0 {
+			m.mode = browseFacets
+		}
+	case "/":
+		m.mode = browseFilters
+		return m, m.inputs[m.focus].Focus()
+	}
+
+	m.offset = scrollOffset(m.cursor, m.offset, m.bodyHeight())
+	return m, nil
+}
+
+func (m browseModel) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q", "backspace":
+		m.mode = browseResults
+		if m.pending != nil {
+			m.applyResult(*m.pending)
+			m.pending = nil
+		}
+		return m, nil
+	case " ", "x":
+		m.toggleMark(m.open)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.detail, cmd = m.detail.Update(msg)
+	return m, cmd
+}
+
+func (m browseModel) updateFacets(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.mode = browseResults
+	case "up", "k":
+		if m.facetCursor > 0 {
+			m.facetCursor--
+		}
+	case "down", "j":
+		if m.facetCursor < len(m.facets)-1 {
+			m.facetCursor++
+		}
+	case " ", "x":
+		m.facets[m.facetCursor].Selected = !m.facets[m.facetCursor].Selected
+	case "enter":
+		m.mode = browseResults
+		m.page = 1
+		m.loading = true
+		return m, m.search()
+	}
+
+	m.facetOffset = scrollOffset(m.facetCursor, m.facetOffset, m.bodyHeight())
+	return m, nil
+}
+
+// mergeFacets replaces the facets with the refiners of the latest response,
+// keeping the facets that are selected even if usajobs no longer returns
+// them.
+func (m *browseModel) mergeFacets() {
+	var facets []browseFacet
+	selected := map[string]bool{}
+	for _, f := range m.facets {
+		if f.Selected {
+			facets = append(facets, f)
+			selected[f.Group+"\x00"+f.Refinement.RefinementToken] = true
+		}
+	}
+
+	refiners := m.response.SearchResult.UserArea.Refiners
+	for _, group := range []struct {
+		name        string
+		refinements []usajobs.Refinement
+	}{
+		{"Organization", refiners.Organization},
+		{"GradeBucket", refiners.GradeBucket},
+		{"SalaryBucket", refiners.SalaryBucket},
+		{"PositionOfferingTypeCode", refiners.PositionOfferingTypeCode},
+		{"PositionScheduleTypeCode", refiners.PositionScheduleTypeCode},
+		{"JobCategoryCode", refiners.JobCategoryCode},
+	} {
+		for _, r := range group.refinements {
+			if !selected[group.name+"\x00"+r.RefinementToken] {
+				facets = append(facets, browseFacet{Group: group.name, Refinement: r})
+			}
+		}
+	}
+
+	m.facets = facets
+	m.facetCursor, m.facetOffset = 0, 0
+}
+
+func (m *browseModel) toggleMark(item usajobs.SearchResultItem) {
+	id := item.MatchedObjectID
+	if _, ok := m.marked[id]; ok {
+		delete(m.marked, id)
+		for i, o := range m.order {
+			if o == id {
+				m.order = append(m.order[:i], m.order[i+1:]...)
+				break
+			}
+		}
+		return
+	}
+
+	m.marked[id] = item
+	m.order = append(m.order, id)
+}
+
+// markedItems returns the marked postings in the order they were marked.
+func (m browseModel) markedItems() []usajobs.SearchResultItem {
+	items := make([]usajobs.SearchResultItem, 0, len(m.order))
+	for _, id := range m.order {
+		items = append(items, m.marked[id])
+	}
+	return items
+}
+
+func (m browseModel) items() []usajobs.SearchResultItem {
+	if m.response == nil {
+		return nil
+	}
+	return m.response.SearchResult.SearchResultItems
+}
+
+// bodyHeight is the number of lines between the header and the help line.
+func (m browseModel) bodyHeight() int {
+	return max(m.height-3, 1)
+}
+
+// scrollOffset returns the first visible line of a list of height lines so
+// the cursor stays visible.
+func scrollOffset(cursor, offset, height int) int {
+	if cursor < offset {
+		return cursor
+	}
+	if cursor >= offset+height {
+		return cursor - height + 1
+	}
+	return offset
+}
+
+func (m browseModel) View() string {
+	var body, help string
+	switch m.mode {
+	case browseFilters:
+		body = m.viewFilters()
+		help = "tab next field • enter search • esc results • ctrl+c quit"
+	case browseResults:
+		body = m.viewResults()
+		help = "↑/↓ select • ←/→ page • enter open • space mark • f facets • / filters • q quit"
+	case browseDetail:
+		body = m.detail.View()
+		help = fmt.Sprintf("↑/↓ scroll • space mark • esc back • %3.f%%", m.detail.ScrollPercent()*100)
+	case browseFacets:
+		body = m.viewFacets()
+		help = "↑/↓ select • space toggle • enter apply • esc back"
+	}
+
+	header := browseTitleStyle.Render("usajobs browse")
+	if m.response != nil {
+		header += fmt.Sprintf("  page %d of %d / %d total", m.page, max(m.response.PageCount(), m.page), m.response.SearchResult.SearchResultCountAll)
+	}
+	if len(m.order) > 0 {
+		header += fmt.Sprintf("  • %d marked", len(m.order))
+	}
+	if m.loading {
+		header += "  • searching…"
+	}
+
+	lines := strings.Split(body, "\n")
+	if len(lines) > m.bodyHeight() {
+		lines = lines[:m.bodyHeight()]
+	}
+	for len(lines) < m.bodyHeight() {
+		lines = append(lines, "")
+	}
+
+	footer := browseFaintStyle.Render(help)
+	if m.err != nil {
+		footer = browseErrorStyle.Render(runewidth.Truncate(m.err.Error(), m.width, "…"))
+	}
+
+	return header + "\n\n" + strings.Join(lines, "\n") + "\n" + footer
+}
+
+func (m browseModel) viewFilters() string {
+	var b strings.Builder
+	for i, input := range m.inputs {
+		b.WriteString(input.View())
+		b.WriteByte('\n')
+
+		// name the occupational series entered as job categories
+		if i == browseFieldCategory && m.series != nil {
+			var names []string
+			for _, code := range splitList(input.Value()) {
+				if name, ok := m.series[code]; ok {
+					names = append(names, code+" "+name)
+				} else {
+					names = append(names, code+" unknown series")
+				}
+			}
+			if len(names) > 0 {
+				b.WriteString(browseFaintStyle.Render(strings.Repeat(" ", 14) + strings.Join(names, ", ")))
+				b.WriteByte('\n')
+			}
+		}
+	}
+	return b.String()
+}
+
+func (m browseModel) viewResults() string {
+	items := m.items()
+	if m.response == nil && !m.loading {
+		return "press / to enter filters and search"
+	}
+	if len(items) == 0 && !m.loading {
+		return "no postings match the search, press / to change the filters"
+	}
+
+	var lines []string
+	end := min(len(items), m.offset+m.bodyHeight())
+	for i := m.offset; i < end; i++ {
+		item := items[i]
+		d := item.MatchedObjectDescriptor
+
+		mark := "[ ]"
+		if _, ok := m.marked[item.MatchedObjectID]; ok {
+			mark = "[x]"
+		}
+
+		line := fmt.Sprintf("%s %s — %s · %s · closes %s", mark, d.PositionTitle, d.OrganizationName,
+			d.PositionLocationDisplay, templateDate("Jan 2", d.ApplicationCloseDate))
+		line = runewidth.Truncate(line, m.width-2, "…")
+
+		if i == m.cursor {
+			line = browseSelectedStyle.Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m browseModel) viewFacets() string {
+	var lines []string
+	end := min(len(m.facets), m.facetOffset+m.bodyHeight())
+	for i := m.facetOffset; i < end; i++ {
+		f := m.facets[i]
+
+		mark := "[ ]"
+		if f.Selected {
+			mark = "[x]"
+		}
+
+		line := fmt.Sprintf("%s %-24s %s (%s)", mark, f.Group, f.Refinement.RefinementName, f.Refinement.RefinementCount)
+		line = runewidth.Truncate(line, m.width-2, "…")
+
+		if i == m.facetCursor {
+			line = browseSelectedStyle.Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// splitList splits a comma separated list, dropping empty values.
+func splitList(s string) []string {
+	var values []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
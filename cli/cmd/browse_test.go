@@ -0,0 +1,236 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// browseKey sends a key press to the model and runs the command it returns,
+// feeding the resulting search message back in like the bubbletea runtime.
+func browseKey(t *testing.T, m browseModel, key string) browseModel {
+	t.Helper()
+
+	var msg tea.KeyMsg
+	switch key {
+	case "enter":
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		msg = tea.KeyMsg{Type: tea.KeyEsc}
+	case "tab":
+		msg = tea.KeyMsg{Type: tea.KeyTab}
+	case "down":
+		msg = tea.KeyMsg{Type: tea.KeyDown}
+	case "right":
+		msg = tea.KeyMsg{Type: tea.KeyRight}
+	case " ":
+		msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	default:
+		msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	}
+
+	model, cmd := m.Update(msg)
+	m = model.(browseModel)
+
+	// only searches are run, other commands like the cursor blink wait
+	if m.loading && cmd != nil {
+		model, _ = m.Update(cmd())
+		m = model.(browseModel)
+	}
+	return m
+}
+
+func TestBrowse(t *testing.T) {
+	data, err := os.ReadFile(searchTestDataPath)
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	series, err := os.ReadFile("../../testdata/occupationalseries-testdata.json")
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	var queries []url.Values
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/codelist/occupationalseries" {
+			w.Write(series)
+			return
+		}
+
+		queries = append(queries, r.URL.Query())
+		w.Write(data)
+	}))
+	defer mockServer.Close()
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+
+	Client, err = usajobs.NewClient("test", "test")
+	if err != nil {
+		t.Fatalf("could not create new usajobs client: %v", err)
+	}
+	Client.BaseURL = u
+
+	m := newBrowseModel()
+	model, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	m = model.(browseModel)
+
+	model, _ = m.Update(browseLoadSeries())
+	m = model.(browseModel)
+
+	// enter a keyword and a job category, then search
+	for _, r := range "cyber" {
+		m = browseKey(t, m, string(r))
+	}
+	m = browseKey(t, m, "tab")
+	m = browseKey(t, m, "tab")
+	for _, r := range "2210" {
+		m = browseKey(t, m, string(r))
+	}
+
+	if !strings.Contains(m.View(), "2210 Information Technology Management") {
+		t.Errorf("expected the job category to be named:\n%s", m.View())
+	}
+
+	m = browseKey(t, m, "enter")
+	if m.mode != browseResults || len(m.items()) != 3 {
+		t.Fatalf("expected 3 results, got mode %d with %d", m.mode, len(m.items()))
+	}
+	if q := queries[len(queries)-1]; q.Get("Keyword") != "cyber" || q.Get("JobCategoryCode") != "2210" || q.Get("Fields") != "Full" {
+		t.Errorf("unexpected search query: %v", q)
+	}
+	if !strings.Contains(m.View(), "page 1 of 1 / 3 total") || !strings.Contains(m.View(), "> [ ] IT Specialist (INFOSEC)") {
+		t.Errorf("unexpected results view:\n%s", m.View())
+	}
+
+	// mark the second posting and open the detail of the first
+	m = browseKey(t, m, "down")
+	m = browseKey(t, m, " ")
+	m = browseKey(t, m, "k")
+	m = browseKey(t, m, "enter")
+	if m.mode != browseDetail || !strings.Contains(m.View(), "SUMMARY") {
+		t.Errorf("expected the posting detail:\n%s", m.View())
+	}
+	m = browseKey(t, m, " ")
+	m = browseKey(t, m, "esc")
+
+	marked := m.markedItems()
+	if len(marked) != 2 || marked[0].MatchedObjectID != m.items()[1].MatchedObjectID || marked[1].MatchedObjectID != "800000001" {
+		t.Errorf("expected the postings in the order they were marked, got %d", len(marked))
+	}
+	if !strings.Contains(m.View(), "2 marked") {
+		t.Errorf("expected the marked count in the header:\n%s", m.View())
+	}
+
+	// narrow the search by the first organization facet
+	m = browseKey(t, m, "f")
+	if m.mode != browseFacets || m.facets[0].Refinement.RefinementToken != "HSCE" {
+		t.Fatalf("expected the organization facets, got mode %d", m.mode)
+	}
+	m = browseKey(t, m, " ")
+	m = browseKey(t, m, "enter")
+	if q := queries[len(queries)-1]; q.Get("Organization") != "HSCE" || q.Get("Page") != "1" {
+		t.Errorf("expected the search to be narrowed by the facet, got %v", q)
+	}
+	if !m.facets[0].Selected || m.facets[0].Refinement.RefinementToken != "HSCE" {
+		t.Error("expected the selected facet to be kept after searching")
+	}
+
+	// there is only one page, so paging does not search again
+	n := len(queries)
+	m = browseKey(t, m, "right")
+	if len(queries) != n || m.page != 1 {
+		t.Errorf("expected to stay on the only page, got page %d", m.page)
+	}
+
+	model, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if cmd == nil {
+		t.Fatal("expected q to quit")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("expected q to quit")
+	}
+	if len(model.(browseModel).markedItems()) != 2 {
+		t.Error("expected the marked postings to be kept when quitting")
+	}
+}
+
+func TestBrowseDetailDefersResults(t *testing.T) {
+	data, err := os.ReadFile(searchTestDataPath)
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	var response usajobs.SearchResponse
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		t.Fatalf("could not decode test data: %v", err)
+	}
+
+	m := newBrowseModel()
+	m.mode = browseResults
+	model, _ := m.Update(browseResultMsg{response: &response})
+	m = model.(browseModel)
+
+	m = browseKey(t, m, "down")
+	m = browseKey(t, m, "enter")
+	open := m.items()[1].MatchedObjectID
+
+	// a search finishing while the detail is open, here with no results,
+	// waits until the detail is closed
+	model, _ = m.Update(browseResultMsg{response: &usajobs.SearchResponse{}})
+	m = model.(browseModel)
+	if len(m.items()) != 3 || m.cursor != 1 {
+		t.Fatalf("expected the results to be kept while the detail is open, got %d", len(m.items()))
+	}
+
+	m = browseKey(t, m, " ")
+	m = browseKey(t, m, "esc")
+	if len(m.items()) != 0 || m.pending != nil {
+		t.Errorf("expected the deferred results after closing the detail, got %d", len(m.items()))
+	}
+
+	marked := m.markedItems()
+	if len(marked) != 1 || marked[0].MatchedObjectID != open {
+		t.Errorf("expected the open posting to be marked, got %d", len(marked))
+	}
+}
+
+func TestScrollOffset(t *testing.T) {
+	for _, tt := range []struct{ cursor, offset, height, want int }{
+		{0, 0, 10, 0},
+		{9, 0, 10, 0},
+		{10, 0, 10, 1},
+		{3, 5, 10, 3},
+	} {
+		if got := scrollOffset(tt.cursor, tt.offset, tt.height); got != tt.want {
+			t.Errorf("scrollOffset(%d, %d, %d) = %d, want %d", tt.cursor, tt.offset, tt.height, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,330 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// compareCmd represents the compare command
+var compareCmd = &cobra.Command{
+	Use:   "compare <id> <id> [id...]",
+	Short: "Compare postings side by side",
+	Long: `
+Compare postings side by side: salary, grades, locations, who may apply, hiring paths,
+close date, travel, relocation, telework, remote work and key requirements, one posting
+per column. Rows whose values differ between the postings are marked with * and
+highlighted in a terminal, or have a DIFFERS column of true with --display=csv. Postings are looked up by the usajobs control number
+(MatchedObjectID) or position id shown by search.
+
+--display=detail adds schedules, appointment types, openings, and other facts, and
+--diff only shows the rows that differ.
+
+Example Usage:
+
+    usajobs compare 800000001 800000002
+    usajobs compare ICE-24-12345-MP ASF-24-0001 --diff --display=csv
+
+    `,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := executeCompare(args)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to execute compare command")
+		}
+	},
+}
+
+var compareDiff bool
+
+// compareRow is a fact compared between postings.
+type compareRow struct {
+	Name string
+	// Detail rows are only compared with --display=detail.
+	Detail bool
+	Value  func(usajobs.SearchResultItem) string
+}
+
+// compareRows are the facts compared, in order.
+var compareRows = []compareRow{
+	{"JOB_TITLE", false, func(i usajobs.SearchResultItem) string { return i.MatchedObjectDescriptor.PositionTitle }},
+	{"AGENCY", false, func(i usajobs.SearchResultItem) string {
+		return joinNonEmpty("\n", i.MatchedObjectDescriptor.UserArea.Details.SubAgencyName, i.MatchedObjectDescriptor.DepartmentName)
+	}},
+	{"SALARY", false, func(i usajobs.SearchResultItem) string {
+		salary, _ := templateSalary(i.MatchedObjectDescriptor.PositionRemuneration)
+		return strings.ReplaceAll(salary, usajobs.FieldSeparator, "\n")
+	}},
+	{"GRADES", false, func(i usajobs.SearchResultItem) string { return showGrades(i.MatchedObjectDescriptor) }},
+	{"LOCATIONS", false, func(i usajobs.SearchResultItem) string {
+		var locations []string
+		for _, l := range i.MatchedObjectDescriptor.PositionLocation {
+			locations = append(locations, l.LocationName)
+		}
+		if len(locations) == 0 {
+			return i.MatchedObjectDescriptor.PositionLocationDisplay
+		}
+		return strings.Join(locations, "\n")
+	}},
+	{"WHO_MAY_APPLY", false, func(i usajobs.SearchResultItem) string {
+		return i.MatchedObjectDescriptor.UserArea.Details.WhoMayApply.Name
+	}},
+	{"HIRING_PATHS", false, func(i usajobs.SearchResultItem) string {
+		return strings.Join(i.MatchedObjectDescriptor.UserArea.Details.HiringPath, "\n")
+	}},
+	{"CLOSE_DATE", false, func(i usajobs.SearchResultItem) string {
+		return templateDate("Jan 2, 2006", i.MatchedObjectDescriptor.ApplicationCloseDate)
+	}},
+	{"TRAVEL", false, func(i usajobs.SearchResultItem) string { return i.MatchedObjectDescriptor.UserArea.Details.TravelCode }},
+	{"RELOCATION", false, func(i usajobs.SearchResultItem) string {
+		return yesNo(i.MatchedObjectDescriptor.UserArea.Details.Relocation)
+	}},
+	{"TELEWORK", false, func(i usajobs.SearchResultItem) string {
+		return yesNo(fmt.Sprint(i.MatchedObjectDescriptor.UserArea.Details.TeleworkEligible))
+	}},
+	{"REMOTE", false, func(i usajobs.SearchResultItem) string {
+		return yesNo(fmt.Sprint(i.MatchedObjectDescriptor.UserArea.Details.RemoteIndicator))
+	}},
+	{"CLEARANCE", false, func(i usajobs.SearchResultItem) string {
+		return i.MatchedObjectDescriptor.UserArea.Details.SecurityClearance
+	}},
+	{"KEY_REQUIREMENTS", false, func(i usajobs.SearchResultItem) string {
+		var requirements []string
+		for _, r := range i.MatchedObjectDescriptor.UserArea.Details.KeyRequirements {
+			requirements = append(requirements, fmt.Sprint(r))
+		}
+		return strings.Join(bullets(requirements), "\n")
+	}},
+	{"SCHEDULES", true, func(i usajobs.SearchResultItem) string {
+		var schedules []string
+		for _, s := range i.MatchedObjectDescriptor.PositionSchedule {
+			schedules = append(schedules, s.Name)
+		}
+		return strings.Join(schedules, "\n")
+	}},
+	{"APPOINTMENT_TYPES", true, func(i usajobs.SearchResultItem) string {
+		var types []string
+		for _, o := range i.MatchedObjectDescriptor.PositionOfferingType {
+			types = append(types, o.Name)
+		}
+		return strings.Join(types, "\n")
+	}},
+	{"SERIES", true, func(i usajobs.SearchResultItem) string {
+		var series []string
+		for _, c := range i.MatchedObjectDescriptor.JobCategory {
+			series = append(series, fmt.Sprintf("%s (%s)", c.Name, c.Code))
+		}
+		return strings.Join(series, "\n")
+	}},
+	{"OPEN_DATE", true, func(i usajobs.SearchResultItem) string {
+		return templateDate("Jan 2, 2006", i.MatchedObjectDescriptor.PublicationStartDate)
+	}},
+	{"OPENINGS", true, func(i usajobs.SearchResultItem) string {
+		return i.MatchedObjectDescriptor.UserArea.Details.TotalOpenings
+	}},
+	{"DRUG_TEST", true, func(i usajobs.SearchResultItem) string {
+		return yesNo(i.MatchedObjectDescriptor.UserArea.Details.DrugTestRequired)
+	}},
+	{"SENSITIVITY", true, func(i usajobs.SearchResultItem) string {
+		return i.MatchedObjectDescriptor.UserArea.Details.PositionSensitivity
+	}},
+	{"URL", true, func(i usajobs.SearchResultItem) string { return i.MatchedObjectDescriptor.PositionURI }},
+}
+
+// comparison is a fact of each compared posting, keyed by the id it was
+// looked up by.
+type comparison struct {
+	Field   string            `json:"field"`
+	Differs bool              `json:"differs"`
+	Values  map[string]string `json:"values"`
+}
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+	compareCmd.Flags().BoolVar(&compareDiff, "diff", false, "[optional] only show the rows that differ between the postings")
+}
+
+// compareConcurrency is the number of postings compare looks up at once.
+const compareConcurrency = 4
+
+func executeCompare(ids []string) error {
+	// ids are trimmed and repeated ids dropped before looking them up
+	seen := map[string]bool{}
+	var unique []string
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) < 2 {
+		return errors.New("compare requires at least two different postings")
+	}
+	ids = unique
+
+	err := initClient(true)
+	if err != nil {
+		return err
+	}
+
+	fetched := make([]usajobs.SearchResultItem, len(ids))
+	errs := make([]error, len(ids))
+	sem := make(chan struct{}, compareConcurrency)
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			item, err := Client.Search.ByID(context.Background(), id)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			fetched[i] = *item
+		}()
+	}
+	wg.Wait()
+
+	err = errors.Join(errs...)
+	if err != nil {
+		return err
+	}
+
+	// a posting looked up by both its control number and position id is
+	// only compared once, under the id given first
+	found := map[string]bool{}
+	var items []usajobs.SearchResultItem
+	var kept []string
+	for i, item := range fetched {
+		if found[item.MatchedObjectID] {
+			continue
+		}
+		found[item.MatchedObjectID] = true
+		items = append(items, item)
+		kept = append(kept, ids[i])
+	}
+	ids = kept
+	if len(items) < 2 {
+		return errors.New("compare requires at least two different postings")
+	}
+
+	if format != "" {
+		records := make([]searchTemplateItem, 0, len(items))
+		for _, item := range items {
+			records = append(records, searchTemplateItem{
+				MatchedObjectDescriptor: item.MatchedObjectDescriptor,
+				SearchItemDetails:       item.MatchedObjectDescriptor.UserArea.Details,
+				MatchedObjectID:         item.MatchedObjectID,
+				RelevanceRank:           item.RelevanceRank,
+			})
+		}
+		return displayTemplate(records)
+	}
+
+	comparisons := compareItems(ids, items, display == "detail")
+	if compareDiff {
+		var differ []comparison
+		for _, c := range comparisons {
+			if c.Differs {
+				differ = append(differ, c)
+			}
+		}
+		comparisons = differ
+	}
+
+	switch display {
+	case "json":
+		if comparisons == nil {
+			comparisons = []comparison{}
+		}
+		return displayJSON(comparisons)
+	case "ndjson":
+		return displayNDJSON(comparisons)
+	}
+
+	// csv has a column saying whether the values differ, tables mark and
+	// highlight the row instead
+	if display == "csv" {
+		headers := append([]string{"FIELD", "DIFFERS"}, ids...)
+		var data [][]string
+		for _, c := range comparisons {
+			row := []string{c.Field, fmt.Sprint(c.Differs)}
+			for _, id := range ids {
+				row = append(row, c.Values[id])
+			}
+			data = append(data, row)
+		}
+		return displayCSV(headers, data)
+	}
+
+	headers := append([]string{"FIELD"}, ids...)
+	var data [][]string
+	highlight := map[int]bool{}
+	for r, c := range comparisons {
+		name := c.Field
+		if c.Differs {
+			name = "* " + name
+			highlight[r] = true
+		}
+
+		row := []string{name}
+		for _, id := range ids {
+			row = append(row, c.Values[id])
+		}
+		data = append(data, row)
+	}
+
+	return renderHighlightedTable(headers, data, highlight)
+}
+
+// compareItems returns the compared facts of the postings looked up by ids,
+// including detail rows when detail is true.
+func compareItems(ids []string, items []usajobs.SearchResultItem, detail bool) []comparison {
+	var comparisons []comparison
+	for _, row := range compareRows {
+		if row.Detail && !detail {
+			continue
+		}
+
+		c := comparison{Field: row.Name, Values: map[string]string{}}
+		for i, item := range items {
+			c.Values[ids[i]] = row.Value(item)
+			if i > 0 && normalizeSpace(c.Values[ids[i]]) != normalizeSpace(c.Values[ids[0]]) {
+				c.Differs = true
+			}
+		}
+		comparisons = append(comparisons, c)
+	}
+	return comparisons
+}
+
+// normalizeSpace collapses runs of white space so values that only differ
+// in layout are equal.
+func normalizeSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,138 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+)
+
+func TestCompare(t *testing.T) {
+	data, err := os.ReadFile(searchTestDataPath)
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	}))
+	defer mockServer.Close()
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+
+	Client, err = usajobs.NewClient("test", "test")
+	if err != nil {
+		t.Fatalf("could not create new usajobs client: %v", err)
+	}
+	Client.BaseURL = u
+
+	defer func() {
+		display = "summary"
+		compareDiff = false
+	}()
+
+	compare := func(ids ...string) string {
+		t.Helper()
+
+		out, err := captureStdout(t, func() error { return executeCompare(ids) })
+		if err != nil {
+			t.Fatalf("failed to execute: %v", err)
+		}
+		return out
+	}
+
+	display = "json"
+	var comparisons []comparison
+	err = json.Unmarshal([]byte(compare("ICE-24-12345-MP", "800000002")), &comparisons)
+	if err != nil {
+		t.Fatalf("could not decode json output: %v", err)
+	}
+
+	rows := map[string]comparison{}
+	for _, c := range comparisons {
+		rows[c.Field] = c
+	}
+	if len(comparisons) != 14 || rows["TELEWORK"].Differs || !rows["REMOTE"].Differs || !rows["SALARY"].Differs {
+		t.Errorf("expected remote and salary to differ but not telework, got %+v", comparisons)
+	}
+	if rows["SALARY"].Values["800000002"] != "$98,496 - $128,043 Per Year" {
+		t.Errorf("expected salary of 800000002, got %+v", rows["SALARY"].Values)
+	}
+
+	display = "csv"
+	compareDiff = true
+	records, err := csv.NewReader(strings.NewReader(compare("800000001", "800000002", "800000003", "800000001"))).ReadAll()
+	if err != nil {
+		t.Fatalf("could not read csv output: %v", err)
+	}
+	if strings.Join(records[0], ",") != "FIELD,DIFFERS,800000001,800000002,800000003" {
+		t.Errorf("expected a column for each different posting, got %v", records[0])
+	}
+	for _, r := range records[1:] {
+		if r[1] != "true" || strings.HasPrefix(r[0], "* ") {
+			t.Errorf("expected only rows that differ with --diff and no marker, got %v", r)
+		}
+	}
+
+	// the control number and position id of the same posting are one posting
+	display = "json"
+	compareDiff = false
+	comparisons = nil
+	err = json.Unmarshal([]byte(compare("800000001", "ICE-24-12345-MP", "800000002")), &comparisons)
+	if err != nil {
+		t.Fatalf("could not decode json output: %v", err)
+	}
+	if len(comparisons[0].Values) != 2 || comparisons[0].Values["800000001"] == "" {
+		t.Errorf("expected the posting to be compared once, got %+v", comparisons[0].Values)
+	}
+
+	display = "detail"
+	compareDiff = false
+	out := compare("800000001", "800000002")
+	for _, want := range []string{"* JOB_TITLE", "SCHEDULES", "* URL"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected detail output to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	_, err = captureStdout(t, func() error { return executeCompare([]string{"800000001", "800000001"}) })
+	if err == nil {
+		t.Error("expected error comparing a posting with itself")
+	}
+
+	_, err = captureStdout(t, func() error { return executeCompare([]string{"800000001", "ICE-24-12345-MP"}) })
+	if err == nil {
+		t.Error("expected error comparing a posting with itself by its position id")
+	}
+
+	_, err = captureStdout(t, func() error { return executeCompare([]string{"800000001", "missing"}) })
+	if err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("expected a not found error for the missing posting, got %v", err)
+	}
+}
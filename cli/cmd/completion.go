@@ -0,0 +1,211 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// codeListCacheTTL is how long a codelist cached for completion is used
+	// before it is requested again.
+	codeListCacheTTL = 7 * 24 * time.Hour
+	// completionTimeout bounds the codelist requests made while completing so
+	// the shell does not hang when usajobs is unreachable.
+	completionTimeout = 5 * time.Second
+)
+
+// codeListCacheDir is the directory codelists are cached in for completion,
+// or ~/.cache/usajobs/codelists when empty.
+var codeListCacheDir string
+
+// completionFunc suggests values for a flag or argument. Suggestions may be
+// described with a tab (ex., "2210\tInformation Technology Management").
+type completionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// registerCompletions registers the completion function of each flag of cmd.
+func registerCompletions(cmd *cobra.Command, funcs map[string]completionFunc) {
+	for flag, f := range funcs {
+		err := cmd.RegisterFlagCompletionFunc(flag, f)
+		if err != nil {
+			log.Fatal().Err(err).Msgf("failed to register completion for %s", flag)
+		}
+	}
+}
+
+// completeCodeList suggests the codes of a codelist, described by their
+// values. Flags that take a comma separated list (list true) complete the
+// code after the last comma and do not add a space after it.
+func completeCodeList(name string, list bool) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if initClient(false) == nil && Client.Client.Timeout == 0 {
+			Client.Client.Timeout = completionTimeout
+		}
+
+		values, err := cachedCodeList(name)
+		if err != nil {
+			cobra.CompErrorln(err.Error())
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		if !list {
+			return codeCompletions(values, "", toComplete), cobra.ShellCompDirectiveNoFileComp
+		}
+
+		prefix, last := "", toComplete
+		if i := strings.LastIndex(toComplete, ","); i >= 0 {
+			prefix, last = toComplete[:i+1], toComplete[i+1:]
+		}
+		return codeCompletions(values, prefix, last), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	}
+}
+
+// codeCompletions returns the codes starting with toComplete, ignoring case,
+// after prefix. Codes already in the comma separated prefix are skipped.
+func codeCompletions(values []usajobs.CodeListValue, prefix, toComplete string) []string {
+	chosen := map[string]bool{}
+	for _, c := range splitList(prefix) {
+		chosen[strings.ToLower(c)] = true
+	}
+
+	var completions []string
+	for _, v := range values {
+		code := strings.TrimSpace(v.Code)
+		if code == "" || chosen[strings.ToLower(code)] || !strings.HasPrefix(strings.ToLower(code), strings.ToLower(toComplete)) {
+			continue
+		}
+		completions = append(completions, prefix+code+"\t"+strings.TrimSpace(v.Value))
+	}
+	return completions
+}
+
+// completeValues suggests a fixed set of values, each followed by a tab and
+// its description.
+func completeValues(values ...string) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeProfiles suggests the profiles of the config file.
+func completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := loadConfig()
+	if err != nil {
+		cobra.CompErrorln(err.Error())
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var names []string
+	for name, p := range cfg.Profiles {
+		names = append(names, name+"\t"+p.UserAgent)
+	}
+	sort.Strings(names)
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProfileArg suggests a profile for the first argument.
+func completeProfileArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeProfiles(cmd, args, toComplete)
+}
+
+// cachedCodeList returns the values of a codelist that are not disabled,
+// from the cache when it is recent. A stale cache is used when usajobs cannot
+// be reached.
+func cachedCodeList(name string) ([]usajobs.CodeListValue, error) {
+	dir := codeListCacheDir
+	if dir == "" {
+		cache, err := os.UserCacheDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(cache, "usajobs", "codelists")
+	}
+	path := filepath.Join(dir, name+".json")
+
+	read := func() (*usajobs.CodeListResponse, error) {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var data usajobs.CodeListResponse
+		err = json.Unmarshal(b, &data)
+		return &data, err
+	}
+
+	info, err := os.Stat(path)
+	if err == nil && time.Since(info.ModTime()) < codeListCacheTTL {
+		data, err := read()
+		if err == nil {
+			return data.Filter(usajobs.CodeListFilter{}).Values(), nil
+		}
+	}
+
+	data, fetchErr := fetchCodeList(name)
+	if fetchErr != nil {
+		stale, err := read()
+		if err != nil {
+			return nil, fetchErr
+		}
+		return stale.Filter(usajobs.CodeListFilter{}).Values(), nil
+	}
+
+	b, err := json.Marshal(data)
+	if err == nil {
+		err = os.MkdirAll(dir, 0o700)
+	}
+	if err == nil {
+		err = os.WriteFile(path, b, 0o600)
+	}
+	if err != nil {
+		// completion still works without the cache, only slower
+		cobra.CompDebugln("failed to cache "+name+": "+err.Error(), false)
+	}
+
+	return data.Filter(usajobs.CodeListFilter{}).Values(), nil
+}
+
+func fetchCodeList(name string) (*usajobs.CodeListResponse, error) {
+	err := initClient(false)
+	if err != nil {
+		return nil, err
+	}
+
+	r, data, err := Client.CodeList(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.StatusCode != http.StatusOK {
+		return nil, errors.New("bad response from usajobs: " + r.Status)
+	}
+
+	return data, nil
+}
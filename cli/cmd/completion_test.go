@@ -0,0 +1,157 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// complete runs the hidden completion command for args and returns the
+// suggestions, without the trailing directive line.
+func complete(t *testing.T, args ...string) []string {
+	t.Helper()
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs(append([]string{"__complete"}, args...))
+	defer func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetArgs(nil)
+	}()
+
+	err := rootCmd.Execute()
+	if err != nil {
+		t.Fatalf("could not complete %v: %v", args, err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	return lines[:len(lines)-1]
+}
+
+func TestCompletion(t *testing.T) {
+	client := Client
+	defer func() { Client, codeListCacheDir = client, "" }()
+	newCodeListServer(t)
+	codeListCacheDir = t.TempDir()
+
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"search", "--clearance", ""}, []string{"0\tNot Required", "1\tConfidential", "2\tSecret", "3\tTop Secret"}},
+		{[]string{"search", "--clearance", "1,"}, []string{"1,0\tNot Required", "1,2\tSecret", "1,3\tTop Secret"}},
+		{[]string{"search", "--job-catagory", "221"}, []string{"2210\t"}},
+		{[]string{"browse", "-j", "0854,221"}, []string{"0854,2210\t"}},
+		{[]string{"search", "--who-may-apply", ""}, []string{"All\t", "Public\t", "Status\t"}},
+		{[]string{"search", "--display", ""}, []string{"summary", "detail", "csv", "json", "ndjson"}},
+		{[]string{"list", "securityc"}, []string{"securityclearances"}},
+	}
+
+	for _, tt := range tests {
+		got := complete(t, tt.args...)
+		for _, w := range tt.want {
+			found := false
+			for _, g := range got {
+				if strings.HasPrefix(g, w) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				t.Errorf("%v: expected a completion starting with %q, got %q", tt.args, w, got)
+			}
+		}
+	}
+
+	// the fetched codelists are cached
+	_, err := os.Stat(filepath.Join(codeListCacheDir, "securityclearances.json"))
+	if err != nil {
+		t.Errorf("expected securityclearances to be cached: %v", err)
+	}
+}
+
+func TestCachedCodeList(t *testing.T) {
+	client := Client
+	defer func() { Client, codeListCacheDir = client, "" }()
+	newCodeListServer(t)
+	codeListCacheDir = t.TempDir()
+
+	values, err := cachedCodeList("securityclearances")
+	if err != nil {
+		t.Fatalf("could not get codelist: %v", err)
+	}
+	if len(values) == 0 {
+		t.Fatal("expected codelist values")
+	}
+
+	// a recent cache is used without a request, and a stale one when usajobs
+	// cannot be reached
+	Client.BaseURL.Host = "127.0.0.1:1"
+	path := filepath.Join(codeListCacheDir, "securityclearances.json")
+	for _, age := range []time.Duration{0, 2 * codeListCacheTTL} {
+		modified := time.Now().Add(-age)
+		err = os.Chtimes(path, modified, modified)
+		if err != nil {
+			t.Fatalf("could not age cache: %v", err)
+		}
+
+		got, err := cachedCodeList("securityclearances")
+		if err != nil {
+			t.Fatalf("could not get cached codelist aged %v: %v", age, err)
+		}
+		if len(got) != len(values) {
+			t.Errorf("aged %v: expected %d values, got %d", age, len(values), len(got))
+		}
+	}
+
+	_, err = cachedCodeList("hiringpaths")
+	if err == nil {
+		t.Error("expected an error for an uncached codelist when usajobs cannot be reached")
+	}
+}
+
+func TestCompleteProfiles(t *testing.T) {
+	configFile = filepath.Join(t.TempDir(), "config.yaml")
+	defer func() { configFile = "" }()
+
+	err := os.WriteFile(configFile, []byte(`profiles:
+  work:
+    token: a
+    user-agent: work@example.com
+  home:
+    token: b
+    user-agent: home@example.com
+`), 0o600)
+	if err != nil {
+		t.Fatalf("could not write config: %v", err)
+	}
+
+	got := complete(t, "config", "use", "")
+	want := []string{"home\thome@example.com", "work\twork@example.com"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	got = complete(t, "config", "use", "home", "")
+	if len(got) != 0 {
+		t.Errorf("expected no completions for a second argument, got %q", got)
+	}
+}
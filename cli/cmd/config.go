@@ -0,0 +1,435 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// envToken and envUserAgent are the environment variables credentials are
+	// read from when they are not set by flags.
+	envToken     = "USAJOBS_TOKEN"
+	envUserAgent = "USAJOBS_USER_AGENT"
+	// envProfile selects a config profile when --profile is not set.
+	envProfile = "USAJOBS_PROFILE"
+	// defaultProfile is the profile config set writes to when no profile is
+	// selected and none is current.
+	defaultProfile = "default"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the credentials profiles used to call usajobs",
+	Long: `
+Manage named profiles of usajobs credentials kept in the config file
+(default ~/.config/usajobs/config.yaml), so tokens do not need to be passed as flags
+or end up in shell history.
+
+Credentials are resolved in order from the --token and --user-agent flags, the
+USAJOBS_TOKEN and USAJOBS_USER_AGENT environment variables, and then the profile
+selected by --profile, USAJOBS_PROFILE, or 'usajobs config use'.
+
+Example Usage:
+
+    Save credentials to the default profile and search without flags, typing
+    the token at the prompt so it is not echoed or kept in shell history:
+    usajobs config set token
+    usajobs config set user-agent $EMAIL
+    usajobs search --keyword=cyber
+
+    Keep a second profile, reading its token from a secrets manager, and switch
+    between them:
+    pass show usajobs/work | usajobs config set token --profile=work
+    usajobs config set user-agent me@agency.gov --profile=work
+    usajobs search --keyword=cyber --profile=work
+    usajobs config use work
+
+    `,
+}
+
+// configSetCmd represents the config set command
+var configSetCmd = &cobra.Command{
+	Use:   "set <token|user-agent> [value]",
+	Short: "Set a credential of the selected profile",
+	Long: `
+Set a credential of the selected profile. When the value is omitted it is read
+from stdin, without echoing it when stdin is a terminal, so tokens do not end up
+in shell history.
+    `,
+	Args:      cobra.RangeArgs(1, 2),
+	ValidArgs: []string{"token", "user-agent"},
+	Run: func(cmd *cobra.Command, args []string) {
+		var value string
+		var err error
+		if len(args) == 2 {
+			value = args[1]
+		} else {
+			value, err = readConfigValue(os.Stdin, args[0])
+		}
+		if err == nil {
+			err = executeConfigSet(args[0], value)
+		}
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to execute config set command")
+		}
+	},
+}
+
+// configUseCmd represents the config use command
+var configUseCmd = &cobra.Command{
+	Use:               "use <profile>",
+	Short:             "Select the profile used when --profile is not set",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProfileArg,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := executeConfigUse(args[0])
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to execute config use command")
+		}
+	},
+}
+
+// configListCmd represents the config list command
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List profiles, with their tokens masked",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := executeConfigList()
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to execute config list command")
+		}
+	},
+}
+
+// configRemoveCmd represents the config remove command
+var configRemoveCmd = &cobra.Command{
+	Use:               "remove <profile>",
+	Short:             "Remove a profile",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProfileArg,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := executeConfigRemove(args[0])
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to execute config remove command")
+		}
+	},
+}
+
+// cliConfig is the config file of the CLI.
+type cliConfig struct {
+	CurrentProfile string                `yaml:"current-profile,omitempty"`
+	Profiles       map[string]cliProfile `yaml:"profiles,omitempty"`
+}
+
+// cliProfile is a named set of usajobs credentials.
+type cliProfile struct {
+	Token     string `yaml:"token,omitempty"`
+	UserAgent string `yaml:"user-agent,omitempty"`
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUseCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configRemoveCmd)
+}
+
+// configPath returns the path of the config file, --config or
+// ~/.config/usajobs/config.yaml.
+func configPath() (string, error) {
+	if configFile != "" {
+		return configFile, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "usajobs", "config.yaml"), nil
+}
+
+// loadConfig reads the config file. A missing file is an empty config.
+func loadConfig() (cliConfig, error) {
+	var cfg cliConfig
+
+	path, err := configPath()
+	if err != nil {
+		return cfg, err
+	}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	err = yaml.Unmarshal(b, &cfg)
+	if err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// saveConfig writes the config file readable only by the current user, since
+// it holds api tokens.
+func saveConfig(cfg cliConfig) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o700)
+	if err != nil {
+		return err
+	}
+
+	b, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, b, 0o600)
+}
+
+// selectedProfile returns the name of the profile selected by --profile or
+// USAJOBS_PROFILE, or the current profile of the config.
+func selectedProfile(cfg cliConfig) string {
+	if profile != "" {
+		return profile
+	}
+	if p := os.Getenv(envProfile); p != "" {
+		return p
+	}
+	return cfg.CurrentProfile
+}
+
+// resolveCredentials returns the user agent and token from the flags, the
+// environment, or the selected profile, in that order. Either may be empty
+// when it is not set anywhere.
+func resolveCredentials() (string, string, error) {
+	ua, token := userAgent, apiToken
+	if ua == "" {
+		ua = os.Getenv(envUserAgent)
+	}
+	if token == "" {
+		token = os.Getenv(envToken)
+	}
+
+	// the config file is only read when it is needed, or when a profile was
+	// named so a misspelled profile is reported
+	if ua != "" && token != "" && profile == "" && os.Getenv(envProfile) == "" {
+		return ua, token, nil
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return "", "", err
+	}
+
+	name := selectedProfile(cfg)
+	if name == "" {
+		return ua, token, nil
+	}
+
+	p, ok := cfg.Profiles[name]
+	if !ok {
+		return "", "", fmt.Errorf("unknown profile %q, add it with 'usajobs config set --profile=%s'", name, name)
+	}
+
+	if ua == "" {
+		ua = p.UserAgent
+	}
+	if token == "" {
+		token = p.Token
+	}
+	return ua, token, nil
+}
+
+// initClient creates the shared Client from the resolved credentials if it
+// does not exist yet. Commands calling endpoints that do not require an api
+// token (ex., /codelist) pass required false and fall back to placeholder
+// credentials.
+func initClient(required bool) error {
+	if Client != nil {
+		return nil
+	}
+
+	ua, token, err := resolveCredentials()
+	if err != nil {
+		return err
+	}
+
+	if ua == "" || token == "" {
+		if required {
+			return errors.New("a usajobs api token and user agent are required, set them with --token and --user-agent, " +
+				envToken + " and " + envUserAgent + ", or 'usajobs config set'")
+		}
+		ua, token = "not", "required"
+	}
+
+	Client, err = usajobs.NewClient(ua, token)
+	return err
+}
+
+// readConfigValue reads the value of a config key from in, prompting without
+// echo when in is a terminal and otherwise reading its first line.
+func readConfigValue(in *os.File, key string) (string, error) {
+	if term.IsTerminal(int(in.Fd())) {
+		fmt.Fprintf(os.Stderr, "%s: ", key)
+		b, err := term.ReadPassword(int(in.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(b)), nil
+	}
+
+	value, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", fmt.Errorf("no %s value provided on stdin", key)
+	}
+	return value, nil
+}
+
+func executeConfigSet(key, value string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	name := selectedProfile(cfg)
+	if name == "" {
+		name = defaultProfile
+	}
+
+	p := cfg.Profiles[name]
+	switch key {
+	case "token":
+		p.Token = value
+	case "user-agent":
+		p.UserAgent = value
+	default:
+		return fmt.Errorf("unknown key %q, expected token or user-agent", key)
+	}
+
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]cliProfile{}
+	}
+	cfg.Profiles[name] = p
+
+	// the first profile is used without having to select it
+	if cfg.CurrentProfile == "" {
+		cfg.CurrentProfile = name
+	}
+
+	return saveConfig(cfg)
+}
+
+func executeConfigUse(name string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	_, ok := cfg.Profiles[name]
+	if !ok {
+		return fmt.Errorf("unknown profile %q", name)
+	}
+
+	cfg.CurrentProfile = name
+	return saveConfig(cfg)
+}
+
+func executeConfigList() error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	headers := []string{"CURRENT", "PROFILE", "USER_AGENT", "TOKEN"}
+	var data [][]string
+	for _, name := range names {
+		p := cfg.Profiles[name]
+
+		current := ""
+		if name == cfg.CurrentProfile {
+			current = "*"
+		}
+		data = append(data, []string{current, name, p.UserAgent, maskToken(p.Token)})
+	}
+
+	switch display {
+	case "csv":
+		return displayCSV(headers, data)
+	default:
+		return displayTable(headers, data)
+	}
+}
+
+func executeConfigRemove(name string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	_, ok := cfg.Profiles[name]
+	if !ok {
+		return fmt.Errorf("unknown profile %q", name)
+	}
+
+	delete(cfg.Profiles, name)
+	if cfg.CurrentProfile == name {
+		cfg.CurrentProfile = ""
+	}
+	return saveConfig(cfg)
+}
+
+// maskToken hides all but the last four characters of a token.
+func maskToken(token string) string {
+	if len(token) <= 4 {
+		return strings.Repeat("*", len(token))
+	}
+	return "****" + token[len(token)-4:]
+}
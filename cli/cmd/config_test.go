@@ -0,0 +1,221 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfig(t *testing.T) {
+	configFile = filepath.Join(t.TempDir(), "usajobs", "config.yaml")
+	defer func() { configFile, profile = "", "" }()
+
+	err := executeConfigSet("token", "default-token")
+	if err != nil {
+		t.Fatalf("failed to set token: %v", err)
+	}
+	err = executeConfigSet("user-agent", "me@example.com")
+	if err != nil {
+		t.Fatalf("failed to set user agent: %v", err)
+	}
+
+	profile = "work"
+	err = executeConfigSet("token", "work-token")
+	if err != nil {
+		t.Fatalf("failed to set work token: %v", err)
+	}
+	profile = ""
+
+	err = executeConfigSet("password", "secret")
+	if err == nil {
+		t.Error("expected error for unknown key, got nil")
+	}
+
+	info, err := os.Stat(configFile)
+	if err != nil {
+		t.Fatalf("expected config file to be written: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("expected config file mode 0600, got %v", info.Mode().Perm())
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if cfg.CurrentProfile != defaultProfile {
+		t.Errorf("expected the first profile to be current, got %q", cfg.CurrentProfile)
+	}
+	if cfg.Profiles["default"] != (cliProfile{Token: "default-token", UserAgent: "me@example.com"}) || cfg.Profiles["work"].Token != "work-token" {
+		t.Errorf("unexpected profiles: %+v", cfg.Profiles)
+	}
+
+	out, err := captureStdout(t, executeConfigList)
+	if err != nil {
+		t.Fatalf("failed to list profiles: %v", err)
+	}
+	if strings.Contains(out, "default-token") || !strings.Contains(out, "****oken") {
+		t.Errorf("expected masked tokens, got:\n%s", out)
+	}
+
+	err = executeConfigUse("work")
+	if err != nil {
+		t.Fatalf("failed to use work profile: %v", err)
+	}
+	err = executeConfigUse("missing")
+	if err == nil {
+		t.Error("expected error for unknown profile, got nil")
+	}
+
+	err = executeConfigRemove("work")
+	if err != nil {
+		t.Fatalf("failed to remove work profile: %v", err)
+	}
+
+	cfg, err = loadConfig()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if _, ok := cfg.Profiles["work"]; ok || cfg.CurrentProfile != "" {
+		t.Errorf("expected work profile to be removed, got %+v", cfg)
+	}
+}
+
+func TestReadConfigValue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stdin")
+
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"piped-token\n", "piped-token", false},
+		{"  piped-token\nignored\n", "piped-token", false},
+		{"no-newline", "no-newline", false},
+		{"", "", true},
+		{"\n", "", true},
+	}
+
+	for _, tt := range tests {
+		err := os.WriteFile(path, []byte(tt.in), 0o600)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		got, err := readConfigValue(f, "token")
+		f.Close()
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("readConfigValue(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestResolveCredentials(t *testing.T) {
+	configFile = filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(configFile, []byte(`current-profile: default
+profiles:
+  default:
+    token: default-token
+    user-agent: default@example.com
+  work:
+    token: work-token
+    user-agent: work@example.com
+`), 0o600)
+	if err != nil {
+		t.Fatalf("could not write config: %v", err)
+	}
+
+	defer func() { configFile, profile, userAgent, apiToken = "", "", "", "" }()
+
+	tests := []struct {
+		name      string
+		flags     [3]string // user agent, token, profile
+		env       [3]string // USAJOBS_USER_AGENT, USAJOBS_TOKEN, USAJOBS_PROFILE
+		userAgent string
+		token     string
+		err       bool
+	}{
+		{name: "current profile", userAgent: "default@example.com", token: "default-token"},
+		{name: "profile flag", flags: [3]string{"", "", "work"}, userAgent: "work@example.com", token: "work-token"},
+		{name: "profile env", env: [3]string{"", "", "work"}, userAgent: "work@example.com", token: "work-token"},
+		{name: "env overrides profile", env: [3]string{"", "env-token", ""}, userAgent: "default@example.com", token: "env-token"},
+		{name: "flags override env", flags: [3]string{"flag@example.com", "flag-token", ""}, env: [3]string{"env@example.com", "env-token", ""}, userAgent: "flag@example.com", token: "flag-token"},
+		{name: "unknown profile", flags: [3]string{"", "", "missing"}, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userAgent, apiToken, profile = tt.flags[0], tt.flags[1], tt.flags[2]
+			t.Setenv(envUserAgent, tt.env[0])
+			t.Setenv(envToken, tt.env[1])
+			t.Setenv(envProfile, tt.env[2])
+
+			ua, token, err := resolveCredentials()
+			if tt.err {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to resolve credentials: %v", err)
+			}
+
+			if ua != tt.userAgent || token != tt.token {
+				t.Errorf("expected %s %s, got %s %s", tt.userAgent, tt.token, ua, token)
+			}
+		})
+	}
+}
+
+func TestInitClient(t *testing.T) {
+	configFile = filepath.Join(t.TempDir(), "config.yaml")
+	client := Client
+	defer func() { configFile, Client = "", client }()
+
+	t.Setenv(envUserAgent, "")
+	t.Setenv(envToken, "")
+	t.Setenv(envProfile, "")
+
+	Client = nil
+	err := initClient(true)
+	if err == nil || !strings.Contains(err.Error(), envToken) {
+		t.Errorf("expected an error naming %s, got %v", envToken, err)
+	}
+
+	err = initClient(false)
+	if err != nil || Client == nil {
+		t.Fatalf("expected a client without credentials, got %v", err)
+	}
+
+	Client = nil
+	t.Setenv(envUserAgent, "me@example.com")
+	t.Setenv(envToken, "env-token")
+	err = initClient(true)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if Client.ApiToken != "env-token" || Client.UserAgent != "me@example.com" {
+		t.Errorf("expected the client to use the environment credentials, got %s %s", Client.UserAgent, Client.ApiToken)
+	}
+}
@@ -0,0 +1,295 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+)
+
+// templateDateLayouts are the date formats the date template function parses.
+var templateDateLayouts = []string{
+	"2006-01-02T15:04:05.9999999",
+	"2006-01-02T15:04:05.9999999Z07:00",
+	"2006-01-02",
+	time.RFC3339,
+}
+
+// codeNames caches the code to value maps used by the codename template
+// function, keyed by codelist name.
+var codeNames = map[string]map[string]string{}
+
+// searchTemplateItem is the value a --format template is executed with for
+// each search result. The descriptor and details fields are promoted so
+// templates can use {{.PositionTitle}} and {{.HiringPath}} rather than the
+// full path to each field.
+type searchTemplateItem struct {
+	usajobs.MatchedObjectDescriptor
+	usajobs.SearchItemDetails
+	MatchedObjectID string
+	RelevanceRank   float64
+	// Distance is the number of miles from --near, or 0 without --near.
+	Distance float64
+	// Queries are the --query files that returned the posting.
+	Queries []string
+}
+
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"join":     templateJoin,
+		"truncate": templateTruncate,
+		"date":     templateDate,
+		"salary":   templateSalary,
+		"codename": templateCodeName,
+	}
+}
+
+// loadTemplate returns the --format template. Named templates are loaded from
+// every *.tmpl file in the template directory, and --format may either be the
+// name of one of them or an inline template that can call them with
+// {{template "name" .}}.
+func loadTemplate() (*template.Template, error) {
+	t := template.New("format").Funcs(templateFuncs())
+
+	dir := templateDir
+	if dir == "" {
+		config, err := os.UserConfigDir()
+		if err == nil {
+			dir = filepath.Join(config, "usajobs", "templates")
+		}
+	}
+
+	if dir != "" {
+		files, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(files) == 0 && templateDir != "" {
+			_, err := os.Stat(templateDir)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for _, f := range files {
+			b, err := os.ReadFile(f)
+			if err != nil {
+				return nil, err
+			}
+
+			name := strings.TrimSuffix(filepath.Base(f), ".tmpl")
+			_, err = t.New(name).Parse(strings.TrimRight(string(b), "\r\n"))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if named := t.Lookup(format); named != nil && !strings.Contains(format, "{{") {
+		return named, nil
+	}
+
+	return t.Parse(format)
+}
+
+// displayTemplate prints each record using the --format template, one per
+// line.
+func displayTemplate[T any](records []T) error {
+	t, err := loadTemplate()
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	for _, r := range records {
+		err = t.Execute(w, r)
+		if err != nil {
+			return err
+		}
+
+		err = w.WriteByte('\n')
+		if err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
+
+// templateJoin joins the elements of any slice with sep.
+func templateJoin(v any, sep string) (string, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return "", fmt.Errorf("join: expected a list, got %T", v)
+	}
+
+	s := make([]string, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		s = append(s, fmt.Sprint(rv.Index(i).Interface()))
+	}
+	return strings.Join(s, sep), nil
+}
+
+// templateTruncate shortens s to at most n characters, ending with "…" when
+// it was cut.
+func templateTruncate(n int, s string) string {
+	if n <= 0 || utf8.RuneCountInString(s) <= n {
+		return s
+	}
+
+	r := []rune(s)
+	if n == 1 {
+		return "…"
+	}
+	return string(r[:n-1]) + "…"
+}
+
+// templateDate reformats a usajobs date with a Go time layout (ex.,
+// "Jan 2, 2006"). Dates that cannot be parsed are returned unchanged.
+func templateDate(layout string, v any) string {
+	switch d := v.(type) {
+	case time.Time:
+		return d.Format(layout)
+	case string:
+		for _, l := range templateDateLayouts {
+			t, err := time.Parse(l, d)
+			if err == nil {
+				return t.Format(layout)
+			}
+		}
+		return d
+	}
+	return fmt.Sprint(v)
+}
+
+// templateSalary formats a salary amount as dollars (ex., $117,962), or a
+// PositionRemuneration as a range with its interval (ex., $117,962 - $183,500
+// Per Year).
+func templateSalary(v any) (string, error) {
+	switch s := v.(type) {
+	case []usajobs.PositionRemuneration:
+		var ranges []string
+		for _, r := range s {
+			f, err := templateSalary(r)
+			if err != nil {
+				return "", err
+			}
+			ranges = append(ranges, f)
+		}
+		return strings.Join(ranges, usajobs.FieldSeparator), nil
+	case usajobs.PositionRemuneration:
+		low, _ := templateSalary(s.MinimumRange)
+		high, _ := templateSalary(s.MaximumRange)
+
+		f := low
+		if high != "" && high != low {
+			f += " - " + high
+		}
+		if s.Description != "" {
+			f += " " + s.Description
+		}
+		return strings.TrimSpace(f), nil
+	case string:
+		amount, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", ""), 64)
+		if err != nil {
+			return s, nil
+		}
+		return dollars(amount), nil
+	case float64:
+		return dollars(s), nil
+	case int:
+		return dollars(float64(s)), nil
+	case int64:
+		return dollars(float64(s)), nil
+	}
+	return "", fmt.Errorf("salary: unsupported type %T", v)
+}
+
+// dollars formats an amount with thousands separators, including cents only
+// when the amount is not a whole number of dollars.
+func dollars(amount float64) string {
+	s := strconv.FormatFloat(amount, 'f', 2, 64)
+	whole, cents, _ := strings.Cut(s, ".")
+
+	neg := strings.HasPrefix(whole, "-")
+	whole = strings.TrimPrefix(whole, "-")
+
+	var b strings.Builder
+	for i, c := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+
+	f := "$" + b.String()
+	if cents != "00" {
+		f += "." + cents
+	}
+	if neg {
+		f = "-" + f
+	}
+	return f
+}
+
+// templateCodeName returns the value of code in the named codelist (ex.,
+// codename "occupationalseries" "2210"), or the code when it is not found.
+func templateCodeName(codelist string, code any) (string, error) {
+	c := fmt.Sprint(code)
+
+	names, ok := codeNames[codelist]
+	if !ok {
+		var err error
+		err = initClient(false)
+		if err != nil {
+			return "", err
+		}
+
+		r, data, err := Client.CodeList(codelist)
+		if err != nil {
+			return "", err
+		}
+
+		if r.StatusCode != http.StatusOK {
+			return "", errors.New("bad response from usajobs: " + r.Status)
+		}
+
+		names = map[string]string{}
+		for _, v := range data.Values() {
+			names[v.Code] = v.Value
+		}
+		codeNames[codelist] = names
+	}
+
+	name, ok := names[c]
+	if !ok {
+		return c, nil
+	}
+	return name, nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	joined, err := templateJoin([]string{"fed-competitive", "vet"}, ", ")
+	if err != nil || joined != "fed-competitive, vet" {
+		t.Errorf("unexpected join result %q: %v", joined, err)
+	}
+
+	_, err = templateJoin("not a list", ", ")
+	if err == nil {
+		t.Error("expected join error for a non-list, got nil")
+	}
+
+	if s := templateTruncate(8, "Información Technology"); s != "Informa…" {
+		t.Errorf("unexpected truncate result %q", s)
+	}
+
+	if s := templateTruncate(30, "IT Specialist"); s != "IT Specialist" {
+		t.Errorf("expected short text unchanged, got %q", s)
+	}
+
+	if s := templateDate("Jan 2, 2006", "2024-07-19T23:59:59.9970"); s != "Jul 19, 2024" {
+		t.Errorf("unexpected date result %q", s)
+	}
+
+	if s := templateDate("Jan 2", "soon"); s != "soon" {
+		t.Errorf("expected unparsable date unchanged, got %q", s)
+	}
+
+	salaries := []struct {
+		in   any
+		want string
+	}{
+		{"117962.0", "$117,962"},
+		{23.5, "$23.50"},
+		{int64(1000000), "$1,000,000"},
+		{usajobs.PositionRemuneration{MinimumRange: "117962.0", MaximumRange: "183500.0", Description: "Per Year"}, "$117,962 - $183,500 Per Year"},
+	}
+	for _, tt := range salaries {
+		s, err := templateSalary(tt.in)
+		if err != nil || s != tt.want {
+			t.Errorf("salary(%v): expected %q, got %q: %v", tt.in, tt.want, s, err)
+		}
+	}
+}
+
+func TestSearchFormat(t *testing.T) {
+	data, err := os.ReadFile(searchTestDataPath)
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	series, err := os.ReadFile("../../testdata/occupationalseries-testdata.json")
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if r.URL.Path == "/codelist/occupationalseries" {
+			w.Write(series)
+			return
+		}
+		w.Write(data)
+	}))
+	defer mockServer.Close()
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+
+	Client, err = usajobs.NewClient("test", "test")
+	if err != nil {
+		panic(err.Error())
+	}
+	Client.BaseURL = u
+
+	templateDir = t.TempDir()
+	defer func() {
+		format = ""
+		templateDir = ""
+	}()
+
+	err = os.WriteFile(filepath.Join(templateDir, "brief.tmpl"), []byte(`{{.MatchedObjectID}} {{.PositionTitle | truncate 13}}`+"\n"), 0o600)
+	if err != nil {
+		t.Fatalf("could not write template: %v", err)
+	}
+
+	opt := usajobs.SearchOptions{Keyword: "it"}
+
+	format = `{{template "brief" .}} | {{date "Jan 2" .ApplicationCloseDate}} | {{salary .PositionRemuneration}} | {{join .HiringPath ","}} | {{codename "occupationalseries" (index .JobCategory 0).Code}}`
+	out, err := captureStdout(t, func() error { return executeSearch(&opt) })
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	want := "800000001 IT Specialis… | Jul 19 | $117,962 - $183,500 Per Year | fed-competitive,vet | Information Technology Management"
+	if len(lines) != 3 || lines[0] != want {
+		t.Errorf("expected first line %q, got:\n%s", want, out)
+	}
+
+	format = "brief"
+	out, err = captureStdout(t, func() error { return executeSearch(&opt) })
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if !strings.HasPrefix(out, "800000001 IT Specialis…\n800000002 Software Eng…\n") {
+		t.Errorf("unexpected output for named template:\n%s", out)
+	}
+
+	format = "{{.NotAField}}"
+	err = executeSearch(&opt)
+	if err == nil {
+		t.Error("expected error for unknown template field, got nil")
+	}
+}
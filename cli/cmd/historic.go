@@ -0,0 +1,214 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// historicCmd represents the historic command
+var historicCmd = &cobra.Command{
+	Use:   "historic",
+	Short: "Search closed job announcements published by usajobs",
+	Long: `
+Search historic (closed) job opportunity announcements published by USAJobs. All
+pages of results are requested automatically. Dates use the YYYY-MM-DD format.
+
+Example Usage:
+
+    IT Specialist announcements opened in January 2023:
+    usajobs historic --series=2210 --open-from=2023-01-01 --open-to=2023-01-31
+
+    `,
+	Run: func(cmd *cobra.Command, args []string) {
+		opt, err := setHistoricOptions()
+		if err != nil {
+			log.Fatal().Err(err).Msg("invalid historic options")
+		}
+
+		err = executeHistoric(&opt)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to execute historic command")
+		}
+	},
+}
+
+var (
+	HiringAgencyCodes      []string
+	HiringDepartmentCodes  []string
+	PositionSeries         []string
+	AnnouncementNumbers    []string
+	USAJOBSControlNumbers  []string
+	StartPositionOpenDate  string
+	EndPositionOpenDate    string
+	StartPositionCloseDate string
+	EndPositionCloseDate   string
+)
+
+func init() {
+	rootCmd.AddCommand(historicCmd)
+	historicCmd.Flags().StringSliceVar(&HiringAgencyCodes, "agency", []string{}, "[optional][Comma Separated List] Filter by hiring agency codes (ex., HSCE,ARFC)")
+	historicCmd.Flags().StringSliceVar(&HiringDepartmentCodes, "department", []string{}, "[optional][Comma Separated List] Filter by hiring department codes (ex., HS,AR)")
+	historicCmd.Flags().StringSliceVar(&PositionSeries, "series", []string{}, "[optional][Comma Separated List] Filter by occupational series (ex., 2210,0854)")
+	historicCmd.Flags().StringSliceVar(&AnnouncementNumbers, "announcement", []string{}, "[optional][Comma Separated List] Filter by announcement numbers")
+	historicCmd.Flags().StringSliceVar(&USAJOBSControlNumbers, "control-number", []string{}, "[optional][Comma Separated List] Filter by usajobs control numbers")
+	historicCmd.Flags().StringVar(&StartPositionOpenDate, "open-from", "", "[optional][YYYY-MM-DD] Only include announcements opened on or after this date")
+	historicCmd.Flags().StringVar(&EndPositionOpenDate, "open-to", "", "[optional][YYYY-MM-DD] Only include announcements opened on or before this date")
+	historicCmd.Flags().StringVar(&StartPositionCloseDate, "close-from", "", "[optional][YYYY-MM-DD] Only include announcements closed on or after this date")
+	historicCmd.Flags().StringVar(&EndPositionCloseDate, "close-to", "", "[optional][YYYY-MM-DD] Only include announcements closed on or before this date")
+}
+
+func setHistoricOptions() (usajobs.HistoricJOAOptions, error) {
+	opt := usajobs.HistoricJOAOptions{
+		HiringAgencyCodes:      HiringAgencyCodes,
+		HiringDepartmentCodes:  HiringDepartmentCodes,
+		PositionSeries:         PositionSeries,
+		AnnouncementNumbers:    AnnouncementNumbers,
+		USAJOBSControlNumbers:  USAJOBSControlNumbers,
+		StartPositionOpenDate:  StartPositionOpenDate,
+		EndPositionOpenDate:    EndPositionOpenDate,
+		StartPositionCloseDate: StartPositionCloseDate,
+		EndPositionCloseDate:   EndPositionCloseDate,
+	}
+
+	for _, d := range []string{StartPositionOpenDate, EndPositionOpenDate, StartPositionCloseDate, EndPositionCloseDate} {
+		if d == "" {
+			continue
+		}
+
+		_, err := time.Parse(time.DateOnly, d)
+		if err != nil {
+			return opt, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", d)
+		}
+	}
+
+	return opt, nil
+}
+
+func executeHistoric(opt *usajobs.HistoricJOAOptions) error {
+
+	var err error
+	err = initClient(false)
+	if err != nil {
+		return err
+	}
+
+	// stream each page as it arrives rather than waiting for every page
+	if display == "ndjson" {
+		return Client.HistoricJOA.Pages(opt, func(page *usajobs.HistoricJOAResponse) error {
+			return displayNDJSON(page.Data)
+		})
+	}
+
+	data, err := Client.HistoricJOA.All(opt)
+	if err != nil {
+		return err
+	}
+
+	if format != "" {
+		return displayTemplate(data)
+	}
+
+	headersSummary := []string{"CONTROL_NUMBER", "AGENCY", "JOB_TITLE", "OPEN_DATE", "CLOSE_DATE"}
+	var dataSummary [][]string
+	for _, item := range data {
+		dataSummary = append(dataSummary, []string{
+			strconv.FormatInt(item.USAJOBSControlNumber, 10),
+			addNewLines(item.HiringAgencyName, 20),
+			addNewLines(item.PositionTitle, 30),
+			item.PositionOpenDate,
+			item.PositionCloseDate,
+		})
+	}
+
+	headersDetails := []string{"CONTROL_NUMBER", "ANNOUNCEMENT_NUMBER", "DEPARTMENT", "AGENCY", "JOB_TITLE",
+		"SERIES", "PAY_SCALE", "MIN_GRADE", "MAX_GRADE", "MIN_SALARY", "MAX_SALARY", "WORK_SCHEDULE",
+		"APPOINTMENT_TYPE", "HIRING_PATHS", "LOCATIONS", "OPEN_DATE", "CLOSE_DATE", "STATUS"}
+	var dataDetails [][]string
+	for _, item := range data {
+		var series, paths, locations []string
+		for _, c := range item.JobCategories {
+			series = append(series, c.Series)
+		}
+		for _, p := range item.HiringPaths {
+			paths = append(paths, p.HiringPath)
+		}
+		for _, l := range item.PositionLocations {
+			locations = append(locations, l.PositionLocationCity+", "+l.PositionLocationState)
+		}
+
+		dataDetails = append(dataDetails, []string{
+			strconv.FormatInt(item.USAJOBSControlNumber, 10),
+			item.AnnouncementNumber,
+			item.HiringDepartmentName,
+			item.HiringAgencyName,
+			item.PositionTitle,
+			strings.Join(series, ";"),
+			item.PayScale,
+			item.MinimumGrade,
+			item.MaximumGrade,
+			strconv.FormatFloat(item.MinimumSalary, 'f', 2, 64),
+			strconv.FormatFloat(item.MaximumSalary, 'f', 2, 64),
+			item.WorkSchedule,
+			item.AppointmentType,
+			strings.Join(paths, ";"),
+			strings.Join(locations, ";"),
+			item.PositionOpenDate,
+			item.PositionCloseDate,
+			item.PositionOpeningStatus,
+		})
+	}
+
+	switch display {
+	case "summary":
+		err = displayTable(headersSummary, dataSummary)
+		if err != nil {
+			return err
+		}
+	case "detail":
+		err = displayTable(headersDetails, dataDetails)
+		if err != nil {
+			return err
+		}
+	case "json":
+		if data == nil {
+			data = []usajobs.HistoricJOA{}
+		}
+		err = displayJSON(data)
+		if err != nil {
+			return err
+		}
+	case "csv":
+		err = displayCSV(headersDetails, dataDetails)
+		if err != nil {
+			return err
+		}
+	default:
+		err = displayTable(headersSummary, dataSummary)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+)
+
+func TestHistoric(t *testing.T) {
+	page1, err := os.ReadFile("../../testdata/historicjoa-page1-testdata.json")
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	page2, err := os.ReadFile("../../testdata/historicjoa-page2-testdata.json")
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	// Create a mock server that pages through the JSON data
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Log(r.URL.String())
+
+		if !strings.HasPrefix(r.URL.Path, "/historicjoa") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if r.URL.Query().Get("continuationtoken") != "" {
+			w.Write(page2)
+			return
+		}
+		w.Write(page1)
+	}))
+	defer mockServer.Close()
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+
+	Client, err = usajobs.NewClient("test", "test")
+	if err != nil {
+		panic(err.Error())
+	}
+
+	Client.BaseURL = u
+
+	opt := usajobs.HistoricJOAOptions{
+		PositionSeries: []string{"2210"},
+	}
+
+	for _, d := range []string{"summary", "detail", "csv", "json"} {
+		display = d
+		err = executeHistoric(&opt)
+		if err != nil {
+			t.Fatalf("failed to execute with display %s: %v", d, err.Error())
+		}
+	}
+	defer func() { display = "summary" }()
+
+	// ndjson writes one announcement per line across both pages
+	display = "ndjson"
+	out, err := captureStdout(t, func() error { return executeHistoric(&opt) })
+	if err != nil {
+		t.Fatalf("failed to execute with display ndjson: %v", err.Error())
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 ndjson records, got %d:\n%s", len(lines), out)
+	}
+
+	for _, line := range lines {
+		var joa usajobs.HistoricJOA
+		err = json.Unmarshal([]byte(line), &joa)
+		if err != nil || joa.USAJOBSControlNumber == 0 {
+			t.Errorf("invalid ndjson record %q: %v", line, err)
+		}
+	}
+}
+
+func TestSetHistoricOpts(t *testing.T) {
+	defer func() {
+		PositionSeries, StartPositionOpenDate = nil, ""
+	}()
+
+	PositionSeries = []string{"2210"}
+	StartPositionOpenDate = "2023-01-01"
+	opt, err := setHistoricOptions()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if opt.StartPositionOpenDate != "2023-01-01" || opt.PositionSeries[0] != "2210" {
+		t.Fatalf("expected options to be set from flags, got %+v", opt)
+	}
+
+	StartPositionOpenDate = "01/01/2023"
+	_, err = setHistoricOptions()
+	if err == nil {
+		t.Fatal("expected error for invalid date, got nil")
+	}
+}
@@ -0,0 +1,220 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JeffRDay/go-usajobs/archive"
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// historyChangeWidth is the most characters of a changed value the summary
+// timeline shows.
+const historyChangeWidth = 60
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history <MatchedObjectID|PositionID>",
+	Short: "Show how an archived posting was amended over time",
+	Long: `
+Show a timeline of each version of an archived posting, when it was first seen, and the
+fields that changed from the version before it: close dates extended, grades changed,
+locations added and removed. Postings are archived with 'usajobs archive add' and looked
+up by the usajobs control number (MatchedObjectID) or position id.
+
+--display=detail shows the full old and new values of each change, and csv, json and
+ndjson include them as well.
+
+Example Usage:
+
+    usajobs history 800000001
+    usajobs history ICE-24-12345-MP --display=detail
+
+    `,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := executeHistory(args[0])
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to execute history command")
+		}
+	},
+}
+
+// historyOutput is the json output of the history command, the latest
+// version of a posting followed by its history.
+type historyOutput struct {
+	archive.Posting
+	History []archive.Amendment `json:"history"`
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.Flags().StringVar(&archivePath, "archive", "", "[optional] archive file (default ~/.config/usajobs/archive.db)")
+}
+
+func executeHistory(id string) error {
+	a, err := openArchive()
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	p, err := archivedPosting(a, id)
+	if err != nil {
+		return err
+	}
+
+	history, err := a.History(p.MatchedObjectID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case format != "":
+		return displayTemplate(history)
+	case display == "json":
+		return displayJSON(historyOutput{Posting: p, History: history})
+	case display == "ndjson":
+		return displayNDJSON(history)
+	}
+
+	// the summary is a timeline of the changes, unless --columns picks
+	// from the full values of each change
+	timeline := display == "summary" && len(columns) == 0
+
+	headers := []string{"VERSION", "SEEN_AT", "FIELD", "OLD", "NEW", "ADDED", "REMOVED"}
+	if timeline {
+		headers = []string{"VERSION", "SEEN", "FIELD", "CHANGE"}
+	}
+
+	var data [][]string
+	for _, h := range history {
+		version := strconv.Itoa(h.Version)
+		seen := h.SeenAt.Format(time.RFC3339)
+		if timeline {
+			seen = h.SeenAt.Local().Format("2006-01-02 15:04")
+		}
+
+		if len(h.Changes) == 0 {
+			row := []string{version, seen, "", "", "", "", ""}
+			if timeline {
+				row = []string{version, seen, "", "first seen"}
+			}
+			data = append(data, row)
+			continue
+		}
+
+		for _, c := range h.Changes {
+			if timeline {
+				data = append(data, []string{version, seen, c.Field, addNewLines(historyChange(c), historyChangeWidth)})
+				continue
+			}
+
+			data = append(data, []string{
+				version,
+				seen,
+				c.Field,
+				c.Old,
+				c.New,
+				strings.Join(c.Added, usajobs.FieldSeparator),
+				strings.Join(c.Removed, usajobs.FieldSeparator),
+			})
+		}
+	}
+
+	switch display {
+	case "csv":
+		return displayCSV(headers, data)
+	case "detail":
+		for i, row := range data {
+			for j, value := range row {
+				data[i][j] = wrapField(value, 0)
+			}
+		}
+		return displayTable(headers, data)
+	}
+
+	if timeline {
+		fmt.Println(joinNonEmpty(" · ", p.Item.MatchedObjectDescriptor.PositionTitle, p.Item.MatchedObjectDescriptor.PositionID, p.MatchedObjectID))
+		fmt.Printf("first seen %s, last seen %s, %d versions\n\n",
+			p.FirstSeen.Local().Format("2006-01-02 15:04"), p.LastSeen.Local().Format("2006-01-02 15:04"), p.Versions)
+	}
+	return displayTable(headers, data)
+}
+
+// historyChange summarizes a change for the timeline, listing the values
+// added to and removed from list fields and shortening long values.
+func historyChange(c archive.Change) string {
+	if len(c.Added) > 0 || len(c.Removed) > 0 {
+		var s []string
+		for _, v := range c.Added {
+			s = append(s, "+ "+templateTruncate(historyChangeWidth, v))
+		}
+		for _, v := range c.Removed {
+			s = append(s, "- "+templateTruncate(historyChangeWidth, v))
+		}
+		return strings.Join(s, "\n")
+	}
+
+	old, updated := c.Old, c.New
+	if old == "" {
+		old = "(none)"
+	}
+	if updated == "" {
+		updated = "(none)"
+	}
+	return templateTruncate(historyChangeWidth, old) + " → " + templateTruncate(historyChangeWidth, updated)
+}
+
+// archivedPosting returns the archived posting with the MatchedObjectID or
+// position id id.
+func archivedPosting(a *archive.Archive, id string) (archive.Posting, error) {
+	p, err := a.Get(id)
+	if !errors.Is(err, archive.ErrNotFound) {
+		return p, err
+	}
+
+	var matches []archive.Posting
+	err = a.Each(func(p archive.Posting) error {
+		if strings.EqualFold(p.Item.MatchedObjectDescriptor.PositionID, strings.TrimSpace(id)) {
+			matches = append(matches, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return archive.Posting{}, err
+	}
+
+	switch len(matches) {
+	case 0:
+		return archive.Posting{}, fmt.Errorf("%s: %w", id, archive.ErrNotFound)
+	case 1:
+		return matches[0], nil
+	}
+
+	var ids []string
+	for _, m := range matches {
+		ids = append(ids, m.MatchedObjectID)
+	}
+	return archive.Posting{}, fmt.Errorf("position id %s matches more than one posting, use one of the MatchedObjectIDs: %s", id, strings.Join(ids, ", "))
+}
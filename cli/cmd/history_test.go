@@ -0,0 +1,127 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JeffRDay/go-usajobs/archive"
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+)
+
+func TestHistory(t *testing.T) {
+	data, err := os.ReadFile(searchTestDataPath)
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	var full usajobs.SearchResponse
+	err = json.Unmarshal(data, &full)
+	if err != nil {
+		t.Fatalf("could not decode test data: %v", err)
+	}
+	items := full.SearchResult.SearchResultItems
+
+	archivePath = filepath.Join(t.TempDir(), "archive.db")
+	defer func() {
+		display = "summary"
+		archivePath = ""
+	}()
+
+	a, err := archive.Open(archivePath)
+	if err != nil {
+		t.Fatalf("could not open archive: %v", err)
+	}
+
+	first := time.Date(2024, 7, 6, 12, 0, 0, 0, time.UTC)
+	_, err = a.Add(items, first)
+	if err != nil {
+		t.Fatalf("could not add postings: %v", err)
+	}
+
+	items[1].MatchedObjectDescriptor.ApplicationCloseDate = "2024-08-30T23:59:59.9970"
+	items[1].MatchedObjectDescriptor.PositionLocation = append(items[1].MatchedObjectDescriptor.PositionLocation, usajobs.PositionLocation{
+		LocationName: "Fort Liberty, North Carolina",
+	})
+	_, err = a.Add(items, first.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("could not add postings: %v", err)
+	}
+	a.Close()
+
+	history := func(id string) string {
+		t.Helper()
+
+		out, err := captureStdout(t, func() error { return executeHistory(id) })
+		if err != nil {
+			t.Fatalf("failed to execute: %v", err)
+		}
+		return out
+	}
+
+	display = "json"
+	var got historyOutput
+	err = json.Unmarshal([]byte(history("ASF-24-0042")), &got)
+	if err != nil {
+		t.Fatalf("could not decode history: %v", err)
+	}
+	if got.MatchedObjectID != "800000002" || got.Versions != 2 || len(got.History) != 2 {
+		t.Fatalf("unexpected history: %+v", got)
+	}
+	if changes := got.History[1].Changes; len(changes) != 2 || changes[0].Field != "LOCATIONS" || changes[1].Field != "CLOSE_DATE" {
+		t.Errorf("expected changed locations and close date, got %+v", changes)
+	}
+
+	display = "csv"
+	records, err := csv.NewReader(strings.NewReader(history("800000002"))).ReadAll()
+	if err != nil {
+		t.Fatalf("could not read csv: %v", err)
+	}
+	want := [][]string{
+		{"VERSION", "SEEN_AT", "FIELD", "OLD", "NEW", "ADDED", "REMOVED"},
+		{"1", "2024-07-06T12:00:00Z", "", "", "", "", ""},
+		{"2", "2024-07-07T12:00:00Z", "LOCATIONS", "Austin, Texas", "Austin, Texas; Fort Liberty, North Carolina", "Fort Liberty, North Carolina", ""},
+		{"2", "2024-07-07T12:00:00Z", "CLOSE_DATE", "2024-07-26T23:59:59.9970", "2024-08-30T23:59:59.9970", "", ""},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("got %d csv rows, want %d:\n%v", len(records), len(want), records)
+	}
+	for i := range want {
+		if strings.Join(records[i], "|") != strings.Join(want[i], "|") {
+			t.Errorf("row %d: got %q, want %q", i, records[i], want[i])
+		}
+	}
+
+	display = "summary"
+	out := history("800000002")
+	for _, s := range []string{"Software Engineer · ASF-24-0042 · 800000002", "2 versions", "first seen", "+ Fort Liberty, North Carolina", "2024-07-26T23:59:59.9970 → 2024-08-30T23:59:59.9970"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("expected %q in the timeline:\n%s", s, out)
+		}
+	}
+
+	_, err = captureStdout(t, func() error { return executeHistory("missing") })
+	if !errors.Is(err, archive.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
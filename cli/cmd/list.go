@@ -16,26 +16,266 @@ limitations under the License.
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
 
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
 
 // listCmd represents the list command
 var listCmd = &cobra.Command{
-	Use:   "list",
-	Short: "A brief description of your command",
-	Long: `A longer description that spans multiple lines and likely contains examples
-and usage of using your command. For example:
-
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
+	Use:   "list [codelist]",
+	Short: "lists the codelists tracked by usajobs or the values of one codelist",
+	Long: `
+lists the codelists tracked by usajobs, with the number of values in each and when
+usajobs generated it, or the values of a single codelist. Codelist values can be
+used to refine job searches (ex., occupationalseries for --job-category-code).
+
+Values usajobs has disabled are hidden unless --include-disabled is set. Large
+codelists can be narrowed with --filter, which matches the code or value, and
+--parent, which selects the children of a value in hierarchical codelists (ex.,
+the subelements of an agency or the subdivisions of a country).
+
+The summary display shows the code and value of each entry. The detail and csv
+displays add the fields specific to a codelist (ex., PARENT_CODE for
+agencysubelements), and --format templates can use them with {{.Fields.ParentCode}}.
+
+Example:
+usajobs list
+usajobs list academichonors
+usajobs list agencysubelements --display=csv --columns=code,value,parent_code
+usajobs list countrysubdivisions --parent=US
+usajobs list languagecodes --filter='^(en|es)' --regex --limit=10
+usajobs list occupationalseries --filter=information --modified-since=2023-01-01
+
+Output:
+┌─────────────────┬─────────────────┐
+│ CODE            │ VALUE           │
+├─────────────────┼─────────────────┤
+│ Cum Laude       │ Cum Laude       │
+├─────────────────┼─────────────────┤
+│ Magna Cum Laude │ Magna Cum Laude │
+├─────────────────┼─────────────────┤
+│ Summa Cum Laude │ Summa Cum Laude │
+└─────────────────┴─────────────────┘
+`,
+	Args:      validateListArgs,
+	ValidArgs: usajobs.CodeListNames,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("list called")
+		var err error
+		if len(args) == 0 {
+			err = executeListCatalog()
+		} else {
+			err = executeList(args[0])
+		}
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to execute list command")
+		}
 	},
 }
 
+var (
+	listFilterText      string
+	listRegex           bool
+	listParent          string
+	listIncludeDisabled bool
+	listModifiedSince   string
+	listLimit           int
+)
+
 func init() {
 	rootCmd.AddCommand(listCmd)
+
+	listCmd.Flags().StringVar(&listFilterText, "filter", "", "[optional] show values whose code or value contains the text, ignoring case")
+	listCmd.Flags().BoolVar(&listRegex, "regex", false, "[optional] treat --filter as a regular expression")
+	listCmd.Flags().StringVar(&listParent, "parent", "", "[optional] show values whose parent is the code (ex., AF for agencysubelements, US for countrysubdivisions)")
+	listCmd.Flags().BoolVar(&listIncludeDisabled, "include-disabled", false, "[optional] show values usajobs has disabled")
+	listCmd.Flags().StringVar(&listModifiedSince, "modified-since", "", "[optional] show values modified on or after the date (ex., 2023-01-02)")
+	listCmd.Flags().IntVar(&listLimit, "limit", 0, "[optional] maximum number of values to show")
+}
+
+// listFilter returns the codelist filter configured by the list flags.
+func listFilter() (usajobs.CodeListFilter, error) {
+	f := usajobs.CodeListFilter{
+		Parent:          listParent,
+		IncludeDisabled: listIncludeDisabled,
+		Limit:           listLimit,
+	}
+
+	if listLimit < 0 {
+		return f, errors.New("--limit must not be negative")
+	}
+
+	if listRegex {
+		pattern, err := regexp.Compile(listFilterText)
+		if err != nil {
+			return f, fmt.Errorf("invalid --filter: %w", err)
+		}
+		f.Pattern = pattern
+	} else {
+		f.Contains = listFilterText
+	}
+
+	if listModifiedSince != "" {
+		var err error
+		f.ModifiedSince, err = time.Parse(time.DateOnly, listModifiedSince)
+		if err != nil {
+			f.ModifiedSince, err = time.Parse(time.RFC3339, listModifiedSince)
+		}
+		if err != nil {
+			return f, fmt.Errorf("invalid --modified-since %q, expected a date like 2023-01-02", listModifiedSince)
+		}
+	}
+
+	return f, nil
+}
+
+func validateListArgs(cmd *cobra.Command, args []string) error {
+	err := cobra.MaximumNArgs(1)(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 1 && !slices.Contains(usajobs.CodeListNames, strings.ToLower(args[0])) {
+		return fmt.Errorf("unknown codelist %q, run 'usajobs list' to see every codelist", args[0])
+	}
+
+	return nil
+}
+
+func executeListCatalog() error {
+	var err error
+	err = initClient(false)
+	if err != nil {
+		return err
+	}
+
+	// codelists that could not be fetched are still listed, without a count,
+	// and the error is returned once the catalog is displayed
+	catalog, fetchErr := Client.CodeListCatalog()
+
+	switch {
+	case format != "":
+		err = displayTemplate(catalog)
+	case display == "json":
+		err = displayJSON(catalog)
+	case display == "ndjson":
+		err = displayNDJSON(catalog)
+	default:
+		headers := []string{"NAME", "ENTRIES", "DATE_GENERATED"}
+		var data [][]string
+		for _, c := range catalog {
+			count := ""
+			if c.DateGenerated != "" {
+				count = strconv.Itoa(c.Count)
+			}
+			data = append(data, []string{c.Name, count, c.DateGenerated})
+		}
+
+		if display == "csv" {
+			err = displayCSV(headers, data)
+		} else {
+			err = displayTable(headers, data)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return fetchErr
+}
+
+func executeList(name string) error {
+	filter, err := listFilter()
+	if err != nil {
+		return err
+	}
+
+	err = initClient(false)
+	if err != nil {
+		return err
+	}
+
+	r, data, err := Client.CodeList(name)
+	if err != nil {
+		return err
+	}
+
+	if r.StatusCode != http.StatusOK {
+		return errors.New("bad response from usajobs: " + r.Status)
+	}
+
+	data = data.Filter(filter)
+
+	switch {
+	case format != "":
+		return displayTemplate(data.Values())
+	case display == "json":
+		return displayJSON(data)
+	case display == "ndjson":
+		return displayNDJSON(data.Values())
+	}
+
+	headersSummary := []string{"CODE", "VALUE"}
+	var dataSummary [][]string
+	for _, item := range data.CodeList {
+		for _, i := range item.ValidValue {
+			dataSummary = append(dataSummary, []string{i.Code, i.Value})
+		}
+	}
+
+	fields := data.FieldNames()
+	headersDetails := []string{"ID", "CODE", "VALUE"}
+	for _, f := range fields {
+		headersDetails = append(headersDetails, columnName(f))
+	}
+	headersDetails = append(headersDetails, "LAST_MODIFIED", "IS_DISABLED", "DATE_GENERATED")
+
+	var dataDetails [][]string
+	for _, item := range data.CodeList {
+		for _, i := range item.ValidValue {
+			row := []string{item.ID, i.Code, i.Value}
+			for _, f := range fields {
+				row = append(row, i.Fields[f])
+			}
+			row = append(row, i.LastModified, i.IsDisabled, data.DateGenerated)
+			dataDetails = append(dataDetails, row)
+		}
+	}
+
+	switch display {
+	case "detail":
+		return displayTable(headersDetails, dataDetails)
+	case "csv":
+		return displayCSV(headersDetails, dataDetails)
+	default:
+		// every column can be selected, not only the summary columns
+		if len(columns) > 0 {
+			return displayTable(headersDetails, dataDetails)
+		}
+		return displayTable(headersSummary, dataSummary)
+	}
+}
+
+// columnName converts a usajobs field name to a column name (ex., ParentCode
+// to PARENT_CODE).
+func columnName(field string) string {
+	var b strings.Builder
+	r := []rune(field)
+	for i, c := range r {
+		if i > 0 && unicode.IsUpper(c) && (unicode.IsLower(r[i-1]) || (i+1 < len(r) && unicode.IsLower(r[i+1]))) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(c))
+	}
+	return b.String()
 }
@@ -0,0 +1,310 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+)
+
+// newCodeListServer returns a mock server that serves every codelist from
+// the testdata directory.
+func newCodeListServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, ok := strings.CutPrefix(r.URL.Path, "/codelist/")
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		data, err := os.ReadFile("../../testdata/" + name + "-testdata.json")
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	}))
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+
+	Client, err = usajobs.NewClient("test", "test")
+	if err != nil {
+		t.Fatalf("could not create new usajobs client: %v", err)
+	}
+	Client.BaseURL = u
+
+	return mockServer
+}
+
+func TestList(t *testing.T) {
+	mockServer := newCodeListServer(t)
+	defer mockServer.Close()
+	defer func() { display = "summary" }()
+
+	for _, name := range usajobs.CodeListNames {
+		t.Run(name, func(t *testing.T) {
+			display = "summary"
+			err := executeList(name)
+			if err != nil {
+				t.Fatalf("failed to execute: %v", err.Error())
+			}
+
+			display = "json"
+			out, err := captureStdout(t, func() error { return executeList(name) })
+			if err != nil {
+				t.Fatalf("failed to execute with display json: %v", err.Error())
+			}
+
+			var resp usajobs.CodeListResponse
+			err = json.Unmarshal([]byte(out), &resp)
+			if err != nil || len(resp.Values()) == 0 {
+				t.Fatalf("invalid json output: %v\n%s", err, out)
+			}
+
+			display = "ndjson"
+			out, err = captureStdout(t, func() error { return executeList(name) })
+			if err != nil {
+				t.Fatalf("failed to execute with display ndjson: %v", err.Error())
+			}
+
+			lines := strings.Split(strings.TrimSpace(out), "\n")
+			if len(lines) != len(resp.Values()) || !strings.HasPrefix(lines[0], "{") {
+				t.Errorf("expected one ndjson record per value, got %d lines", len(lines))
+			}
+
+			display = "csv"
+			out, err = captureStdout(t, func() error { return executeList(name) })
+			if err != nil {
+				t.Fatalf("failed to execute with display csv: %v", err.Error())
+			}
+
+			records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+			if err != nil {
+				t.Fatalf("invalid csv output: %v", err)
+			}
+			if len(records) != len(resp.Values())+1 {
+				t.Errorf("expected a header and %d rows, got %d records", len(resp.Values()), len(records))
+			}
+		})
+	}
+}
+
+func TestListFields(t *testing.T) {
+	mockServer := newCodeListServer(t)
+	defer mockServer.Close()
+	defer func() {
+		display = "summary"
+		columns = nil
+		format = ""
+	}()
+
+	display = "csv"
+	out, err := captureStdout(t, func() error { return executeList("agencysubelements") })
+	if err != nil {
+		t.Fatalf("failed to execute: %v", err.Error())
+	}
+
+	header, _, _ := strings.Cut(out, "\n")
+	if header != "ID,CODE,VALUE,ACRONYM,PARENT_CODE,LAST_MODIFIED,IS_DISABLED,DATE_GENERATED" {
+		t.Errorf("unexpected csv header: %s", header)
+	}
+
+	display = "summary"
+	columns = []string{"code", "parent-code"}
+	out, err = captureStdout(t, func() error { return executeList("agencysubelements") })
+	if err != nil {
+		t.Fatalf("failed to execute with columns: %v", err.Error())
+	}
+
+	header, _, _ = strings.Cut(out, "\n")
+	if !strings.Contains(header, "PARENT_CODE") || strings.Contains(header, "VALUE") {
+		t.Errorf("expected only the selected columns, got: %s", header)
+	}
+
+	columns = nil
+	format = `{{.Code}}={{.Fields.ParentCode}}`
+	out, err = captureStdout(t, func() error { return executeList("agencysubelements") })
+	if err != nil {
+		t.Fatalf("failed to execute with format: %v", err.Error())
+	}
+
+	if !strings.Contains(out, "AF1A=AF\n") {
+		t.Errorf("expected the parent code of AF1A in:\n%s", out)
+	}
+}
+
+func TestListCatalog(t *testing.T) {
+	mockServer := newCodeListServer(t)
+	defer mockServer.Close()
+	defer func() { display = "summary" }()
+
+	err := executeListCatalog()
+	if err != nil {
+		t.Fatalf("failed to execute: %v", err.Error())
+	}
+
+	display = "json"
+	out, err := captureStdout(t, executeListCatalog)
+	if err != nil {
+		t.Fatalf("failed to execute with display json: %v", err.Error())
+	}
+
+	var catalog []usajobs.CodeListSummary
+	err = json.Unmarshal([]byte(out), &catalog)
+	if err != nil {
+		t.Fatalf("invalid json output: %v\n%s", err, out)
+	}
+
+	if len(catalog) != len(usajobs.CodeListNames) {
+		t.Fatalf("expected %d codelists, got %d", len(usajobs.CodeListNames), len(catalog))
+	}
+
+	for _, c := range catalog {
+		if c.Count == 0 || c.DateGenerated == "" {
+			t.Errorf("expected entries and a date generated for %s, got %+v", c.Name, c)
+		}
+	}
+}
+
+func TestListArgs(t *testing.T) {
+	err := validateListArgs(listCmd, []string{"occupationalseries"})
+	if err != nil {
+		t.Errorf("expected occupationalseries to be valid, got %v", err)
+	}
+
+	err = validateListArgs(listCmd, []string{"occupations"})
+	if err == nil || !strings.Contains(err.Error(), "unknown codelist") {
+		t.Errorf("expected unknown codelist error, got %v", err)
+	}
+
+	err = validateListArgs(listCmd, []string{"payplans", "countries"})
+	if err == nil {
+		t.Error("expected error for more than one codelist, got nil")
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for field, want := range map[string]string{
+		"ParentCode":             "PARENT_CODE",
+		"CountrySubdivisionCode": "COUNTRY_SUBDIVISION_CODE",
+		"GeoLocCode":             "GEO_LOC_CODE",
+		"Acronym":                "ACRONYM",
+	} {
+		got := columnName(field)
+		if got != want {
+			t.Errorf("columnName(%q) = %q, want %q", field, got, want)
+		}
+	}
+}
+
+func TestListFilter(t *testing.T) {
+	mockServer := newCodeListServer(t)
+	defer mockServer.Close()
+	defer func() {
+		display = "summary"
+		listFilterText, listRegex, listParent = "", false, ""
+		listIncludeDisabled, listModifiedSince, listLimit = false, "", 0
+	}()
+
+	list := func(name string) []usajobs.CodeListValue {
+		t.Helper()
+
+		display = "json"
+		out, err := captureStdout(t, func() error { return executeList(name) })
+		if err != nil {
+			t.Fatalf("failed to execute: %v", err.Error())
+		}
+
+		var resp usajobs.CodeListResponse
+		err = json.Unmarshal([]byte(out), &resp)
+		if err != nil {
+			t.Fatalf("invalid json output: %v\n%s", err, out)
+		}
+		return resp.Values()
+	}
+
+	all := len(list("languagecodes"))
+	listIncludeDisabled = true
+	if len(list("languagecodes")) <= all {
+		t.Error("expected --include-disabled to show more languagecodes")
+	}
+
+	listIncludeDisabled = false
+	listParent = "US"
+	for _, v := range list("countrysubdivisions") {
+		if v.Fields["ParentCode"] != "US" {
+			t.Errorf("expected only subdivisions of the US, got %+v", v)
+		}
+	}
+
+	listParent = ""
+	listFilterText = "information technology"
+	values := list("occupationalseries")
+	if len(values) == 0 || values[0].Code != "2210" {
+		t.Errorf("expected 2210 to match, got %+v", values)
+	}
+
+	listFilterText, listRegex = "^22[0-9]{2}$", true
+	for _, v := range list("occupationalseries") {
+		if !strings.HasPrefix(v.Code, "22") {
+			t.Errorf("expected only 22xx series, got %+v", v)
+		}
+	}
+
+	listFilterText, listRegex = "", false
+	listModifiedSince = "2023-01-01"
+	for _, v := range list("occupationalseries") {
+		if v.LastModified < "2023-01-01" {
+			t.Errorf("expected values modified since 2023, got %+v", v)
+		}
+	}
+
+	listModifiedSince = ""
+	listLimit = 3
+	if n := len(list("countries")); n != 3 {
+		t.Errorf("expected 3 countries, got %d", n)
+	}
+
+	listLimit = 0
+	for _, bad := range []func(){
+		func() { listFilterText, listRegex = "(", true },
+		func() { listModifiedSince = "yesterday" },
+		func() { listLimit = -1 },
+	} {
+		listFilterText, listRegex, listModifiedSince, listLimit = "", false, "", 0
+		bad()
+		err := executeList("countries")
+		if err == nil {
+			t.Error("expected an error for invalid filter flags, got nil")
+		}
+	}
+}
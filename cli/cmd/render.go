@@ -0,0 +1,330 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/mattn/go-runewidth"
+	"golang.org/x/term"
+)
+
+const (
+	// defaultTerminalWidth is used when stdout is a terminal whose size
+	// cannot be read.
+	defaultTerminalWidth = 120
+	// minColumnWidth is the narrowest a column is shrunk to when fitting a
+	// table to the terminal.
+	minColumnWidth = 8
+)
+
+// plainOutput reports whether tables are printed without borders or wrapping,
+// one row per line, either because --no-border was set or because stdout is
+// not a terminal (ex., piped to another command).
+func plainOutput() bool {
+	return noBorder || !term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// terminalWidth returns the width tables are fit to. The COLUMNS environment
+// variable takes precedence over the detected size.
+func terminalWidth() int {
+	if c, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && c > 0 {
+		return c
+	}
+
+	w, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || w <= 0 {
+		return defaultTerminalWidth
+	}
+	return w
+}
+
+// wrapText word-wraps s so no line is wider than n terminal cells. Words
+// wider than n are broken on rune boundaries. Existing line breaks are kept.
+func wrapText(s string, n int) string {
+	if n <= 0 {
+		return s
+	}
+
+	var out []string
+	for _, line := range strings.Split(s, "\n") {
+		var current strings.Builder
+		width := 0
+
+		for _, word := range strings.Fields(line) {
+			w := runewidth.StringWidth(word)
+
+			if width > 0 && width+1+w > n {
+				out = append(out, current.String())
+				current.Reset()
+				width = 0
+			}
+
+			// break words that do not fit on a line of their own
+			for w > n {
+				if width > 0 {
+					out = append(out, current.String())
+					current.Reset()
+					width = 0
+				}
+				head := runewidth.Truncate(word, n, "")
+				if head == "" {
+					// a single rune wider than the line
+					_, size := firstRune(word)
+					head = word[:size]
+				}
+				out = append(out, head)
+				word = word[len(head):]
+				w = runewidth.StringWidth(word)
+			}
+
+			if word == "" {
+				continue
+			}
+
+			if width > 0 {
+				current.WriteByte(' ')
+				width++
+			}
+			current.WriteString(word)
+			width += w
+		}
+
+		out = append(out, current.String())
+	}
+
+	return strings.Join(out, "\n")
+}
+
+func firstRune(s string) (rune, int) {
+	for i, r := range s {
+		if i > 0 {
+			return r, i
+		}
+	}
+	return 0, len(s)
+}
+
+// selectColumns returns only the --columns of a table, in the order they were
+// requested. Column names are matched case-insensitively and '-' may be used
+// in place of '_'.
+func selectColumns(headers []string, data [][]string) ([]string, [][]string, error) {
+	if len(columns) == 0 {
+		return headers, data, nil
+	}
+
+	var index []int
+	for _, c := range columns {
+		name := strings.ReplaceAll(strings.TrimSpace(c), "-", "_")
+
+		found := -1
+		for i, h := range headers {
+			if strings.EqualFold(h, name) {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			return nil, nil, fmt.Errorf("unknown column %q, valid columns are: %s", c, strings.Join(headers, ", "))
+		}
+		index = append(index, found)
+	}
+
+	selected := make([]string, 0, len(index))
+	for _, i := range index {
+		selected = append(selected, headers[i])
+	}
+
+	rows := make([][]string, 0, len(data))
+	for _, row := range data {
+		r := make([]string, 0, len(index))
+		for _, i := range index {
+			if i < len(row) {
+				r = append(r, row[i])
+			} else {
+				r = append(r, "")
+			}
+		}
+		rows = append(rows, r)
+	}
+
+	return selected, rows, nil
+}
+
+// fitColumns returns the width of each column so the table fits in width
+// terminal cells, shrinking the widest columns first. overhead is the number
+// of cells used by borders and padding.
+func fitColumns(headers []string, data [][]string, width, overhead int) []int {
+	widths := make([]int, len(headers))
+	measure := func(i int, cell string) {
+		for _, line := range strings.Split(cell, "\n") {
+			widths[i] = max(widths[i], runewidth.StringWidth(line))
+		}
+	}
+
+	for i, h := range headers {
+		measure(i, h)
+	}
+	for _, row := range data {
+		for i := 0; i < len(row) && i < len(widths); i++ {
+			measure(i, row[i])
+		}
+	}
+
+	available := width - overhead
+	total := 0
+	for _, w := range widths {
+		total += w
+	}
+
+	for total > available {
+		widest := 0
+		for i, w := range widths {
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= minColumnWidth {
+			break
+		}
+		widths[widest]--
+		total--
+	}
+
+	return widths
+}
+
+// renderTable prints a bordered table fit to the terminal, or plain aligned
+// text when plainOutput is true.
+func renderTable(headers []string, data [][]string) error {
+	return renderHighlightedTable(headers, data, nil)
+}
+
+// renderHighlightedTable is renderTable with the rows at the highlighted
+// indexes of data drawn in bold color. Plain text is not highlighted.
+func renderHighlightedTable(headers []string, data [][]string, highlight map[int]bool) error {
+	if plainOutput() {
+		return renderPlain(headers, data)
+	}
+
+	// each column has one cell of padding on both sides and a border after
+	// it, plus the border before the first column
+	widths := fitColumns(headers, data, terminalWidth(), 3*len(headers)+1)
+
+	rows := make([][]string, 0, len(data))
+	for _, row := range data {
+		r := make([]string, len(row))
+		for i, cell := range row {
+			if i < len(widths) {
+				cell = wrapText(cell, widths[i])
+			}
+			r[i] = cell
+		}
+		rows = append(rows, r)
+	}
+
+	t := table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderRow(true).
+		BorderColumn(true).
+		BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("99"))).
+		Headers(headers...).
+		Rows(rows...).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			// row 0 is the header
+			style := lipgloss.NewStyle().Padding(0, 1)
+			if row > 0 && highlight[row-1] {
+				style = style.Bold(true).Foreground(lipgloss.Color("214"))
+			}
+			return style
+		})
+
+	fmt.Println(t.Render())
+	return nil
+}
+
+// renderPlain prints the header and each row on a single line with columns
+// aligned by spaces, which is easier to read with a screen reader and to
+// process with tools like grep and awk.
+func renderPlain(headers []string, data [][]string) error {
+	flatten := func(row []string) []string {
+		r := make([]string, len(row))
+		for i, cell := range row {
+			r[i] = strings.Join(strings.Fields(cell), " ")
+		}
+		return r
+	}
+
+	rows := [][]string{flatten(headers)}
+	for _, row := range data {
+		rows = append(rows, flatten(row))
+	}
+
+	widths := make([]int, len(headers))
+	for _, row := range rows {
+		for i := 0; i < len(row) && i < len(widths); i++ {
+			widths[i] = max(widths[i], runewidth.StringWidth(row[i]))
+		}
+	}
+
+	var b strings.Builder
+	for _, row := range rows {
+		var line strings.Builder
+		for i, cell := range row {
+			if i > 0 {
+				line.WriteString("  ")
+			}
+			line.WriteString(cell)
+			if i < len(row)-1 && i < len(widths) {
+				line.WriteString(strings.Repeat(" ", widths[i]-runewidth.StringWidth(cell)))
+			}
+		}
+		b.WriteString(strings.TrimRight(line.String(), " "))
+		b.WriteByte('\n')
+	}
+
+	_, err := os.Stdout.WriteString(b.String())
+	return err
+}
+
+// displayCSV writes the --columns of a table to stdout as CSV.
+func displayCSV(headers []string, data [][]string) error {
+	headers, data, err := selectColumns(headers, data)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(os.Stdout)
+
+	err = writer.Write(headers)
+	if err != nil {
+		return err
+	}
+
+	err = writer.WriteAll(data)
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
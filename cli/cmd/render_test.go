@@ -0,0 +1,112 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+)
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		in   string
+		n    int
+		want string
+	}{
+		{"Department of Homeland Security", 12, "Department\nof Homeland\nSecurity"},
+		{"Défense nationale", 8, "Défense\nnational\ne"},
+		{"国防部国防部", 5, "国防\n部国\n防部"},
+		{"https://www.usajobs.gov/job", 10, "https://ww\nw.usajobs.\ngov/job"},
+		{"line one\nline two", 20, "line one\nline two"},
+		{"unchanged", 0, "unchanged"},
+	}
+
+	for _, tt := range tests {
+		got := wrapText(tt.in, tt.n)
+		if got != tt.want {
+			t.Errorf("wrapText(%q, %d): expected %q, got %q", tt.in, tt.n, tt.want, got)
+		}
+
+		for _, line := range strings.Split(got, "\n") {
+			if !utf8.ValidString(line) {
+				t.Errorf("wrapText(%q, %d) split a multibyte character: %q", tt.in, tt.n, line)
+			}
+			if tt.n > 0 && runewidth.StringWidth(line) > tt.n {
+				t.Errorf("wrapText(%q, %d) line %q is wider than %d", tt.in, tt.n, line, tt.n)
+			}
+		}
+	}
+}
+
+func TestSelectColumns(t *testing.T) {
+	defer func() { columns = nil }()
+
+	headers := []string{"CODE", "VALUE", "LAST_MODIFIED"}
+	data := [][]string{{"1", "one", "2024"}, {"2", "two", "2023"}}
+
+	columns = []string{"last-modified", "code"}
+	h, d, err := selectColumns(headers, data)
+	if err != nil {
+		t.Fatalf("could not select columns: %v", err)
+	}
+
+	if !reflect.DeepEqual(h, []string{"LAST_MODIFIED", "CODE"}) || !reflect.DeepEqual(d[1], []string{"2023", "2"}) {
+		t.Errorf("unexpected selection %v %v", h, d)
+	}
+
+	columns = []string{"CODE", "NAME"}
+	_, _, err = selectColumns(headers, data)
+	if err == nil || !strings.Contains(err.Error(), "LAST_MODIFIED") {
+		t.Errorf("expected unknown column error listing valid columns, got %v", err)
+	}
+}
+
+func TestFitColumns(t *testing.T) {
+	headers := []string{"ID", "TITLE", "URL"}
+	data := [][]string{{"1", "IT Specialist", strings.Repeat("x", 100)}}
+
+	widths := fitColumns(headers, data, 60, 10)
+	if !reflect.DeepEqual(widths, []int{2, 13, 35}) {
+		t.Errorf("expected the widest column to shrink to fit, got %v", widths)
+	}
+
+	widths = fitColumns(headers, data, 200, 10)
+	if widths[2] != 100 {
+		t.Errorf("expected columns that fit to keep their width, got %v", widths)
+	}
+}
+
+func TestRenderPlain(t *testing.T) {
+	// output captured through a pipe is not a terminal, so tables are plain
+	out, err := captureStdout(t, func() error {
+		return displayTable([]string{"CODE", "VALUE"}, [][]string{
+			{"GS", addNewLines("General Schedule Pay Plan", 8)},
+			{"WG", "Wage Grade"},
+		})
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	want := "CODE  VALUE\nGS    General Schedule Pay Plan\nWG    Wage Grade\n"
+	if out != want {
+		t.Errorf("expected plain output %q, got %q", want, out)
+	}
+}
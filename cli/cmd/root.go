@@ -16,14 +16,12 @@ limitations under the License.
 package cmd
 
 import (
-	"fmt"
+	"encoding/json"
 	"os"
-	"strings"
 
 	usajobs "github.com/JeffRDay/go-usajobs/client"
-	"github.com/charmbracelet/lipgloss"
-	"github.com/charmbracelet/lipgloss/table"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -43,6 +41,32 @@ available to users of the website via the API.
 
 !note!: You must request an API Token from USAJobs to use this CLI. See Links below.
 
+Credentials:
+Every command that queries the usajobs search api (search, show, compare, stats, browse,
+watch, serve, and archive add without --from) requires a usajobs api token and the email
+address it was issued to. list, historic, history, and archive query, search and export
+do not. Credentials are read from --token and --user-agent, the USAJOBS_TOKEN and
+USAJOBS_USER_AGENT environment variables, or a profile saved with 'usajobs config set'.
+
+Completion:
+'usajobs completion bash|zsh|fish' prints a shell completion script. Coded flags such as
+--job-catagory and --clearance complete their codes with the names they stand for, from
+codelists cached in ~/.cache/usajobs/codelists for a week.
+
+Templates:
+--format prints each result with a Go text/template. Search results expose the job
+announcement and details fields directly (ex., {{.PositionTitle}}, {{.LowGrade}}). Codelist values expose Code,
+Value, LastModified, IsDisabled, and their codelist specific Fields (ex., {{.Fields.ParentCode}}), and
+the codelist catalog exposes Name, Count, and DateGenerated. Stats expose Postings, Remote, Telework,
+MedianGSGrade, the salaries, and Groups (ex., {{range $group, $buckets := .Groups}}{{$group}} {{len $buckets}} {{end}}). Templates saved as <name>.tmpl in --template-dir
+can be used by name (--format=name) or called with {{template "name" .}}.
+
+    join LIST SEP          join a list (ex., {{join .HiringPath ", "}})
+    truncate N TEXT        shorten text to N characters (ex., {{.PositionTitle | truncate 30}})
+    date LAYOUT DATE       reformat a date with a Go layout (ex., {{date "Jan 2, 2006" .ApplicationCloseDate}})
+    salary AMOUNT          format dollars, or a pay range (ex., {{salary .PositionRemuneration}})
+    codename LIST CODE     look up a code's name (ex., {{codename "payplans" "GS"}})
+
 Links:
 Obtain a USAJobs API Token here: https://developer.usajobs.gov/apirequest/
 You can view this project at: https://github.com/JeffRDay/go-usajobs 
@@ -70,37 +94,78 @@ func Execute() {
 
 // Global Variables
 var (
-	Client  *usajobs.Client
-	display string
+	Client      *usajobs.Client
+	display     string
+	format      string
+	templateDir string
+	columns     []string
+	noBorder    bool
+	userAgent   string
+	apiToken    string
+	profile     string
+	configFile  string
 )
 
 func init() {
-	rootCmd.PersistentFlags().StringVar(&display, "display", "summary", "[summary|detail|csv] type of output supported")
+	rootCmd.PersistentFlags().StringVar(&userAgent, "user-agent", "", "[optional] email address used when obtaining a usajobs api token, overrides "+envUserAgent+" and the profile")
+	rootCmd.PersistentFlags().StringVar(&apiToken, "token", "", "[optional] usajobs api token, overrides "+envToken+" and the profile")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "[optional] config profile to read credentials from, overrides "+envProfile+" and the current profile")
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "[optional] config file of credential profiles (default ~/.config/usajobs/config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&display, "display", "summary", "[summary|detail|csv|json|ndjson] type of output supported")
+	rootCmd.PersistentFlags().StringVar(&format, "format", "", "[optional] Go template printed for each result, or the name of a template in --template-dir, overrides --display (ex., '{{.PositionTitle}} closes {{date \"Jan 2\" .ApplicationCloseDate}}')")
+	rootCmd.PersistentFlags().StringSliceVar(&columns, "columns", []string{}, "[optional][Comma Separated List] columns to show, in order, for table and csv output (ex., JOB_TITLE,CLOSE_DATE)")
+	rootCmd.PersistentFlags().BoolVar(&noBorder, "no-border", false, "[optional] print tables as plain aligned text without borders, the default when output is not a terminal")
+	rootCmd.PersistentFlags().StringVar(&templateDir, "template-dir", "", "[optional] directory of named <name>.tmpl templates for --format (default ~/.config/usajobs/templates)")
+	rootCmd.SetGlobalNormalizationFunc(normalizeFlagName)
+	registerCompletions(rootCmd, map[string]completionFunc{
+		"profile": completeProfiles,
+		"display": completeValues("summary", "detail", "csv", "json", "ndjson"),
+	})
 }
 
+// normalizeFlagName accepts the correct spelling of flags whose names are
+// misspelled, so --job-category sets --job-catagory.
+func normalizeFlagName(f *pflag.FlagSet, name string) pflag.NormalizedName {
+	if name == "job-category" {
+		name = "job-catagory"
+	}
+	return pflag.NormalizedName(name)
+}
+
+// addNewLines word-wraps s to lines of at most n characters for display in a
+// table. Plain output is never wrapped.
 func addNewLines(s string, n int) string {
-	var result strings.Builder
-	for len(s) > n {
-		result.WriteString(s[:n] + "\n")
-		s = s[n:]
+	if plainOutput() {
+		return s
 	}
-	result.WriteString(s)
-	return result.String()
+	return wrapText(s, n)
 }
 
+// displayTable prints the --columns of a table, fit to the terminal.
 func displayTable(headers []string, data [][]string) error {
+	headers, data, err := selectColumns(headers, data)
+	if err != nil {
+		return err
+	}
+	return renderTable(headers, data)
+}
 
-	t := table.New().
-		Border(lipgloss.NormalBorder()).
-		BorderRow(true).
-		BorderColumn(true).
-		BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("99"))).
-		Headers(headers...).
-		Rows(data...).
-		StyleFunc(func(row, col int) lipgloss.Style {
-			return lipgloss.NewStyle().Padding(0, 1).MaxWidth(120)
-		})
-
-	fmt.Println(t.Render())
+// displayJSON writes v to stdout as indented JSON.
+func displayJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// displayNDJSON writes each record to stdout as JSON on its own line. It can
+// be called once per page of results to stream them.
+func displayNDJSON[T any](records []T) error {
+	enc := json.NewEncoder(os.Stdout)
+	for _, r := range records {
+		err := enc.Encode(r)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
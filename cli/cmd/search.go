@@ -16,14 +16,21 @@ limitations under the License.
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
 	"strings"
 
 	usajobs "github.com/JeffRDay/go-usajobs/client"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // searchCmd represents the search command
@@ -40,10 +47,59 @@ Example Usage:
     Search using a keyword and multiple locations:
     search --token=$TOKEN --user-agent=$EMAIL --keyword=army --location=Austin,Texas-Portland,Oregon
 
+    Search for jobs within 50 miles of a postal code, nearest first:
+    search --token=$TOKEN --user-agent=$EMAIL --keyword=software --near=78701 --within=50
+
+    Run several saved queries at once and list each posting only once:
+    search --token=$TOKEN --user-agent=$EMAIL --query=it.json --query=cyber.json
+
+    Export every field of the results to CSV, or only selected columns:
+    search --token=$TOKEN --user-agent=$EMAIL --keyword=cyber --display=csv > cyber.csv
+    search --token=$TOKEN --user-agent=$EMAIL --keyword=cyber --display=csv --columns=JOB_TITLE,LOCATIONS,MIN_SALARY,MAX_SALARY
+
+    Fetch every page of results, or stop once 1,200 postings have been fetched:
+    search --token=$TOKEN --user-agent=$EMAIL --keyword=nurse --all --display=csv > nurse.csv
+    search --token=$TOKEN --user-agent=$EMAIL --keyword=nurse --max-results=1200 --display=ndjson
+
+    Coded filters accept names as well as codes, resolved from the usajobs codelists:
+    search --token=$TOKEN --user-agent=$EMAIL --clearance="Top Secret" --hiring-path=veterans --job-category="Information Technology Management"
+
+    Print each result with a Go template, see 'usajobs --help' for the template functions:
+    search --token=$TOKEN --user-agent=$EMAIL --keyword=cyber --format='{{.PositionTitle | truncate 40}} closes {{date "Jan 2" .ApplicationCloseDate}}'
+
+    Without --all or --max-results only a single page is fetched, the first unless --page is
+    set. The page and the total number of results are logged as each page is fetched.
+
+    Repeated fields such as locations are joined with "; " in csv output.
+
+    Query files are JSON objects using the usajobs.SearchOptions field names and are
+    applied on top of any other flags, for example: {"Keyword": "cyber", "JobCategoryCode": ["2210"]}
+
     `,
 	Run: func(cmd *cobra.Command, args []string) {
-		opt := setSearchOptions()
-		err := executeSearch(&opt)
+		opt, err := setSearchOptions()
+		if err != nil {
+			log.Fatal().Err(err).Msg("invalid search options")
+		}
+
+		if len(QueryFiles) > 0 {
+			if SearchAll || MaxResults > 0 {
+				log.Fatal().Msg("--all and --max-results are not supported with --query")
+			}
+
+			opts, err := loadSearchQueries(opt, QueryFiles)
+			if err != nil {
+				log.Fatal().Err(err).Msg("invalid search query file")
+			}
+
+			err = executeMultiSearch(opts, QueryFiles)
+			if err != nil {
+				log.Fatal().Err(err).Msg("failed to execute search command")
+			}
+			return
+		}
+
+		err = executeSearch(&opt)
 		if err != nil {
 			log.Fatal().Err(err).Msg("failed to execute search command")
 		}
@@ -51,8 +107,6 @@ Example Usage:
 }
 
 var (
-	userAgent                 string
-	apiToken                  string
 	Keyword                   string
 	PositionTitle             string
 	RemunerationMinimumAmount string
@@ -65,9 +119,9 @@ var (
 	Organization              []string
 	PositionOfferingTypeCode  []string
 	TravelPercentage          int
-	PositionScheduleTypeCode  []int
+	PositionScheduleTypeCode  []string
 	RelocationIndicator       bool
-	SecurityClearanceRequired []int
+	SecurityClearanceRequired []string
 	SupervisoryStatus         string
 	DatePosted                int
 	JobGradeCode              []int
@@ -78,54 +132,87 @@ var (
 	WhoMayApply               string
 	Radius                    int
 	Fields                    string
+	SalaryBucket              []string
+	GradeBucket               []string
 	HiringPath                []string
+	MissionCriticalTags       []string
 	PositionSensitivity       []int
 	RemoteIndicator           bool
+	Near                      string
+	Within                    float64
+	QueryFiles                []string
+	SearchAll                 bool
+	MaxResults                int
 )
 
+// searchFlags are the search flags, shared by every command that runs a
+// search. They are created before any init function runs so commands can add
+// them regardless of the order their files are initialized in.
+var searchFlags = newSearchFlags()
+
+func newSearchFlags() *pflag.FlagSet {
+	f := pflag.NewFlagSet("search", pflag.ContinueOnError)
+	f.StringVarP(&Keyword, "keyword", "k", "", "[optional] Words used to refine search (ex., Army Software Factory)")
+	f.StringVar(&PositionTitle, "title", "", "[optional] filter jobs by position title (ex., IT Specialist)")
+	f.StringVar(&RemunerationMinimumAmount, "min-salary", "", "[optional] Sets the lower limit for filtering jobs by salary (ex., 80,000)")
+	f.StringVar(&RemunerationMaximumAmount, "max-salary", "", "[optional] Sets the upper limit for filtering jobs by salary (ex., 120,000)")
+	f.StringVar(&PayGradeLow, "min-grade", "", "[optional] Sets the lower limit for filtering jobs by pay grade (ex, GS14)")
+	f.StringVar(&PayGradeHigh, "max-grade", "", "[optional] Sets the upper limit for filtering jobs by pay grade (ex., GS15)")
+	f.StringSliceVarP(&JobCategoryCode, "job-catagory", "j", []string{""}, "[optional] Comma separated list of job codes or names (ex., 2210,\"Contracting\")")
+	f.StringVar(&LocationName, "location", "", "[optional] dash (-) separated list of <city,state> (ex., Austin,Texas-Portland,Oregon)")
+	f.StringSliceVar(&Organization, "organization", []string{""}, "[optional] Comma separated list of organizations (ex., Immigration and Customs Enforcement,Office of Chief Information Officer)")
+	f.StringSliceVar(&PostingChannel, "posting-channel", []string{}, "[optional][Comma Separated List] Filter jobs by the channel they were posted through (ex., USAJOBS)")
+	f.StringSliceVar(&PositionOfferingTypeCode, "position-type", []string{}, "[optional][Comma Separated List] Filter jobs by position type codes or names (ex., 15317,Temporary)")
+	f.IntVar(&TravelPercentage, "travel-rate", -1, "[optional] Filter jobs by percent of travel (ex., 25)")
+	f.StringSliceVar(&PositionScheduleTypeCode, "position-schedule-type-code", []string{}, "[optional][Comma Separated List] Filter jobs by schedule position type codes or names (ex., 6,Part-time)")
+	f.BoolVar(&RelocationIndicator, "relocation", false, "[optional][true/false] Only show jobs that offer relocation assistance if true.")
+	f.StringSliceVar(&SecurityClearanceRequired, "clearance", []string{}, "[optional][Comma Separated List] Filter jobs by clearance codes or names (ex., 2,\"Top Secret\")")
+	f.StringVar(&SupervisoryStatus, "supervisory-status", "", "[optional][Y/N] Only show supervisory (Y) or non-supervisory (N) jobs")
+	f.IntVar(&DatePosted, "date-posted", -1, "[optional][0 to 60] Filter jobs that were posted within the number of days specified")
+	f.IntSliceVar(&JobGradeCode, "job-grade-code", []int{}, "[optional] Filter for jobs containing the specified Job Grade Codes")
+	f.StringVar(&SortField, "sort-by", "", "[optional] Sort results by the specified value.")
+	f.StringVar(&SortDirection, "sort-direction", "", "[optional][Asc/Dsc] Ascending or Descending sort order")
+	f.IntVar(&ResultsPerPage, "num-results", 500, "[optional][25-500] number of results to return, 0 returns all")
+	f.IntVar(&Page, "page", 1, "[optional] page of results to return, or to start from with --all and --max-results")
+	f.BoolVar(&SearchAll, "all", false, "[optional][true/false] return every page of results if true")
+	f.IntVar(&MaxResults, "max-results", 0, "[optional] fetch pages until this many results are returned, 0 fetches a single page unless --all is set")
+	f.StringVar(&WhoMayApply, "who-may-apply", "", "[optional][All|Public|Status] Filter jobs based on who can apply")
+	f.IntVar(&Radius, "radius", -1, "[optional][int] Radius of miles from location to filter jobs")
+	f.StringVar(&Fields, "fields", "", "[optional][min|full] Amount of job announcement detail returned for each result")
+	f.StringSliceVar(&SalaryBucket, "salary-bucket", []string{}, "[optional][Comma Separated List] Filter jobs by salary bucket refinement tokens (ex., 5,6)")
+	f.StringSliceVar(&GradeBucket, "grade-bucket", []string{}, "[optional][Comma Separated List] Filter jobs by grade bucket refinement tokens (ex., 13,14)")
+	f.StringSliceVar(&HiringPath, "hiring-path", []string{}, "[optional][Comma Separated List] Filter jobs by hiring path codes or names (ex., FED-COMPETITIVE,veterans)")
+	f.StringSliceVar(&MissionCriticalTags, "mission-critical", []string{}, "[optional][Comma Separated List] Filter jobs by mission critical codes or names (ex., 01,\"Data Scientist\")")
+	f.IntSliceVar(&PositionSensitivity, "position-sensitivity", []int{}, "[optional][Comma Separated List] Sensitivity Codes to filter jobs by position sensitivity")
+	f.BoolVar(&RemoteIndicator, "remote", false, "[optional][true/false] Only shows jobs supporting remote work if true")
+	f.StringVar(&Near, "near", "", "[optional] postal code or <latitude,longitude> to sort results by distance from (ex., 78701)")
+	f.StringArrayVar(&QueryFiles, "query", []string{}, "[optional][Repeatable] JSON file of search options to run, postings matched by several queries are listed once")
+	f.Float64Var(&Within, "within", 50, "[optional] with --near, only show jobs with a location within this many miles, 0 shows all")
+
+	return f
+}
+
 func init() {
 	rootCmd.AddCommand(searchCmd)
-	searchCmd.PersistentFlags().StringVar(&userAgent, "user-agent", "", "[required] email address used when obtaining a usajobs api token")
-	searchCmd.PersistentFlags().StringVar(&apiToken, "token", "", "[required] usajobs api token")
-	err := searchCmd.MarkPersistentFlagRequired("user-agent")
-	if err != nil {
-		log.Fatal().Err(err).Msg("user-agent flag must be set")
-	}
-
-	err = searchCmd.MarkPersistentFlagRequired("token")
-	if err != nil {
-		log.Fatal().Err(err).Msg("token flag must be set")
-	}
-
-	searchCmd.PersistentFlags().StringVarP(&Keyword, "keyword", "k", "", "[optional] Words used to refine search (ex., Army Software Factory)")
-	searchCmd.PersistentFlags().StringVar(&PositionTitle, "title", "", "[optional] filter jobs by position title (ex., IT Specialist)")
-	searchCmd.PersistentFlags().StringVar(&RemunerationMinimumAmount, "min-salary", "", "[optional] Sets the lower limit for filtering jobs by salary (ex., 80,000)")
-	searchCmd.PersistentFlags().StringVar(&RemunerationMaximumAmount, "max-salary", "", "[optional] Sets the upper limit for filtering jobs by salary (ex., 120,000)")
-	searchCmd.PersistentFlags().StringVar(&PayGradeLow, "min-grade", "", "[optional] Sets the lower limit for filtering jobs by pay grade (ex, GS14)")
-	searchCmd.PersistentFlags().StringVar(&PayGradeHigh, "max-grade", "", "[optional] Sets the upper limit for filtering jobs by pay grade (ex., GS15)")
-	searchCmd.PersistentFlags().StringSliceVarP(&JobCategoryCode, "job-catagory", "j", []string{""}, "[optional] Comma separated list of job codes (ex., 2210, 0854)")
-	searchCmd.PersistentFlags().StringVar(&LocationName, "location", "", "[optional] dash (-) separated list of <city,state> (ex., Austin,Texas-Portland,Oregon)")
-	searchCmd.PersistentFlags().StringSliceVar(&Organization, "organization", []string{""}, "[optional] Comma separated list of organizations (ex., Immigration and Customs Enforcement,Office of Chief Information Officer)")
-	searchCmd.PersistentFlags().StringSliceVar(&PositionOfferingTypeCode, "position-type", []string{}, "[optional] Filter jobs by position type (ex., 15317)")
-	searchCmd.PersistentFlags().IntVar(&TravelPercentage, "travel-rate", -1, "[optional] Filter jobs by percent of travel (ex., 25)")
-	searchCmd.PersistentFlags().IntSliceVar(&PositionScheduleTypeCode, "position-schedule-type-code", []int{}, "[optional][Comma Separated List] Filter jobs by schedule position type code (ex., 6,2)")
-	searchCmd.PersistentFlags().BoolVar(&RelocationIndicator, "relocation", false, "[optional][true/false] Only show jobs that offer relocation assistance if true.")
-	searchCmd.PersistentFlags().IntSliceVar(&SecurityClearanceRequired, "clearance", []int{}, "[optional][Comma Separated List] Filter jobs by clearance types (ex., 1,2,3)")
-	searchCmd.PersistentFlags().StringVar(&SupervisoryStatus, "supervisory-status", "", "[optional] TBD, don't use yet")
-	searchCmd.PersistentFlags().IntVar(&DatePosted, "date-posted", -1, "[optional][0 to 60] Filter jobs that were posted within the number of days specified")
-	searchCmd.PersistentFlags().IntSliceVar(&JobGradeCode, "job-grade-code", []int{}, "[optional] Filter for jobs containing the specified Job Grade Codes")
-	searchCmd.PersistentFlags().StringVar(&SortField, "sort-by", "", "[optional] Sort results by the specified value.")
-	searchCmd.PersistentFlags().StringVar(&SortDirection, "sort-direction", "", "[optional][Asc/Dsc] Ascending or Descending sort order")
-	searchCmd.PersistentFlags().IntVar(&ResultsPerPage, "num-results", 500, "[optional][25-500] number of results to return, 0 returns all")
-	searchCmd.PersistentFlags().StringVar(&WhoMayApply, "who-may-apply", "", "[optional][All|Public|Status] Filter jobs based on who can apply")
-	searchCmd.PersistentFlags().IntVar(&Radius, "radius", -1, "[optional][int] Radius of miles from location to filter jobs")
-	searchCmd.PersistentFlags().StringSliceVar(&HiringPath, "hiring-path", []string{}, "[optional][Comma Seperated List]")
-	searchCmd.PersistentFlags().IntSliceVar(&PositionSensitivity, "position-sensitivity", []int{}, "[optional][Comma Separated List] Sensitivity Codes to filter jobs by position sensitivity")
-	searchCmd.PersistentFlags().BoolVar(&RemoteIndicator, "remote", false, "[optional][true/false] Only shows jobs supporting remote work if true")
+	searchCmd.PersistentFlags().AddFlagSet(searchFlags)
+	registerCompletions(searchCmd, map[string]completionFunc{
+		"job-catagory":                completeCodeList("occupationalseries", true),
+		"hiring-path":                 completeCodeList("hiringpaths", true),
+		"clearance":                   completeCodeList("securityclearances", true),
+		"position-type":               completeCodeList("positionofferingtypes", true),
+		"position-schedule-type-code": completeCodeList("positionscheduletypes", true),
+		"mission-critical":            completeCodeList("missioncriticalcodes", true),
+		"travel-rate":                 completeCodeList("travelpercentages", false),
+		"who-may-apply":               completeValues("All\tfederal employees and the public", "Public\tU.S. citizens and the public", "Status\tcurrent and former federal employees"),
+		"supervisory-status":          completeValues("Y\tsupervisory", "N\tnon-supervisory"),
+		"sort-direction":              completeValues("Asc\tascending", "Dsc\tdescending"),
+		"fields":                      completeValues("min\tminimum detail", "full\tfull announcement detail"),
+	})
 }
 
-func setSearchOptions() usajobs.SearchOptions {
+func setSearchOptions() (usajobs.SearchOptions, error) {
 	var opt usajobs.SearchOptions
+	var err error
 
 	if Keyword != "" {
 		opt.Keyword = Keyword
@@ -151,44 +238,49 @@ func setSearchOptions() usajobs.SearchOptions {
 		opt.PayGradeHigh = PayGradeHigh
 	}
 
-	if len(JobCategoryCode) >= 1 {
-		opt.JobCategoryCode = JobCategoryCode
+	opt.JobCategoryCode, err = resolveCodeNames("job-catagory", "occupationalseries", JobCategoryCode)
+	if err != nil {
+		return opt, err
 	}
 
 	if LocationName != "" {
-		locationSlice := strings.Split(LocationName, "-")
-		for _, i := range locationSlice {
-			fmt.Println(i)
-		}
-		opt.LocationName = locationSlice
+		opt.LocationName = strings.Split(LocationName, "-")
+	}
+
+	if len(PostingChannel) >= 1 {
+		opt.PostingChannel = toTyped[usajobs.PostingChannel](PostingChannel)
 	}
 
 	if len(Organization) >= 1 {
 		opt.Organization = Organization
 	}
 
-	if len(PositionOfferingTypeCode) >= 1 {
-		opt.PositionOfferingTypeCode = PositionOfferingTypeCode
+	opt.PositionOfferingTypeCode, err = resolveCodeNames("position-type", "positionofferingtypes", PositionOfferingTypeCode)
+	if err != nil {
+		return opt, err
 	}
 
 	if TravelPercentage > -1 {
 		opt.TravelPercentage = TravelPercentage
 	}
 
-	if len(PositionScheduleTypeCode) >= 1 {
-		opt.PositionScheduleTypeCode = PositionScheduleTypeCode
+	opt.PositionScheduleTypeCode, err = resolveIntCodeNames("position-schedule-type-code", "positionscheduletypes", PositionScheduleTypeCode)
+	if err != nil {
+		return opt, err
 	}
 
 	if RelocationIndicator {
 		opt.RelocationIndicator = RelocationIndicator
 	}
 
-	if len(SecurityClearanceRequired) >= 1 {
-		opt.SecurityClearanceRequired = SecurityClearanceRequired
+	opt.SecurityClearanceRequired, err = resolveIntCodeNames("clearance", "securityclearances", SecurityClearanceRequired)
+	if err != nil {
+		return opt, err
 	}
 
-	if SupervisoryStatus != "" {
-		opt.SupervisoryStatus = SupervisoryStatus
+	opt.SupervisoryStatus, err = usajobs.ParseSupervisoryStatus(SupervisoryStatus)
+	if err != nil {
+		return opt, err
 	}
 
 	if DatePosted > -1 {
@@ -215,16 +307,48 @@ func setSearchOptions() usajobs.SearchOptions {
 		opt.ResultsPerPage = ResultsPerPage
 	}
 
-	if WhoMayApply != "" {
-		opt.WhoMayApply = WhoMayApply
+	if Page < 1 {
+		return opt, errors.New("--page must be 1 or greater")
+	}
+	opt.Page = Page
+
+	if MaxResults < 0 {
+		return opt, errors.New("--max-results must not be negative")
+	}
+
+	opt.WhoMayApply, err = usajobs.ParseWhoMayApply(WhoMayApply)
+	if err != nil {
+		return opt, err
 	}
 
 	if Radius > 0 {
 		opt.Radius = Radius
 	}
 
-	if len(HiringPath) > 0 {
-		opt.HiringPath = HiringPath
+	opt.Fields, err = usajobs.ParseSearchFields(Fields)
+	if err != nil {
+		return opt, err
+	}
+
+	if len(SalaryBucket) > 0 {
+		opt.SalaryBucket = toTyped[usajobs.SalaryBucket](SalaryBucket)
+	}
+
+	if len(GradeBucket) > 0 {
+		opt.GradeBucket = toTyped[usajobs.GradeBucket](GradeBucket)
+	}
+
+	opt.HiringPath, err = resolveCodeNames("hiring-path", "hiringpaths", HiringPath)
+	if err != nil {
+		return opt, err
+	}
+
+	tags, err := resolveCodeNames("mission-critical", "missioncriticalcodes", MissionCriticalTags)
+	if err != nil {
+		return opt, err
+	}
+	if len(tags) > 0 {
+		opt.MissionCriticalTags = toTyped[usajobs.MissionCriticalTag](tags)
 	}
 
 	if len(PositionSensitivity) > 0 {
@@ -235,50 +359,314 @@ func setSearchOptions() usajobs.SearchOptions {
 		opt.RemoteIndicator = RemoteIndicator
 	}
 
-	return opt
+	return opt, nil
+}
+
+// toTyped converts flag values into the typed string values used by
+// usajobs.SearchOptions.
+func toTyped[T ~string](values []string) []T {
+	typed := make([]T, 0, len(values))
+	for _, v := range values {
+		typed = append(typed, T(v))
+	}
+	return typed
+}
+
+// resolveCodeNames returns the codes of a codelist named by the values of a
+// flag. Numeric values are taken as codes, others are resolved by name from
+// the cached codelist (ex., "Top Secret" is clearance 3).
+func resolveCodeNames(flag, list string, names []string) ([]string, error) {
+	var values []usajobs.CodeListValue
+	var codes []string
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		_, err := strconv.Atoi(name)
+		if err == nil {
+			codes = append(codes, name)
+			continue
+		}
+
+		if values == nil {
+			values, err = cachedCodeList(list)
+			if err != nil {
+				return nil, fmt.Errorf("could not look up --%s %q in %s: %w", flag, name, list, err)
+			}
+		}
+
+		code, err := usajobs.ResolveCode(values, name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --%s: %w", flag, err)
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// resolveIntCodeNames is resolveCodeNames for codelists with numeric codes.
+func resolveIntCodeNames(flag, list string, names []string) ([]int, error) {
+	codes, err := resolveCodeNames(flag, list, names)
+	if err != nil {
+		return nil, err
+	}
+
+	var ints []int
+	for _, code := range codes {
+		i, err := strconv.Atoi(code)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --%s: %s is not a numeric code", flag, code)
+		}
+		ints = append(ints, i)
+	}
+	return ints, nil
 }
 
 func executeSearch(opt *usajobs.SearchOptions) error {
 
 	var err error
-	if Client == nil {
-		Client, err = usajobs.NewClient(userAgent, apiToken)
+	err = initClient(true)
+	if err != nil {
+		return err
+	}
+
+	// ndjson is written as each page arrives, unless --near needs every
+	// result to sort them by distance
+	stream := format == "" && display == "ndjson" && Near == ""
+
+	var items []usajobs.SearchResultItem
+	count := 0
+	err = Client.Search.Pages(context.Background(), opt, func(page int, r *usajobs.SearchResponse) error {
+		log.Info().Msgf("page %d of %d / %d total", page, max(r.PageCount(), page), r.SearchResult.SearchResultCountAll)
+
+		results := r.SearchResult.SearchResultItems
+		if MaxResults > 0 && count+len(results) > MaxResults {
+			results = results[:MaxResults-count]
+		}
+		count += len(results)
+
+		if stream {
+			err := displayNDJSON(results)
+			if err != nil {
+				return err
+			}
+		} else {
+			items = append(items, results...)
+		}
+
+		if MaxResults > 0 && count >= MaxResults {
+			return usajobs.SkipPages
+		}
+		if !SearchAll && MaxResults == 0 {
+			return usajobs.SkipPages
+		}
+		return nil
+	})
+	if err != nil || stream {
+		return err
+	}
+
+	return displaySearch(items, nil)
+}
+
+// loadSearchQueries reads each JSON query file on top of a copy of base so
+// flags like --num-results apply to every query.
+func loadSearchQueries(base usajobs.SearchOptions, files []string) ([]usajobs.SearchOptions, error) {
+	var opts []usajobs.SearchOptions
+	for _, f := range files {
+		b, err := os.ReadFile(f)
 		if err != nil {
-			return err
+			return nil, err
 		}
+
+		opt := cloneSearchOptions(base)
+		decoder := json.NewDecoder(bytes.NewReader(b))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&opt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f, err)
+		}
+		opts = append(opts, opt)
 	}
+	return opts, nil
+}
 
-	response, data, err := Client.Search.WithOptions(opt)
+// cloneSearchOptions copies opt along with its list options, since decoding
+// a query file into a copy sharing them would overwrite base's values.
+func cloneSearchOptions(opt usajobs.SearchOptions) usajobs.SearchOptions {
+	v := reflect.ValueOf(&opt).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		if f.Kind() != reflect.Slice || f.IsNil() {
+			continue
+		}
+
+		c := reflect.MakeSlice(f.Type(), f.Len(), f.Len())
+		reflect.Copy(c, f)
+		f.Set(c)
+	}
+	return opt
+}
+
+// executeMultiSearch runs each query and displays the de-duplicated postings
+// with the names of the queries that matched them.
+func executeMultiSearch(opts []usajobs.SearchOptions, names []string) error {
+
+	var err error
+	err = initClient(true)
 	if err != nil {
 		return err
 	}
 
-	if response.StatusCode != http.StatusOK {
-		return errors.New(response.Status)
+	results, err := Client.Search.Multi(context.Background(), opts)
+	if err != nil {
+		return err
 	}
 
-	headersSummary := []string{"DEPARTMENT", "JOB_TITLE", "CLOSE_DATE", "URL"}
-	var dataSummary [][]string
-	for _, item := range data.SearchResult.SearchResultItems {
-		dataSummary = append(dataSummary, []string{
-			addNewLines(item.MatchedObjectDescriptor.DepartmentName, 10),
-			addNewLines(item.MatchedObjectDescriptor.PositionTitle, 20),
-			addNewLines(item.MatchedObjectDescriptor.ApplicationCloseDate, 10),
-			addNewLines(item.MatchedObjectDescriptor.ApplyURI[0], 80)})
+	var items []usajobs.SearchResultItem
+	matched := map[string][]string{}
+	for _, r := range results {
+		items = append(items, r.Item)
+		for _, q := range r.Queries {
+			matched[r.Item.MatchedObjectID] = append(matched[r.Item.MatchedObjectID], filepath.Base(names[q]))
+		}
 	}
 
-	switch display {
-	case "summary":
-		err = displayTable(headersSummary, dataSummary)
+	return displaySearch(items, matched)
+}
+
+// displaySearch renders search results in the requested display format. When
+// matched is not nil, a column lists the queries that returned each posting.
+func displaySearch(items []usajobs.SearchResultItem, matched map[string][]string) error {
+
+	var err error
+	var distances []usajobs.ItemDistance
+	if Near != "" {
+		origin, err := Client.ResolvePoint(Near)
 		if err != nil {
 			return err
 		}
+
+		distances = usajobs.WithinRadius(items, origin, Within)
+		items = make([]usajobs.SearchResultItem, 0, len(distances))
+		for _, d := range distances {
+			items = append(items, d.Item)
+		}
+	}
+
+	if format != "" {
+		records := make([]searchTemplateItem, 0, len(items))
+		for i, item := range items {
+			r := searchTemplateItem{
+				MatchedObjectDescriptor: item.MatchedObjectDescriptor,
+				SearchItemDetails:       item.MatchedObjectDescriptor.UserArea.Details,
+				MatchedObjectID:         item.MatchedObjectID,
+				RelevanceRank:           item.RelevanceRank,
+				Queries:                 matched[item.MatchedObjectID],
+			}
+			if distances != nil {
+				r.Distance = distances[i].Miles
+			}
+			records = append(records, r)
+		}
+		return displayTemplate(records)
+	}
+
+	switch display {
+	case "json":
+		if items == nil {
+			items = []usajobs.SearchResultItem{}
+		}
+		return displayJSON(items)
+	case "ndjson":
+		return displayNDJSON(items)
+	}
+
+	// summary keeps its compact, hand-picked columns unless --columns picks
+	// from every field; detail and csv default to every field
+	var fields []usajobs.SearchItemField
+	if len(columns) > 0 || display == "detail" || display == "csv" {
+		fields = usajobs.SearchItemFields
+	}
+
+	var headers []string
+	for _, f := range fields {
+		headers = append(headers, f.Name)
+	}
+	if fields == nil {
+		headers = []string{"DEPARTMENT", "JOB_TITLE", "CLOSE_DATE", "URL"}
+	}
+	if distances != nil {
+		headers = append(headers, "DISTANCE")
+	}
+	if matched != nil {
+		headers = append(headers, "QUERIES")
+	}
+
+	var data [][]string
+	for i, item := range items {
+		var row []string
+		if fields != nil {
+			row = item.Row(fields)
+		} else {
+			var apply string
+			if len(item.MatchedObjectDescriptor.ApplyURI) > 0 {
+				apply = item.MatchedObjectDescriptor.ApplyURI[0]
+			}
+			row = []string{
+				item.MatchedObjectDescriptor.DepartmentName,
+				item.MatchedObjectDescriptor.PositionTitle,
+				item.MatchedObjectDescriptor.ApplicationCloseDate,
+				apply,
+			}
+		}
+		if distances != nil {
+			row = append(row, fmt.Sprintf("%.1f mi (%s)", distances[i].Miles, distances[i].Location.LocationName))
+		}
+		if matched != nil {
+			row = append(row, strings.Join(matched[item.MatchedObjectID], usajobs.FieldSeparator))
+		}
+		data = append(data, row)
+	}
+
+	switch display {
 	case "detail":
-		log.Info().Msg("Detail view not supported for the search command due to the size of the output. Use 'csv' instead.")
+		headers, data, err = selectColumns(headers, data)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range data {
+			var fieldData [][]string
+			for i, value := range row {
+				if value == "" {
+					continue
+				}
+				fieldData = append(fieldData, []string{headers[i], wrapField(value, 0)})
+			}
+
+			err = renderTable([]string{"FIELD", "VALUE"}, fieldData)
+			if err != nil {
+				return err
+			}
+		}
 	case "csv":
-		log.Info().Msg("CSV support coming soon!")
+		err = displayCSV(headers, data)
+		if err != nil {
+			return err
+		}
 	default:
-		err = displayTable(headersSummary, dataSummary)
+		// columns without a preferred width are fit to the terminal
+		widths := map[string]int{"DEPARTMENT": 10, "JOB_TITLE": 20, "CLOSE_DATE": 10, "DISTANCE": 20}
+		for _, row := range data {
+			for i, value := range row {
+				row[i] = wrapField(value, widths[headers[i]])
+			}
+		}
+
+		err = displayTable(headers, data)
 		if err != nil {
 			return err
 		}
@@ -286,3 +674,17 @@ func executeSearch(opt *usajobs.SearchOptions) error {
 
 	return nil
 }
+
+// wrapField puts each value of a flattened repeated field on its own line and
+// wraps values longer than n. Plain output keeps the field on one line.
+func wrapField(s string, n int) string {
+	if plainOutput() {
+		return s
+	}
+
+	values := strings.Split(s, usajobs.FieldSeparator)
+	for i := range values {
+		values[i] = addNewLines(values[i], n)
+	}
+	return strings.Join(values, "\n")
+}
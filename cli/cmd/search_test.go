@@ -16,11 +16,17 @@ limitations under the License.
 package cmd
 
 import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	usajobs "github.com/JeffRDay/go-usajobs/client"
@@ -71,12 +77,543 @@ func TestSearch(t *testing.T) {
 	}
 }
 
+func TestSearchNear(t *testing.T) {
+	data, err := os.ReadFile(searchTestDataPath)
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	postalCodes, err := os.ReadFile("../../testdata/postalcodes-testdata.json")
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	// Create a mock server that returns the search and postal code JSON data
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Log(r.URL.String())
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if strings.Contains(r.URL.String(), "/codelist/postalcodes") {
+			w.Write(postalCodes)
+			return
+		}
+		w.Write(data)
+	}))
+	defer mockServer.Close()
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+
+	Client, err = usajobs.NewClient("test", "test")
+	if err != nil {
+		panic(err.Error())
+	}
+	Client.BaseURL = u
+
+	defer func() {
+		Near, Within = "", 50
+	}()
+
+	Near = "78701"
+	Within = 50
+	err = executeSearch(&usajobs.SearchOptions{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	Near = "not-a-postal-code"
+	err = executeSearch(&usajobs.SearchOptions{})
+	if err == nil {
+		t.Fatal("expected error for unknown postal code, got nil")
+	}
+}
+
+func TestMultiSearch(t *testing.T) {
+	data, err := os.ReadFile(searchTestDataPath)
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	// Create a mock server that returns the same JSON data for every query
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Log(r.URL.String())
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	}))
+	defer mockServer.Close()
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+
+	Client, err = usajobs.NewClient("test", "test")
+	if err != nil {
+		panic(err.Error())
+	}
+	Client.BaseURL = u
+
+	dir := t.TempDir()
+	it := filepath.Join(dir, "it.json")
+	cyber := filepath.Join(dir, "cyber.json")
+	typo := filepath.Join(dir, "typo.json")
+	os.WriteFile(it, []byte(`{"JobCategoryCode": ["2210"]}`), 0o600)
+	os.WriteFile(cyber, []byte(`{"Keyword": "cyber"}`), 0o600)
+	os.WriteFile(typo, []byte(`{"Keywrd": "cyber"}`), 0o600)
+
+	opts, err := loadSearchQueries(usajobs.SearchOptions{ResultsPerPage: 25}, []string{it, cyber})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if opts[0].JobCategoryCode[0] != "2210" || opts[0].ResultsPerPage != 25 {
+		t.Errorf("expected query file to be applied on top of flags, got %+v", opts[0])
+	}
+
+	if opts[1].Keyword != "cyber" || opts[1].JobCategoryCode != nil {
+		t.Errorf("expected queries to be independent, got %+v", opts[1])
+	}
+
+	err = executeMultiSearch(opts, []string{it, cyber})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	_, err = loadSearchQueries(usajobs.SearchOptions{}, []string{typo})
+	if err == nil {
+		t.Fatal("expected error for unknown query field, got nil")
+	}
+
+	// a query file setting a list option must not change the flags the
+	// next query file is applied on top of
+	army := filepath.Join(dir, "army.json")
+	os.WriteFile(army, []byte(`{"Organization": ["ARMY"]}`), 0o600)
+	base := usajobs.SearchOptions{Organization: []string{"AF"}}
+	opts, err = loadSearchQueries(base, []string{army, cyber})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if opts[0].Organization[0] != "ARMY" {
+		t.Errorf("expected the first query's organization, got %v", opts[0].Organization)
+	}
+	if opts[1].Organization[0] != "AF" || base.Organization[0] != "AF" {
+		t.Errorf("expected the flag organization to be unchanged, got %v and %v", opts[1].Organization, base.Organization)
+	}
+}
+
 func TestSetSearchOpts(t *testing.T) {
 
 	Keyword = "Army"
-	opt := setSearchOptions()
+	opt, err := setSearchOptions()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
 
 	if opt.Keyword != "Army" {
 		t.Fatalf("expected %s, got %s", "Army", opt.Keyword)
 	}
 }
+
+func TestSetSearchOptsTyped(t *testing.T) {
+	defer func() {
+		SupervisoryStatus, Fields, WhoMayApply = "", "", ""
+		SalaryBucket, GradeBucket, MissionCriticalTags, PostingChannel = nil, nil, nil, nil
+	}()
+
+	SupervisoryStatus = "yes"
+	Fields = "full"
+	WhoMayApply = "public"
+	SalaryBucket = []string{"5", "6"}
+	GradeBucket = []string{"13"}
+	MissionCriticalTags = []string{"01"}
+	PostingChannel = []string{"USAJOBS"}
+
+	opt, err := setSearchOptions()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if opt.SupervisoryStatus != usajobs.SupervisoryStatusYes {
+		t.Errorf("expected %s, got %s", usajobs.SupervisoryStatusYes, opt.SupervisoryStatus)
+	}
+
+	if opt.Fields != usajobs.SearchFieldsFull {
+		t.Errorf("expected %s, got %s", usajobs.SearchFieldsFull, opt.Fields)
+	}
+
+	if opt.WhoMayApply != usajobs.WhoMayApplyPublic {
+		t.Errorf("expected %s, got %s", usajobs.WhoMayApplyPublic, opt.WhoMayApply)
+	}
+
+	if len(opt.SalaryBucket) != 2 || opt.SalaryBucket[1] != "6" {
+		t.Errorf("expected salary buckets [5 6], got %v", opt.SalaryBucket)
+	}
+
+	if len(opt.GradeBucket) != 1 || len(opt.MissionCriticalTags) != 1 || len(opt.PostingChannel) != 1 {
+		t.Errorf("expected grade bucket, mission critical tag, and posting channel to be set, got %+v", opt)
+	}
+
+	Fields = "everything"
+	_, err = setSearchOptions()
+	if err == nil {
+		t.Fatal("expected error for invalid fields value, got nil")
+	}
+}
+
+func TestSetSearchOptsCodeNames(t *testing.T) {
+	client := Client
+	defer func() {
+		Client, codeListCacheDir = client, ""
+		SecurityClearanceRequired, HiringPath, JobCategoryCode, PositionOfferingTypeCode = nil, nil, nil, nil
+	}()
+	newCodeListServer(t)
+	codeListCacheDir = t.TempDir()
+
+	SecurityClearanceRequired = []string{"2", "Top Secret"}
+	HiringPath = []string{"veterans", "FED-COMPETITIVE"}
+	JobCategoryCode = []string{"", "Information Technology Management"}
+	PositionOfferingTypeCode = []string{"permanent"}
+
+	opt, err := setSearchOptions()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if fmt.Sprint(opt.SecurityClearanceRequired) != "[2 3]" {
+		t.Errorf("expected clearances [2 3], got %v", opt.SecurityClearanceRequired)
+	}
+	if fmt.Sprint(opt.HiringPath) != "[VET FED-COMPETITIVE]" {
+		t.Errorf("expected hiring paths [VET FED-COMPETITIVE], got %v", opt.HiringPath)
+	}
+	if fmt.Sprint(opt.JobCategoryCode) != "[2210]" {
+		t.Errorf("expected job category [2210], got %v", opt.JobCategoryCode)
+	}
+	if fmt.Sprint(opt.PositionOfferingTypeCode) != "[15317]" {
+		t.Errorf("expected position type [15317], got %v", opt.PositionOfferingTypeCode)
+	}
+
+	JobCategoryCode = []string{"information technology"}
+	_, err = setSearchOptions()
+	if !errors.Is(err, usajobs.ErrAmbiguousCodeName) || !strings.Contains(err.Error(), "--job-catagory") || !strings.Contains(err.Error(), "(2299)") {
+		t.Errorf("expected an ambiguous --job-catagory error suggesting 2299, got %v", err)
+	}
+
+	JobCategoryCode, SecurityClearanceRequired = nil, []string{"top secert"}
+	_, err = setSearchOptions()
+	if !errors.Is(err, usajobs.ErrUnknownCodeName) || !strings.Contains(err.Error(), `"Top Secret" (3)`) {
+		t.Errorf("expected an unknown --clearance error suggesting Top Secret, got %v", err)
+	}
+}
+
+// captureStdout returns everything fn writes to os.Stdout.
+func captureStdout(t *testing.T, fn func() error) (string, error) {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("could not create pipe: %v", err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	out := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		out <- string(b)
+	}()
+
+	err = fn()
+	w.Close()
+	return <-out, err
+}
+
+func TestSearchDisplay(t *testing.T) {
+	data, err := os.ReadFile(searchTestDataPath)
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	}))
+	defer mockServer.Close()
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+
+	Client, err = usajobs.NewClient("test", "test")
+	if err != nil {
+		panic(err.Error())
+	}
+	Client.BaseURL = u
+
+	defer func() {
+		display = "summary"
+		columns = nil
+	}()
+
+	opt := usajobs.SearchOptions{Keyword: "it"}
+
+	display = "csv"
+	out, err := captureStdout(t, func() error { return executeSearch(&opt) })
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Fatalf("could not parse csv output: %v", err)
+	}
+
+	if len(records) != 4 || len(records[0]) != len(usajobs.SearchItemFields) {
+		t.Fatalf("expected a header and 3 rows of %d fields, got %d rows of %d", len(usajobs.SearchItemFields), len(records), len(records[0]))
+	}
+
+	row := map[string]string{}
+	for i, h := range records[0] {
+		row[h] = records[1][i]
+	}
+
+	if row["LOCATIONS"] != "Washington, District of Columbia; Dallas, Texas" {
+		t.Errorf("expected flattened locations, got %q", row["LOCATIONS"])
+	}
+
+	if row["HIRING_PATHS"] != "fed-competitive; vet" || row["TELEWORK_ELIGIBLE"] != "true" {
+		t.Errorf("expected hiring paths and telework, got %q and %q", row["HIRING_PATHS"], row["TELEWORK_ELIGIBLE"])
+	}
+
+	columns = []string{"job_title", "MIN_SALARY"}
+	out, err = captureStdout(t, func() error { return executeSearch(&opt) })
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if !strings.HasPrefix(out, "JOB_TITLE,MIN_SALARY\nIT Specialist (INFOSEC),117962.0\n") {
+		t.Errorf("unexpected csv output for selected columns:\n%s", out)
+	}
+
+	display = "detail"
+	columns = nil
+	out, err = captureStdout(t, func() error { return executeSearch(&opt) })
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if !strings.Contains(out, "SECURITY_CLEARANCE") || !strings.Contains(out, "Registered Nurse") {
+		t.Errorf("expected detail output to include every field, got:\n%s", out)
+	}
+
+	display = "json"
+	out, err = captureStdout(t, func() error { return executeSearch(&opt) })
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var items []usajobs.SearchResultItem
+	err = json.Unmarshal([]byte(out), &items)
+	if err != nil || len(items) != 3 || items[0].MatchedObjectID != "800000001" {
+		t.Errorf("invalid json output: %v\n%s", err, out)
+	}
+
+	display = "ndjson"
+	out, err = captureStdout(t, func() error { return executeSearch(&opt) })
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 ndjson records, got %d", len(lines))
+	}
+
+	var item usajobs.SearchResultItem
+	err = json.Unmarshal([]byte(lines[2]), &item)
+	if err != nil || item.MatchedObjectDescriptor.PositionTitle != "Registered Nurse" {
+		t.Errorf("invalid ndjson record: %v\n%s", err, lines[2])
+	}
+
+	display = "detail"
+	columns = []string{"NOT_A_FIELD"}
+	err = executeSearch(&opt)
+	if err == nil {
+		t.Error("expected error for unknown column, got nil")
+	}
+}
+
+func TestSearchLocationNDJSON(t *testing.T) {
+	data, err := os.ReadFile(searchTestDataPath)
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	var queries []url.Values
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	}))
+	defer mockServer.Close()
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+
+	Client, err = usajobs.NewClient("test", "test")
+	if err != nil {
+		panic(err.Error())
+	}
+	Client.BaseURL = u
+
+	LocationName, display = "Austin, Texas-Portland, Oregon", "ndjson"
+	defer func() { LocationName, display = "", "summary" }()
+
+	// the locations are only sent to usajobs, never written with the results
+	out, err := captureStdout(t, func() error {
+		opt, err := setSearchOptions()
+		if err != nil {
+			return err
+		}
+		return executeSearch(&opt)
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		t.Errorf("expected 3 ndjson records, got %d:\n%s", len(lines), out)
+	}
+	for _, line := range lines {
+		var item usajobs.SearchResultItem
+		err = json.Unmarshal([]byte(line), &item)
+		if err != nil {
+			t.Errorf("invalid ndjson record %q: %v", line, err)
+		}
+	}
+
+	if q := queries[len(queries)-1]; q.Get("LocationName") != "Austin, Texas;Portland, Oregon" {
+		t.Errorf("expected both locations to be searched, got %q", q.Get("LocationName"))
+	}
+}
+
+func TestSearchPages(t *testing.T) {
+	data, err := os.ReadFile(searchTestDataPath)
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	var full usajobs.SearchResponse
+	err = json.Unmarshal(data, &full)
+	if err != nil {
+		t.Fatalf("could not decode test data: %v", err)
+	}
+	items := full.SearchResult.SearchResultItems
+
+	// every page returns all of the test data postings, so n pages return
+	// n times as many results
+	const pages = 3
+	var requested []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Query().Get("Page"))
+
+		sr := usajobs.SearchResponse{}
+		sr.SearchResult.SearchResultItems = items
+		sr.SearchResult.SearchResultCount = len(items)
+		sr.SearchResult.SearchResultCountAll = pages * len(items)
+		sr.SearchResult.UserArea.NumberOfPages = "3"
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(sr)
+	}))
+	defer mockServer.Close()
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+
+	Client, err = usajobs.NewClient("test", "test")
+	if err != nil {
+		t.Fatalf("could not create new usajobs client: %v", err)
+	}
+	Client.BaseURL = u
+
+	defer func() {
+		display = "summary"
+		SearchAll, MaxResults, Page = false, 0, 1
+	}()
+
+	search := func(opt usajobs.SearchOptions) string {
+		t.Helper()
+
+		requested = nil
+		out, err := captureStdout(t, func() error { return executeSearch(&opt) })
+		if err != nil {
+			t.Fatalf("failed to execute: %v", err.Error())
+		}
+		return out
+	}
+
+	display = "json"
+	var results []usajobs.SearchResultItem
+	err = json.Unmarshal([]byte(search(usajobs.SearchOptions{Page: 2})), &results)
+	if err != nil || len(results) != len(items) {
+		t.Errorf("expected a single page of results, got %d: %v", len(results), err)
+	}
+	if strings.Join(requested, ",") != "2" {
+		t.Errorf("expected only page 2 to be requested, got %v", requested)
+	}
+
+	SearchAll = true
+	err = json.Unmarshal([]byte(search(usajobs.SearchOptions{})), &results)
+	if err != nil || len(results) != pages*len(items) {
+		t.Errorf("expected every page of results, got %d: %v", len(results), err)
+	}
+	if strings.Join(requested, ",") != "1,2,3" {
+		t.Errorf("expected every page to be requested, got %v", requested)
+	}
+
+	SearchAll = false
+	MaxResults = len(items) + 1
+	display = "csv"
+	records, err := csv.NewReader(strings.NewReader(search(usajobs.SearchOptions{}))).ReadAll()
+	if err != nil || len(records) != MaxResults+1 {
+		t.Errorf("expected a header and %d rows, got %d: %v", MaxResults, len(records), err)
+	}
+	if strings.Join(requested, ",") != "1,2" {
+		t.Errorf("expected paging to stop at --max-results, got %v", requested)
+	}
+
+	MaxResults = 0
+	SearchAll = true
+	display = "ndjson"
+	lines := strings.Split(strings.TrimSpace(search(usajobs.SearchOptions{})), "\n")
+	if len(lines) != pages*len(items) {
+		t.Errorf("expected one ndjson record per result on every page, got %d", len(lines))
+	}
+
+	SearchAll = false
+	Page = 0
+	_, err = setSearchOptions()
+	if err == nil {
+		t.Error("expected error for --page 0, got nil")
+	}
+
+	Page, MaxResults = 1, -1
+	_, err = setSearchOptions()
+	if err == nil {
+		t.Error("expected error for negative --max-results, got nil")
+	}
+}
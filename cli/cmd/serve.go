@@ -0,0 +1,171 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+	"github.com/JeffRDay/go-usajobs/server"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// serveShutdownTimeout is how long serve waits for requests in flight to
+// finish when it is stopped.
+const serveShutdownTimeout = 10 * time.Second
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the usajobs api locally, sharing one token, cache and rate limit",
+	Long: `
+Serve a local HTTP JSON API in front of the usajobs api, so many applications share one
+api token, one cache of responses and one rate limit instead of each using their own.
+
+/search and /codelist/{name} mirror the usajobs api: the query is passed through and the
+response returned unchanged. The typed endpoints flatten postings to the same fields as
+'usajobs search --columns', keyed by their lower case names (ex., job_title):
+
+    GET /v1/postings          search, with the usajobs /search parameters
+                              (ex., ?Keyword=nurse&JobCategoryCode=0610;0620),
+                              plus columns=job_title,close_date to select fields
+    GET /v1/postings/{id}     a posting by MatchedObjectID or PositionID
+    GET /v1/codelists         the names of the codelists
+    GET /v1/codelists/{name}  codelist values, filtered by contains, parent and disabled=true
+    GET /healthz              status and cache statistics
+
+Successful responses are cached for --cache-ttl and concurrent requests for the same
+url share one request to usajobs. Requests to usajobs are limited to --rate a second,
+with bursts of up to --burst. The X-Cache header of each response is HIT when it was
+served from the cache.
+
+Example Usage:
+
+    usajobs serve --addr=localhost:8080
+    curl 'localhost:8080/v1/postings?Keyword=kubernetes&columns=job_title,department,url'
+
+    `,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		err := executeServe(ctx)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to execute serve command")
+		}
+	},
+}
+
+var (
+	serveAddr      string
+	serveCacheTTL  time.Duration
+	serveCacheSize int
+	serveRate      float64
+	serveBurst     int
+)
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "localhost:8080", "[optional] address to listen on")
+	serveCmd.Flags().DurationVar(&serveCacheTTL, "cache-ttl", 15*time.Minute, "[optional] how long responses are cached, 0 disables the cache")
+	serveCmd.Flags().IntVar(&serveCacheSize, "cache-size", 1000, "[optional] maximum number of cached responses, 0 is no limit")
+	serveCmd.Flags().Float64Var(&serveRate, "rate", 2, "[optional] maximum requests a second to usajobs")
+	serveCmd.Flags().IntVar(&serveBurst, "burst", 5, "[optional] maximum burst of requests to usajobs")
+}
+
+func executeServe(ctx context.Context) error {
+	handler, err := serveHandler()
+	if err != nil {
+		return err
+	}
+
+	l, err := net.Listen("tcp", serveAddr)
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	done := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+		defer cancel()
+		done <- srv.Shutdown(shutdown)
+	}()
+
+	log.Info().Msgf("serving the usajobs api on http://%s", l.Addr())
+	err = srv.Serve(l)
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return <-done
+}
+
+// serveHandler returns the server, with the client's requests to usajobs
+// cached and rate limited, and each request logged.
+func serveHandler() (http.Handler, error) {
+	if serveRate <= 0 {
+		return nil, errors.New("--rate must be greater than 0")
+	}
+	if serveCacheTTL < 0 || serveCacheSize < 0 || serveBurst < 1 {
+		return nil, errors.New("--cache-ttl and --cache-size must not be negative, and --burst must be at least 1")
+	}
+
+	err := initClient(true)
+	if err != nil {
+		return nil, err
+	}
+
+	limited := usajobs.NewRateLimiter(Client.Client.Transport, serveRate, serveBurst)
+	cache := usajobs.NewResponseCache(limited, serveCacheTTL, serveCacheSize)
+	Client.Client.Transport = cache
+
+	s := server.New(Client)
+	s.Cache = cache
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		s.ServeHTTP(rw, r)
+
+		log.Info().
+			Str("method", r.Method).
+			Str("path", r.URL.RequestURI()).
+			Int("status", rw.status).
+			Str("cache", w.Header().Get(usajobs.CacheHeader)).
+			Dur("duration", time.Since(start)).
+			Msg("request")
+	}), nil
+}
+
+// statusWriter records the status of a response for logging.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+	"github.com/JeffRDay/go-usajobs/server"
+)
+
+func TestServe(t *testing.T) {
+	data, err := os.ReadFile(searchTestDataPath)
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	var requests atomic.Int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	}))
+	defer mockServer.Close()
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+
+	Client, err = usajobs.NewClient("test", "test")
+	if err != nil {
+		t.Fatalf("could not create new usajobs client: %v", err)
+	}
+	Client.BaseURL = u
+
+	defer func() {
+		serveRate, serveAddr = 2, "localhost:8080"
+	}()
+
+	serveRate = 0
+	_, err = serveHandler()
+	if err == nil {
+		t.Error("expected an error for a rate of 0")
+	}
+	serveRate = 2
+
+	handler, err := serveHandler()
+	if err != nil {
+		t.Fatalf("failed to create handler: %v", err)
+	}
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	for _, cache := range []string{"MISS", "HIT"} {
+		r, err := http.Get(srv.URL + "/v1/postings?Keyword=nurse&columns=job_title")
+		if err != nil {
+			t.Fatalf("failed to search: %v", err)
+		}
+
+		var p server.Postings
+		err = json.NewDecoder(r.Body).Decode(&p)
+		r.Body.Close()
+		if err != nil {
+			t.Fatalf("could not decode postings: %v", err)
+		}
+		if len(p.Items) != 3 || p.Items[2]["job_title"] != "Registered Nurse" {
+			t.Errorf("unexpected postings: %+v", p)
+		}
+		if got := r.Header.Get(usajobs.CacheHeader); got != cache {
+			t.Errorf("got %s %s, want %s", usajobs.CacheHeader, got, cache)
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("expected 1 request to usajobs, got %d", n)
+	}
+
+	// serve stops when its context is done
+	serveAddr = "127.0.0.1:0"
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err = executeServe(ctx)
+	if err != nil {
+		t.Errorf("expected serve to stop cleanly, got %v", err)
+	}
+}
@@ -0,0 +1,342 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// showMaxWidth is the widest show wraps text to, since long lines are
+	// hard to read even on wide terminals.
+	showMaxWidth = 100
+	// showLabelWidth is the width of the labels of the key facts of a posting.
+	showLabelWidth = 15
+	// defaultPager is used when the PAGER environment variable is not set.
+	defaultPager = "less -FRX"
+)
+
+var (
+	htmlBreak     = regexp.MustCompile(`(?i)<br\s*/?>`)
+	htmlBlockEnd  = regexp.MustCompile(`(?i)</(p|div|ul|ol|h[1-6])>`)
+	htmlListItem  = regexp.MustCompile(`(?i)<li[^>]*>`)
+	htmlTag       = regexp.MustCompile(`<[^>]+>`)
+	extraNewlines = regexp.MustCompile(`\n{3,}`)
+)
+
+// showCmd represents the show command
+var showCmd = &cobra.Command{
+	Use:   "show <MatchedObjectID|PositionID>",
+	Short: "Show the full announcement of a single posting",
+	Long: `
+Show the full announcement of a single posting: salary, grades, locations and the
+other key facts, followed by the summary, duties, requirements, education,
+evaluations, benefits, how to apply, and required documents. Postings are looked up
+by the usajobs control number (MatchedObjectID) or position id shown by search.
+
+Output to a terminal is shown with the pager in the PAGER environment variable
+(default "less -FRX") unless --no-pager is set.
+
+Example Usage:
+
+    usajobs show 800000001
+    usajobs show ICE-24-12345-MP --display=json
+
+    `,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := executeShow(args[0])
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to execute show command")
+		}
+	},
+}
+
+var showNoPager bool
+
+func init() {
+	rootCmd.AddCommand(showCmd)
+	showCmd.Flags().BoolVar(&showNoPager, "no-pager", false, "[optional] print to the terminal without a pager")
+}
+
+func executeShow(id string) error {
+	err := initClient(true)
+	if err != nil {
+		return err
+	}
+
+	item, err := Client.Search.ByID(context.Background(), id)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case format != "":
+		return displayTemplate([]searchTemplateItem{{
+			MatchedObjectDescriptor: item.MatchedObjectDescriptor,
+			SearchItemDetails:       item.MatchedObjectDescriptor.UserArea.Details,
+			MatchedObjectID:         item.MatchedObjectID,
+			RelevanceRank:           item.RelevanceRank,
+		}})
+	case display == "json":
+		return displayJSON(item)
+	case display == "ndjson":
+		return displayNDJSON([]usajobs.SearchResultItem{*item})
+	}
+
+	width := showMaxWidth
+	if !plainOutput() {
+		width = min(terminalWidth(), showMaxWidth)
+	}
+
+	return page(renderPosting(*item, width))
+}
+
+// renderPosting lays out a posting for reading in a terminal, wrapping text to
+// width cells.
+func renderPosting(item usajobs.SearchResultItem, width int) string {
+	d := item.MatchedObjectDescriptor
+	details := d.UserArea.Details
+
+	title := lipgloss.NewStyle().Bold(true)
+	heading := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("99"))
+	label := lipgloss.NewStyle().Faint(true)
+
+	var b strings.Builder
+	line := func(s string) {
+		b.WriteString(s)
+		b.WriteByte('\n')
+	}
+
+	line(title.Render(d.PositionTitle))
+	line(joinNonEmpty(" · ", details.SubAgencyName, d.DepartmentName))
+	line(joinNonEmpty(" · ", d.PositionID, item.MatchedObjectID))
+	b.WriteByte('\n')
+
+	fact := func(name string, values ...string) {
+		value := joinNonEmpty("\n", values...)
+		if value == "" {
+			return
+		}
+
+		// links are never wrapped so terminals can still open them
+		if !strings.HasPrefix(value, "http") {
+			value = wrapText(value, width-showLabelWidth)
+		}
+
+		indent := strings.Repeat(" ", showLabelWidth)
+		for i, l := range strings.Split(value, "\n") {
+			if i == 0 {
+				line(label.Render(fmt.Sprintf("%-*s", showLabelWidth, name)) + l)
+				continue
+			}
+			line(indent + l)
+		}
+	}
+
+	salary, _ := templateSalary(d.PositionRemuneration)
+	fact("Salary", salary)
+	fact("Grades", showGrades(d))
+
+	var series []string
+	for _, c := range d.JobCategory {
+		series = append(series, fmt.Sprintf("%s (%s)", c.Name, c.Code))
+	}
+	fact("Series", series...)
+
+	var schedules, appointments []string
+	for _, s := range d.PositionSchedule {
+		schedules = append(schedules, s.Name)
+	}
+	for _, o := range d.PositionOfferingType {
+		appointments = append(appointments, o.Name)
+	}
+	fact("Schedule", strings.Join(schedules, ", "))
+	fact("Appointment", strings.Join(appointments, ", "))
+	if d.PublicationStartDate != "" || d.ApplicationCloseDate != "" {
+		fact("Open", templateDate("Jan 2, 2006", d.PublicationStartDate)+" to "+templateDate("Jan 2, 2006", d.ApplicationCloseDate))
+	}
+
+	var locations []string
+	for _, l := range d.PositionLocation {
+		locations = append(locations, l.LocationName)
+	}
+	if len(locations) == 0 {
+		locations = append(locations, d.PositionLocationDisplay)
+	}
+	fact("Locations", locations...)
+
+	fact("Who may apply", details.WhoMayApply.Name)
+	fact("Hiring paths", strings.Join(details.HiringPath, ", "))
+	fact("Openings", details.TotalOpenings)
+	fact("Clearance", details.SecurityClearance)
+	fact("Telework", yesNo(fmt.Sprint(details.TeleworkEligible)))
+	fact("Remote", yesNo(fmt.Sprint(details.RemoteIndicator)))
+	fact("Relocation", yesNo(details.Relocation))
+	fact("Drug test", yesNo(details.DrugTestRequired))
+	fact("Travel", details.TravelCode)
+	fact("URL", d.PositionURI)
+	if len(d.ApplyURI) > 0 {
+		fact("Apply", d.ApplyURI[0])
+	}
+
+	section := func(name string, texts ...string) {
+		var paragraphs []string
+		for _, t := range texts {
+			t = htmlToText(t)
+			if t != "" {
+				paragraphs = append(paragraphs, wrapText(t, width))
+			}
+		}
+		if len(paragraphs) == 0 {
+			return
+		}
+
+		b.WriteByte('\n')
+		line(heading.Render(strings.ToUpper(name)))
+		line(strings.Join(paragraphs, "\n\n"))
+	}
+
+	section("Summary", details.JobSummary, details.AgencyMarketingStatement)
+	section("Duties", bullets(details.MajorDuties)...)
+
+	var keyRequirements []string
+	for _, r := range details.KeyRequirements {
+		keyRequirements = append(keyRequirements, fmt.Sprint(r))
+	}
+	section("Requirements", append(bullets(keyRequirements), details.Requirements)...)
+	section("Qualifications", d.QualificationSummary)
+	section("Education", details.Education)
+	section("Evaluations", details.Evaluations)
+	section("Benefits", details.Benefits, details.BenefitsURL)
+	section("How to apply", details.HowToApply)
+	section("What to expect next", details.WhatToExpectNext)
+	section("Required documents", details.RequiredDocuments)
+	section("Other information", details.OtherInformation)
+	section("Contact", details.AgencyContactEmail, details.AgencyContactPhone)
+
+	return b.String()
+}
+
+// showGrades returns the pay plan and grade range of a posting (ex., GS 13 -
+// 14, promotion potential 14).
+func showGrades(d usajobs.MatchedObjectDescriptor) string {
+	details := d.UserArea.Details
+
+	grades := details.LowGrade
+	if details.HighGrade != "" && details.HighGrade != details.LowGrade {
+		grades = joinNonEmpty(" - ", grades, details.HighGrade)
+	}
+	if len(d.JobGrade) > 0 {
+		grades = joinNonEmpty(" ", d.JobGrade[0].Code, grades)
+	}
+	if details.PromotionPotential != "" && grades != "" {
+		grades += ", promotion potential " + details.PromotionPotential
+	}
+	return grades
+}
+
+// htmlToText converts the html usajobs uses in announcement text to plain
+// text, keeping line breaks and list items.
+func htmlToText(s string) string {
+	s = htmlBreak.ReplaceAllString(s, "\n")
+	s = htmlBlockEnd.ReplaceAllString(s, "\n\n")
+	s = htmlListItem.ReplaceAllString(s, "\n• ")
+	s = htmlTag.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = extraNewlines.ReplaceAllString(s, "\n\n")
+	return strings.TrimSpace(s)
+}
+
+// bullets prefixes each item with a bullet, joined as a single paragraph.
+func bullets(items []string) []string {
+	var lines []string
+	for _, i := range items {
+		i = strings.TrimSpace(i)
+		if i != "" {
+			lines = append(lines, "• "+i)
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return []string{strings.Join(lines, "\n")}
+}
+
+// yesNo converts the "True" and "False" strings usajobs uses to Yes and No.
+func yesNo(s string) string {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "true":
+		return "Yes"
+	case "false":
+		return "No"
+	}
+	return s
+}
+
+func joinNonEmpty(sep string, values ...string) string {
+	var s []string
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			s = append(s, v)
+		}
+	}
+	return strings.Join(s, sep)
+}
+
+// page writes text to stdout through the PAGER when stdout is a terminal,
+// falling back to writing it directly when the pager cannot be started.
+func page(text string) error {
+	if showNoPager || plainOutput() {
+		_, err := os.Stdout.WriteString(text)
+		return err
+	}
+
+	pager := os.Getenv("PAGER")
+	if pager == "" {
+		pager = defaultPager
+	}
+
+	args := strings.Fields(pager)
+	if len(args) == 0 {
+		_, err := os.Stdout.WriteString(text)
+		return err
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	err := cmd.Run()
+	if errors.Is(err, exec.ErrNotFound) {
+		_, err = os.Stdout.WriteString(text)
+	}
+	return err
+}
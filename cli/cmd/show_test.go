@@ -0,0 +1,105 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+)
+
+func TestShow(t *testing.T) {
+	data, err := os.ReadFile(searchTestDataPath)
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	}))
+	defer mockServer.Close()
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+
+	Client, err = usajobs.NewClient("test", "test")
+	if err != nil {
+		t.Fatalf("could not create new usajobs client: %v", err)
+	}
+	Client.BaseURL = u
+
+	defer func() { display = "summary" }()
+
+	out, err := captureStdout(t, func() error { return executeShow("ICE-24-12345-MP") })
+	if err != nil {
+		t.Fatalf("failed to execute: %v", err.Error())
+	}
+
+	for _, want := range []string{
+		"IT Specialist (INFOSEC)",
+		"ICE-24-12345-MP · 800000001",
+		"$117,962 - $183,500 Per Year",
+		"GS 13 - 14, promotion potential 14",
+		"Washington, District of Columbia",
+		"DUTIES\n• Plans and performs information technology management work.",
+		"REQUIREMENTS\n• U.S. Citizenship Required",
+		"EDUCATION",
+		"EVALUATIONS",
+		"BENEFITS",
+		"HOW TO APPLY",
+		"REQUIRED DOCUMENTS\nResume;",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q:\n%s", want, out)
+		}
+	}
+
+	display = "json"
+	out, err = captureStdout(t, func() error { return executeShow("800000001") })
+	if err != nil {
+		t.Fatalf("failed to execute with display json: %v", err.Error())
+	}
+
+	var item usajobs.SearchResultItem
+	err = json.Unmarshal([]byte(out), &item)
+	if err != nil || item.MatchedObjectDescriptor.UserArea.Details.JobSummary == "" {
+		t.Errorf("invalid json output: %v\n%s", err, out)
+	}
+
+	err = executeShow("123")
+	if !errors.Is(err, usajobs.ErrPostingNotFound) {
+		t.Errorf("expected ErrPostingNotFound, got %v", err)
+	}
+}
+
+func TestHTMLToText(t *testing.T) {
+	in := "<p>Duties include:</p><ul><li>Planning &amp; design</li><li>Testing</li></ul>Apply by 5pm<br/>EST"
+	want := "Duties include:\n\n• Planning & design\n• Testing\n\nApply by 5pm\nEST"
+	if got := htmlToText(in); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,259 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+	"github.com/mattn/go-runewidth"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarize search results by department, agency, series, grade, state, schedule and salary",
+	Long: `
+Run a search and summarize the results: the share of remote and telework postings, the
+median GS grade, the salary range, and the number of postings by department, sub-agency,
+series, grade, location state, schedule type and salary band. Every search flag is
+supported. Every page of results is summarized unless --max-results is set.
+
+Postings with several series, locations or schedules are counted once in each, so the
+percentages of those groups can add up to more than 100.
+
+Example Usage:
+
+    IT Specialist postings by agency and grade:
+    usajobs stats --job-catagory=2210 --by=agency,grade
+
+    Salary distribution of nurse postings in $10,000 bands, as CSV:
+    usajobs stats --keyword=nurse --by=salary --salary-band=10000 --display=csv
+
+    `,
+	Run: func(cmd *cobra.Command, args []string) {
+		opt, err := setSearchOptions()
+		if err != nil {
+			log.Fatal().Err(err).Msg("invalid search options")
+		}
+
+		err = executeStats(&opt)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to execute stats command")
+		}
+	},
+}
+
+var (
+	statsBy         []string
+	statsSalaryBand int
+	statsTop        int
+)
+
+// statsBarWidth is the widest a histogram bar is drawn.
+const statsBarWidth = 40
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+	statsCmd.Flags().AddFlagSet(searchFlags)
+	statsCmd.Flags().StringSliceVar(&statsBy, "by", []string{}, "[optional][Comma Separated List] groups to report (default department,agency,series,grade,state,schedule,salary)")
+	statsCmd.Flags().IntVar(&statsSalaryBand, "salary-band", usajobs.DefaultSalaryBand, "[optional] width of the salary bands in dollars a year")
+	statsCmd.Flags().IntVar(&statsTop, "top", 10, "[optional] largest groups shown in each summary histogram, 0 shows all")
+
+	var dimensions []string
+	for _, d := range usajobs.StatsDimensions {
+		dimensions = append(dimensions, string(d))
+	}
+	registerCompletions(statsCmd, map[string]completionFunc{
+		"by": func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			prefix := toComplete[:strings.LastIndex(toComplete, ",")+1]
+			var completions []string
+			for _, d := range dimensions {
+				completions = append(completions, prefix+d)
+			}
+			return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+		},
+	})
+}
+
+// searchEveryPage returns the results of every page of a search, or the
+// first --max-results of them.
+func searchEveryPage(opt *usajobs.SearchOptions) ([]usajobs.SearchResultItem, error) {
+	var items []usajobs.SearchResultItem
+	err := Client.Search.Pages(context.Background(), opt, func(page int, r *usajobs.SearchResponse) error {
+		log.Info().Msgf("page %d of %d / %d total", page, max(r.PageCount(), page), r.SearchResult.SearchResultCountAll)
+
+		items = append(items, r.SearchResult.SearchResultItems...)
+		if MaxResults > 0 && len(items) >= MaxResults {
+			items = items[:MaxResults]
+			return usajobs.SkipPages
+		}
+		return nil
+	})
+	return items, err
+}
+
+// statsDimensions returns the dimensions selected by --by.
+func statsDimensions() ([]usajobs.StatsDimension, error) {
+	if len(statsBy) == 0 {
+		return usajobs.StatsDimensions, nil
+	}
+
+	var dimensions []usajobs.StatsDimension
+	for _, s := range statsBy {
+		d, err := usajobs.ParseStatsDimension(s)
+		if err != nil {
+			return nil, err
+		}
+		dimensions = append(dimensions, d)
+	}
+	return dimensions, nil
+}
+
+func executeStats(opt *usajobs.SearchOptions) error {
+	if len(QueryFiles) > 0 || Near != "" {
+		return errors.New("--query and --near are not supported by stats")
+	}
+	// every page is summarized, so only --max-results limits the search
+	if SearchAll || opt.Page > 1 {
+		return errors.New("--all and --page are not supported by stats, every page is summarized unless --max-results is set")
+	}
+	if statsSalaryBand < 1 {
+		return errors.New("--salary-band must be 1 or greater")
+	}
+
+	dimensions, err := statsDimensions()
+	if err != nil {
+		return err
+	}
+
+	err = initClient(true)
+	if err != nil {
+		return err
+	}
+
+	items, err := searchEveryPage(opt)
+	if err != nil {
+		return err
+	}
+
+	stats := usajobs.SummarizeSearch(items, statsSalaryBand)
+	groups := map[usajobs.StatsDimension][]usajobs.StatsBucket{}
+	for _, d := range dimensions {
+		groups[d] = stats.Groups[d]
+	}
+	stats.Groups = groups
+
+	return displayStats(stats, dimensions)
+}
+
+func displayStats(stats usajobs.SearchStats, dimensions []usajobs.StatsDimension) error {
+	if format != "" {
+		return displayTemplate([]usajobs.SearchStats{stats})
+	}
+
+	headers := []string{"GROUP", "KEY", "NAME", "COUNT", "PERCENT"}
+	var data [][]string
+	type statsRow struct {
+		Group usajobs.StatsDimension `json:"group"`
+		usajobs.StatsBucket
+	}
+	var rows []statsRow
+	for _, d := range dimensions {
+		for _, b := range stats.Groups[d] {
+			data = append(data, []string{string(d), b.Key, b.Name, strconv.Itoa(b.Count), strconv.FormatFloat(b.Percent, 'f', 1, 64)})
+			rows = append(rows, statsRow{d, b})
+		}
+	}
+
+	switch display {
+	case "json":
+		return displayJSON(stats)
+	case "ndjson":
+		return displayNDJSON(rows)
+	case "csv":
+		return displayCSV(headers, data)
+	case "detail":
+		fmt.Print(renderStats(stats, dimensions, 0, terminalWidth()))
+	default:
+		fmt.Print(renderStats(stats, dimensions, statsTop, terminalWidth()))
+	}
+	return nil
+}
+
+// renderStats lays out stats as a summary followed by a histogram of each
+// dimension showing at most top groups, or every group when top is 0. Lines
+// are fit to width cells.
+func renderStats(stats usajobs.SearchStats, dimensions []usajobs.StatsDimension, top, width int) string {
+	var b strings.Builder
+	fact := func(label, value string) {
+		fmt.Fprintf(&b, "%-16s %s\n", label, value)
+	}
+
+	fact("POSTINGS", strconv.Itoa(stats.Postings))
+	fact("REMOTE", fmt.Sprintf("%d (%.1f%%)", stats.Remote, stats.RemotePercent()))
+	fact("TELEWORK", fmt.Sprintf("%d (%.1f%%)", stats.Telework, stats.TeleworkPercent()))
+	if stats.MedianGSGrade > 0 {
+		fact("MEDIAN GS GRADE", strconv.FormatFloat(stats.MedianGSGrade, 'f', -1, 64))
+	}
+	if stats.HighestSalary > 0 {
+		fact("SALARY", fmt.Sprintf("%s lowest, %s - %s median, %s highest", dollars(stats.LowestSalary),
+			dollars(stats.MedianMinSalary), dollars(stats.MedianMaxSalary), dollars(stats.HighestSalary)))
+	}
+
+	for _, d := range dimensions {
+		buckets := stats.Groups[d]
+		if len(buckets) == 0 {
+			continue
+		}
+
+		b.WriteString("\n" + strings.ToUpper(string(d)) + "\n")
+
+		shown := buckets
+		if top > 0 && len(shown) > top {
+			shown = shown[:top]
+		}
+
+		most, labelWidth, countWidth := 0, 0, 0
+		for _, bucket := range shown {
+			most = max(most, bucket.Count)
+			labelWidth = max(labelWidth, runewidth.StringWidth(bucket.Label()))
+			countWidth = max(countWidth, len(strconv.Itoa(bucket.Count)))
+		}
+
+		// label, bar, count and percent are separated by two spaces and the
+		// percent is at most 6 cells (ex., 100.0%)
+		barWidth := min(statsBarWidth, max(width/4, 10))
+		labelWidth = min(labelWidth, max(width-barWidth-countWidth-6-6, 10))
+
+		for _, bucket := range shown {
+			label := runewidth.FillRight(runewidth.Truncate(bucket.Label(), labelWidth, "…"), labelWidth)
+			bar := runewidth.FillRight(strings.Repeat("█", max(bucket.Count*barWidth/most, 1)), barWidth)
+			fmt.Fprintf(&b, "%s  %s  %*d  %5.1f%%\n", label, bar, countWidth, bucket.Count, bucket.Percent)
+		}
+		if len(shown) < len(buckets) {
+			fmt.Fprintf(&b, "… %d more\n", len(buckets)-len(shown))
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,165 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+	"github.com/mattn/go-runewidth"
+)
+
+func TestStats(t *testing.T) {
+	data, err := os.ReadFile(searchTestDataPath)
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	var full usajobs.SearchResponse
+	err = json.Unmarshal(data, &full)
+	if err != nil {
+		t.Fatalf("could not decode test data: %v", err)
+	}
+	items := full.SearchResult.SearchResultItems
+
+	// every page returns all of the test data postings
+	const pages = 2
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sr := usajobs.SearchResponse{}
+		sr.SearchResult.SearchResultItems = items
+		sr.SearchResult.SearchResultCount = len(items)
+		sr.SearchResult.SearchResultCountAll = pages * len(items)
+		sr.SearchResult.UserArea.NumberOfPages = "2"
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(sr)
+	}))
+	defer mockServer.Close()
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+
+	Client, err = usajobs.NewClient("test", "test")
+	if err != nil {
+		t.Fatalf("could not create new usajobs client: %v", err)
+	}
+	Client.BaseURL = u
+
+	defer func() {
+		display = "summary"
+		statsBy, MaxResults = nil, 0
+	}()
+
+	stats := func() string {
+		t.Helper()
+
+		out, err := captureStdout(t, func() error { return executeStats(&usajobs.SearchOptions{}) })
+		if err != nil {
+			t.Fatalf("failed to execute: %v", err)
+		}
+		return out
+	}
+
+	display = "json"
+	var got usajobs.SearchStats
+	err = json.Unmarshal([]byte(stats()), &got)
+	if err != nil {
+		t.Fatalf("could not decode json output: %v", err)
+	}
+	if got.Postings != pages*len(items) || len(got.Groups) != len(usajobs.StatsDimensions) {
+		t.Errorf("expected every page and group to be summarized, got %+v", got)
+	}
+
+	MaxResults = 4
+	statsBy = []string{"state", "Grade"}
+	got = usajobs.SearchStats{}
+	err = json.Unmarshal([]byte(stats()), &got)
+	if err != nil {
+		t.Fatalf("could not decode json output: %v", err)
+	}
+	if got.Postings != 4 || len(got.Groups) != 2 || got.Groups[usajobs.StatsState] == nil {
+		t.Errorf("expected 4 postings grouped by state and grade, got %+v", got)
+	}
+
+	display = "csv"
+	records, err := csv.NewReader(strings.NewReader(stats())).ReadAll()
+	if err != nil {
+		t.Fatalf("could not read csv output: %v", err)
+	}
+	if strings.Join(records[0], ",") != "GROUP,KEY,NAME,COUNT,PERCENT" || strings.Join(records[1], ",") != "state,Texas,,3,75.0" {
+		t.Errorf("unexpected csv output %v", records)
+	}
+
+	display = "summary"
+	out := stats()
+	for _, want := range []string{"POSTINGS         4", "REMOTE           1 (25.0%)", "MEDIAN GS GRADE  13", "\nSTATE\nTexas", "\nGRADE\nGS-12"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected summary to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	statsBy = []string{"planet"}
+	_, err = captureStdout(t, func() error { return executeStats(&usajobs.SearchOptions{}) })
+	if err == nil {
+		t.Error("expected error for unknown --by group")
+	}
+
+	statsBy = nil
+	_, err = captureStdout(t, func() error { return executeStats(&usajobs.SearchOptions{Page: 2}) })
+	if err == nil || !strings.Contains(err.Error(), "--page") {
+		t.Errorf("expected error for --page, got %v", err)
+	}
+
+	SearchAll = true
+	_, err = captureStdout(t, func() error { return executeStats(&usajobs.SearchOptions{}) })
+	SearchAll = false
+	if err == nil || !strings.Contains(err.Error(), "--all") {
+		t.Errorf("expected error for --all, got %v", err)
+	}
+}
+
+func TestRenderStats(t *testing.T) {
+	stats := usajobs.SearchStats{
+		Postings: 4,
+		Groups: map[usajobs.StatsDimension][]usajobs.StatsBucket{
+			usajobs.StatsAgency: {
+				{Key: "A very long agency name that does not fit in a narrow terminal", Count: 3, Percent: 75},
+				{Key: "Short", Count: 1, Percent: 25},
+			},
+		},
+	}
+
+	out := renderStats(stats, []usajobs.StatsDimension{usajobs.StatsAgency}, 1, 60)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	last := lines[len(lines)-1]
+	if last != "… 1 more" {
+		t.Errorf("expected the hidden groups to be counted, got %q", last)
+	}
+
+	bar := lines[len(lines)-2]
+	if runewidth.StringWidth(bar) > 60 || !strings.Contains(bar, "…") || !strings.HasSuffix(bar, "3   75.0%") {
+		t.Errorf("expected a truncated label fit to 60 cells, got %q", bar)
+	}
+}
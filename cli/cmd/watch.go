@@ -0,0 +1,363 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+	"github.com/JeffRDay/go-usajobs/savedsearch"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// watchCmd represents the watch command
+var watchCmd = &cobra.Command{
+	Use:   "watch [name...]",
+	Short: "Run saved searches and report postings that changed since the last run",
+	Long: `
+Run saved searches and report what changed since each search last ran: new postings,
+postings that closed or were removed, and postings whose close date or salary changed.
+Runs every saved search unless names are provided.
+
+Example Usage:
+
+    Save a search from a query file and run it daily:
+    usajobs watch add it-austin --query=it-austin.json
+    usajobs watch --token=$TOKEN --user-agent=$EMAIL
+
+    Post changes to a Slack channel and email them through an SMTP relay:
+    usajobs watch add it-austin --query=it-austin.json --slack=$SLACK_WEBHOOK_URL \
+        --email=me@example.com --smtp-host=smtp.example.com --smtp-from=usajobs@example.com \
+        --smtp-user=usajobs@example.com --smtp-password-env=SMTP_PASSWORD
+
+    `,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := executeWatch(args)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to execute watch command")
+		}
+	},
+}
+
+// watchAddCmd represents the watch add command
+var watchAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Save a search to watch",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := executeWatchAdd(args[0], watchQueryFile, watchNotifiers())
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to execute watch add command")
+		}
+	},
+}
+
+// watchListCmd represents the watch list command
+var watchListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved searches",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := executeWatchList()
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to execute watch list command")
+		}
+	},
+}
+
+// watchRemoveCmd represents the watch remove command
+var watchRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a saved search and its history",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := executeWatchRemove(args[0])
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to execute watch remove command")
+		}
+	},
+}
+
+var (
+	watchDir       string
+	watchQueryFile string
+	watchNoNotify  bool
+
+	watchWebhooks    []string
+	watchSlack       []string
+	watchTeams       []string
+	watchEmail       []string
+	watchSMTPHost    string
+	watchSMTPPort    int
+	watchSMTPFrom    string
+	watchSMTPUser    string
+	watchSMTPPassEnv string
+)
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	watchCmd.AddCommand(watchAddCmd)
+	watchCmd.AddCommand(watchListCmd)
+	watchCmd.AddCommand(watchRemoveCmd)
+
+	watchCmd.PersistentFlags().StringVar(&watchDir, "dir", "", "[optional] directory saved searches are kept in (default ~/.config/usajobs)")
+	watchCmd.Flags().BoolVar(&watchNoNotify, "no-notify", false, "[optional] report changes without sending notifications")
+	watchAddCmd.Flags().StringVar(&watchQueryFile, "query", "", "[required] JSON file of search options to save")
+	watchAddCmd.Flags().StringArrayVar(&watchWebhooks, "webhook", nil, "[optional] URL to post changes to as JSON, may be repeated")
+	watchAddCmd.Flags().StringArrayVar(&watchSlack, "slack", nil, "[optional] Slack incoming webhook URL, may be repeated")
+	watchAddCmd.Flags().StringArrayVar(&watchTeams, "teams", nil, "[optional] Microsoft Teams incoming webhook URL, may be repeated")
+	watchAddCmd.Flags().StringSliceVar(&watchEmail, "email", nil, "[optional] comma separated email addresses to send changes to, requires --smtp-host and --smtp-from")
+	watchAddCmd.Flags().StringVar(&watchSMTPHost, "smtp-host", "", "[optional] SMTP server used to send email")
+	watchAddCmd.Flags().IntVar(&watchSMTPPort, "smtp-port", 587, "[optional] SMTP server port")
+	watchAddCmd.Flags().StringVar(&watchSMTPFrom, "smtp-from", "", "[optional] address email is sent from")
+	watchAddCmd.Flags().StringVar(&watchSMTPUser, "smtp-user", "", "[optional] SMTP username")
+	watchAddCmd.Flags().StringVar(&watchSMTPPassEnv, "smtp-password-env", "", "[optional] name of the environment variable holding the SMTP password")
+	err := watchAddCmd.MarkFlagRequired("query")
+	if err != nil {
+		log.Fatal().Err(err).Msg("query flag must be set")
+	}
+}
+
+func openWatchStore() (*savedsearch.Store, error) {
+	dir := watchDir
+	if dir == "" {
+		var err error
+		dir, err = savedsearch.DefaultDir()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return savedsearch.NewStore(dir)
+}
+
+// watchNotifiers returns the notifiers configured by the watch add flags.
+func watchNotifiers() []savedsearch.NotifierConfig {
+	var notify []savedsearch.NotifierConfig
+	for _, u := range watchWebhooks {
+		notify = append(notify, savedsearch.NotifierConfig{Type: savedsearch.NotifierWebhook, URL: u})
+	}
+	for _, u := range watchSlack {
+		notify = append(notify, savedsearch.NotifierConfig{Type: savedsearch.NotifierSlack, URL: u})
+	}
+	for _, u := range watchTeams {
+		notify = append(notify, savedsearch.NotifierConfig{Type: savedsearch.NotifierTeams, URL: u})
+	}
+	if len(watchEmail) > 0 {
+		notify = append(notify, savedsearch.NotifierConfig{Type: savedsearch.NotifierSMTP, SMTP: &savedsearch.SMTPConfig{
+			Host:        watchSMTPHost,
+			Port:        watchSMTPPort,
+			Username:    watchSMTPUser,
+			PasswordEnv: watchSMTPPassEnv,
+			From:        watchSMTPFrom,
+			To:          watchEmail,
+		}})
+	}
+	return notify
+}
+
+func executeWatchAdd(name, queryFile string, notify []savedsearch.NotifierConfig) error {
+	store, err := openWatchStore()
+	if err != nil {
+		return err
+	}
+
+	opts, err := loadSearchQueries(usajobs.SearchOptions{}, []string{queryFile})
+	if err != nil {
+		return err
+	}
+
+	for _, n := range notify {
+		_, err := n.Notifier()
+		if err != nil {
+			return err
+		}
+		if n.SMTP != nil && (n.SMTP.Host == "" || n.SMTP.From == "") {
+			return errors.New("--email requires --smtp-host and --smtp-from")
+		}
+	}
+
+	return store.Save(savedsearch.SavedSearch{Name: name, Options: opts[0], Notify: notify})
+}
+
+func executeWatchList() error {
+	store, err := openWatchStore()
+	if err != nil {
+		return err
+	}
+
+	searches, err := store.List()
+	if err != nil {
+		return err
+	}
+
+	headers := []string{"NAME", "LAST_RUN", "POSTINGS", "NOTIFY"}
+	var data [][]string
+	for _, s := range searches {
+		lastRun, postings := "never", ""
+		run, err := store.LastRun(s.Name)
+		if err == nil {
+			lastRun = run.RunAt.Local().Format(time.DateTime)
+			postings = fmt.Sprint(len(run.Postings))
+		} else if !errors.Is(err, savedsearch.ErrNotFound) {
+			return err
+		}
+		var notify []string
+		for _, n := range s.Notify {
+			notify = append(notify, n.Type)
+		}
+		data = append(data, []string{s.Name, lastRun, postings, strings.Join(notify, ", ")})
+	}
+
+	return displayTable(headers, data)
+}
+
+func executeWatchRemove(name string) error {
+	store, err := openWatchStore()
+	if err != nil {
+		return err
+	}
+
+	return store.Delete(name)
+}
+
+func executeWatch(names []string) error {
+	store, err := openWatchStore()
+	if err != nil {
+		return err
+	}
+
+	var searches []savedsearch.SavedSearch
+	if len(names) == 0 {
+		searches, err = store.List()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range names {
+		s, err := store.Get(name)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		searches = append(searches, s)
+	}
+
+	if len(searches) == 0 {
+		log.Info().Msg("no saved searches, add one with 'usajobs watch add'")
+		return nil
+	}
+
+	err = initClient(true)
+	if err != nil {
+		return err
+	}
+
+	var all []savedsearch.Changes
+	var notifyErrs []error
+	for _, s := range searches {
+		changes, snapshot, err := savedsearch.Run(context.Background(), Client, store, s, time.Now())
+		if err != nil {
+			return fmt.Errorf("%s: %w", s.Name, err)
+		}
+		all = append(all, changes)
+
+		// a failed notification should not hide the changes or stop the
+		// remaining searches from running, and the run is not recorded so
+		// the changes are sent again next time
+		if !watchNoNotify {
+			err = savedsearch.NotifyAll(context.Background(), s, changes)
+			if err != nil {
+				notifyErrs = append(notifyErrs, fmt.Errorf("%s: %w, the run was not recorded", s.Name, err))
+				continue
+			}
+		}
+
+		err = store.SaveRun(s.Name, snapshot)
+		if err != nil {
+			return fmt.Errorf("%s: %w", s.Name, err)
+		}
+	}
+
+	err = displayWatchChanges(all)
+	if err != nil {
+		return err
+	}
+
+	return errors.Join(notifyErrs...)
+}
+
+func displayWatchChanges(all []savedsearch.Changes) error {
+	switch display {
+	case "json":
+		return displayJSON(all)
+	case "ndjson":
+		return displayNDJSON(all)
+	}
+
+	headers := []string{"SEARCH", "CHANGE", "JOB_TITLE", "DEPARTMENT", "DETAIL", "URL"}
+	var data [][]string
+
+	row := func(search, change string, p savedsearch.Posting, detail string) []string {
+		return []string{search, change, p.PositionTitle, p.DepartmentName, detail, p.PositionURI}
+	}
+
+	for _, c := range all {
+		for _, p := range c.New {
+			data = append(data, row(c.Search, "new", p, "closes "+p.CloseDate))
+		}
+		for _, p := range c.Closed {
+			data = append(data, row(c.Search, "closed", p, "closed "+p.CloseDate))
+		}
+		for _, p := range c.Removed {
+			data = append(data, row(c.Search, "removed", p, "no longer returned by the search"))
+		}
+		for _, u := range c.Updated {
+			var details []string
+			for _, f := range u.Fields {
+				switch f {
+				case "CloseDate":
+					details = append(details, fmt.Sprintf("close date %s -> %s", u.Before.CloseDate, u.After.CloseDate))
+				case "MinimumSalary":
+					details = append(details, fmt.Sprintf("minimum salary %s -> %s", u.Before.MinimumSalary, u.After.MinimumSalary))
+				case "MaximumSalary":
+					details = append(details, fmt.Sprintf("maximum salary %s -> %s", u.Before.MaximumSalary, u.After.MaximumSalary))
+				}
+			}
+			data = append(data, row(c.Search, "updated", u.After, strings.Join(details, "\n")))
+		}
+	}
+
+	switch display {
+	case "csv":
+		return displayCSV(headers, data)
+	default:
+		if len(data) == 0 {
+			log.Info().Msg("no changes since the last run")
+			return nil
+		}
+
+		for i := range data {
+			data[i][2] = addNewLines(data[i][2], 30)
+			data[i][3] = addNewLines(data[i][3], 20)
+			data[i][5] = addNewLines(data[i][5], 60)
+		}
+		return displayTable(headers, data)
+	}
+}
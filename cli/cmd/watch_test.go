@@ -0,0 +1,161 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+	"github.com/JeffRDay/go-usajobs/savedsearch"
+)
+
+func TestWatch(t *testing.T) {
+	data, err := os.ReadFile(searchTestDataPath)
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	// Create a mock server that returns the JSON data
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Log(r.URL.String())
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	}))
+	defer mockServer.Close()
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+
+	Client, err = usajobs.NewClient("test", "test")
+	if err != nil {
+		panic(err.Error())
+	}
+	Client.BaseURL = u
+
+	watchDir = t.TempDir()
+	defer func() { watchDir = "" }()
+
+	query := filepath.Join(t.TempDir(), "it.json")
+	os.WriteFile(query, []byte(`{"JobCategoryCode": ["2210"]}`), 0o600)
+
+	err = executeWatchAdd("it", query, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// the first run reports every posting as new, the second run nothing
+	err = executeWatch(nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = executeWatch([]string{"it"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = executeWatchList()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = executeWatch([]string{"missing"})
+	if err == nil {
+		t.Fatal("expected error for missing saved search, got nil")
+	}
+
+	err = executeWatchRemove("it")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+}
+
+func TestWatchNotify(t *testing.T) {
+	data, err := os.ReadFile(searchTestDataPath)
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	}))
+	defer mockServer.Close()
+
+	// the first post fails
+	var posts int
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts++
+		if posts == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer hook.Close()
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+
+	Client, err = usajobs.NewClient("test", "test")
+	if err != nil {
+		panic(err.Error())
+	}
+	Client.BaseURL = u
+
+	watchDir = t.TempDir()
+	defer func() { watchDir = "" }()
+
+	query := filepath.Join(t.TempDir(), "it.json")
+	os.WriteFile(query, []byte(`{"JobCategoryCode": ["2210"]}`), 0o600)
+
+	err = executeWatchAdd("it", query, []savedsearch.NotifierConfig{{Type: savedsearch.NotifierSlack}})
+	if err == nil {
+		t.Fatal("expected error for slack notifier without a url, got nil")
+	}
+
+	err = executeWatchAdd("it", query, []savedsearch.NotifierConfig{{Type: savedsearch.NotifierWebhook, URL: hook.URL}})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// a failed notification leaves the run unrecorded, so the next run
+	// sends the same changes again and only then has nothing to send
+	err = executeWatch(nil)
+	if err == nil {
+		t.Fatal("expected error for a failed notification, got nil")
+	}
+
+	for i := 0; i < 2; i++ {
+		err = executeWatch(nil)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	if posts != 2 {
+		t.Errorf("expected 2 webhook posts, got %d", posts)
+	}
+}
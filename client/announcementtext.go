@@ -0,0 +1,212 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package usajobs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// defaultAnnouncementTextConcurrency is the number of requests ByPositionIDs
+// keeps in flight when the caller does not set a limit.
+const defaultAnnouncementTextConcurrency = 4
+
+// AnnouncementTextService is used for interacting with the
+// /historicjoa/announcementtext endpoint of the usajobs api. The endpoint
+// returns the full text of a job announcement, which /search only summarizes.
+type AnnouncementTextService struct {
+	Client *Client
+}
+
+// NewAnnouncementTextService instatiates and returns an announcement text
+// service for this client.
+func NewAnnouncementTextService(c *Client) *AnnouncementTextService {
+	as := new(AnnouncementTextService)
+	as.Client = c
+	return as
+}
+
+// AnnouncementTextOptions are the url query parameters supported by the
+// /historicjoa/announcementtext usajobs api endpoint. Announcement numbers
+// are the PositionID values returned by /search and control numbers are the
+// MatchedObjectID values. Dates use the YYYY-MM-DD format.
+type AnnouncementTextOptions struct {
+	AnnouncementNumbers    []string `url:"AnnouncementNumbers,omitempty" del:","`
+	USAJOBSControlNumbers  []string `url:"USAJOBSControlNumbers,omitempty" del:","`
+	StartPositionOpenDate  string   `url:"StartPositionOpenDate,omitempty"`
+	EndPositionOpenDate    string   `url:"EndPositionOpenDate,omitempty"`
+	StartPositionCloseDate string   `url:"StartPositionCloseDate,omitempty"`
+	EndPositionCloseDate   string   `url:"EndPositionCloseDate,omitempty"`
+	ContinuationToken      string   `url:"continuationtoken,omitempty"`
+}
+
+// AnnouncementTextResponse is the golang struct implementation of all
+// possible response fields from /historicjoa/announcementtext. Consumers are
+// responsible for ensuring omitted fields do not cause errors in consumer
+// implementations.
+type AnnouncementTextResponse struct {
+	Paging HistoricPaging     `json:"paging,omitempty"`
+	Data   []AnnouncementText `json:"data,omitempty"`
+}
+
+// AnnouncementText is the full text of a single job announcement.
+type AnnouncementText struct {
+	USAJOBSControlNumber               int64  `json:"usajobsControlNumber,omitempty"`
+	Summary                            string `json:"summary,omitempty"`
+	HiringPathExplanation              string `json:"hiringPathExplanation,omitempty"`
+	Duties                             string `json:"duties,omitempty"`
+	MajorDutiesList                    string `json:"majorDutiesList,omitempty"`
+	RequirementsConditionsOfEmployment string `json:"requirementsConditionsOfEmployment,omitempty"`
+	RequirementsQualifications         string `json:"requirementsQualifications,omitempty"`
+	RequirementsEducation              string `json:"requirementsEducation,omitempty"`
+	RequirementsAdditionalInformation  string `json:"requirementsAdditionalInformation,omitempty"`
+	HowYouWillBeEvaluated              string `json:"howYouWillBeEvaluated,omitempty"`
+	RequiredStandardDocuments          string `json:"requiredStandardDocuments,omitempty"`
+	RequiredDocuments                  string `json:"requiredDocuments,omitempty"`
+	HowToApply                         string `json:"howToApply,omitempty"`
+	NextSteps                          string `json:"nextSteps,omitempty"`
+	Benefits                           string `json:"benefits,omitempty"`
+	OtherInformation                   string `json:"otherInformation,omitempty"`
+}
+
+// AnnouncementSection is a titled section of an announcement's text.
+type AnnouncementSection struct {
+	Title   string
+	Content string
+}
+
+// Sections returns the non-empty sections of the announcement in the order
+// usajobs displays them.
+func (a AnnouncementText) Sections() []AnnouncementSection {
+	all := []AnnouncementSection{
+		{Title: "Summary", Content: a.Summary},
+		{Title: "Hiring Path", Content: a.HiringPathExplanation},
+		{Title: "Duties", Content: a.Duties},
+		{Title: "Major Duties", Content: a.MajorDutiesList},
+		{Title: "Conditions of Employment", Content: a.RequirementsConditionsOfEmployment},
+		{Title: "Qualifications", Content: a.RequirementsQualifications},
+		{Title: "Education", Content: a.RequirementsEducation},
+		{Title: "Additional Information", Content: a.RequirementsAdditionalInformation},
+		{Title: "How You Will Be Evaluated", Content: a.HowYouWillBeEvaluated},
+		{Title: "Required Standard Documents", Content: a.RequiredStandardDocuments},
+		{Title: "Required Documents", Content: a.RequiredDocuments},
+		{Title: "How to Apply", Content: a.HowToApply},
+		{Title: "Next Steps", Content: a.NextSteps},
+		{Title: "Benefits", Content: a.Benefits},
+		{Title: "Other Information", Content: a.OtherInformation},
+	}
+
+	var sections []AnnouncementSection
+	for _, s := range all {
+		if s.Content != "" {
+			sections = append(sections, s)
+		}
+	}
+	return sections
+}
+
+// WithOptions executes a single request to the usajobs
+// /historicjoa/announcementtext endpoint with the provided options and
+// returns one page of results. Pass nil if no options desired.
+func (as *AnnouncementTextService) WithOptions(opt *AnnouncementTextOptions) (*http.Response, *AnnouncementTextResponse, error) {
+	usajobsEndpoint := "/historicjoa/announcementtext"
+	responseObject := new(AnnouncementTextResponse)
+	r, object, err := as.Client.NewResponse(usajobsEndpoint, opt, responseObject)
+	return r, object.(*AnnouncementTextResponse), err
+}
+
+// All requests every page of results for the provided options, following
+// the continuation token returned by usajobs, and returns the combined
+// announcement text. Pass nil if no options desired.
+func (as *AnnouncementTextService) All(opt *AnnouncementTextOptions) ([]AnnouncementText, error) {
+	page := AnnouncementTextOptions{}
+	if opt != nil {
+		page = *opt
+	}
+
+	var all []AnnouncementText
+	seen := map[string]bool{}
+	for {
+		r, data, err := as.WithOptions(&page)
+		if err != nil {
+			return all, err
+		}
+
+		if r.StatusCode != http.StatusOK {
+			return all, errors.New("bad response from usajobs: " + r.Status)
+		}
+
+		all = append(all, data.Data...)
+
+		token := data.Paging.Metadata.ContinuationToken
+		if token == "" || seen[token] {
+			return all, nil
+		}
+		seen[token] = true
+		page.ContinuationToken = token
+	}
+}
+
+// ByPositionIDs fetches the announcement text for each of the provided
+// PositionIDs (announcement numbers) with at most concurrency requests in
+// flight; a concurrency less than 1 uses a default of 4. Results are keyed by
+// PositionID. An announcement number can be reused by more than one posting,
+// so each PositionID maps to every matching announcement. Text that was
+// fetched is returned alongside any errors, which are joined.
+func (as *AnnouncementTextService) ByPositionIDs(ids []string, concurrency int) (map[string][]AnnouncementText, error) {
+	if concurrency < 1 {
+		concurrency = defaultAnnouncementTextConcurrency
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		errs    []error
+		results = make(map[string][]AnnouncementText, len(ids))
+		sem     = make(chan struct{}, concurrency)
+	)
+
+	for _, id := range ids {
+		mu.Lock()
+		_, dup := results[id]
+		results[id] = nil
+		mu.Unlock()
+		if dup {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(id string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			text, err := as.All(&AnnouncementTextOptions{AnnouncementNumbers: []string{id}})
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[id] = text
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", id, err))
+			}
+		}(id)
+	}
+
+	wg.Wait()
+	return results, errors.Join(errs...)
+}
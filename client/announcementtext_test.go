@@ -0,0 +1,154 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package usajobs_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+)
+
+func TestAnnouncementText(t *testing.T) {
+	data, err := os.ReadFile("../testdata/announcementtext-testdata.json")
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	// Create a mock server that returns the JSON data
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Log(r.URL.String())
+
+		if strings.Contains(r.URL.String(), "/historicjoa/announcementtext") {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			w.Write(data)
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	c, err := usajobs.NewClient("test", "test")
+	if err != nil {
+		t.Fatalf("could not create new usajobs client: %v", err)
+	}
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+
+	c.BaseURL = u
+
+	_, res, err := c.AnnouncementText.WithOptions(&usajobs.AnnouncementTextOptions{
+		USAJOBSControlNumbers: []string{"800000001"},
+	})
+	if err != nil {
+		t.Fatalf("failed to execute announcement text request: %v", err.Error())
+	}
+
+	if len(res.Data) != 1 {
+		t.Fatalf("expected %d announcement, got %d", 1, len(res.Data))
+	}
+
+	sections := res.Data[0].Sections()
+	if len(sections) != 14 {
+		t.Fatalf("expected %d non-empty sections, got %d", 14, len(sections))
+	}
+
+	if sections[0].Title != "Summary" {
+		t.Errorf("expected first section to be Summary, got %s", sections[0].Title)
+	}
+}
+
+func TestAnnouncementTextByPositionIDs(t *testing.T) {
+	data, err := os.ReadFile("../testdata/announcementtext-testdata.json")
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	var inFlight, maxInFlight int32
+
+	// Create a mock server that tracks how many requests are in flight
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		if r.URL.Query().Get("AnnouncementNumbers") == "MISSING" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	}))
+	defer mockServer.Close()
+
+	c, err := usajobs.NewClient("test", "test")
+	if err != nil {
+		t.Fatalf("could not create new usajobs client: %v", err)
+	}
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+
+	c.BaseURL = u
+
+	ids := []string{"A-1", "A-2", "A-3", "A-4", "A-5", "A-1"}
+	res, err := c.AnnouncementText.ByPositionIDs(ids, 2)
+	if err != nil {
+		t.Fatalf("failed to fetch announcement text: %v", err.Error())
+	}
+
+	if len(res) != 5 {
+		t.Fatalf("expected %d position ids, got %d", 5, len(res))
+	}
+
+	if len(res["A-3"]) != 1 || res["A-3"][0].USAJOBSControlNumber != 800000001 {
+		t.Errorf("expected announcement text for A-3, got %+v", res["A-3"])
+	}
+
+	if maxInFlight > 2 {
+		t.Errorf("expected at most %d requests in flight, got %d", 2, maxInFlight)
+	}
+
+	res, err = c.AnnouncementText.ByPositionIDs([]string{"A-1", "MISSING"}, 0)
+	if err == nil {
+		t.Fatal("expected error for missing announcement, got nil")
+	}
+
+	if len(res["A-1"]) != 1 {
+		t.Errorf("expected text for A-1 to be returned alongside the error, got %+v", res["A-1"])
+	}
+}
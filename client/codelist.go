@@ -0,0 +1,248 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package usajobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// codeListCatalogConcurrency is the number of /codelist requests
+// CodeListCatalog keeps in flight at once.
+const codeListCatalogConcurrency = 4
+
+// CodeListNames are the names of every /codelist endpoint supported by
+// CodeList, which match the typed codelist services of the Client.
+var CodeListNames = []string{
+	"academichonors",
+	"academiclevels",
+	"agencysubelements",
+	"applicantsuppliers",
+	"applicationstatuses",
+	"countries",
+	"countrysubdivisions",
+	"cyberworkgroupings",
+	"cyberworkroles",
+	"degreetypecodes",
+	"disabilities",
+	"documentations",
+	"documentformats",
+	"ethnicities",
+	"federalemploymentstatuses",
+	"geoloccodes",
+	"gsageoloccodes",
+	"hiringpaths",
+	"keystandardrequirements",
+	"languagecodes",
+	"languageproficiencies",
+	"locationexpansions",
+	"militarystatuscodes",
+	"missioncriticalcodes",
+	"occupationalseries",
+	"payplans",
+	"positionofferingtypes",
+	"positionopeningstatuses",
+	"positionscheduletypes",
+	"postalcodes",
+	"racecodes",
+	"refereetypecodes",
+	"remunerationrateintervalcodes",
+	"requiredstandarddocuments",
+	"securityclearances",
+	"servicetypes",
+	"specialhirings",
+	"travelpercentages",
+	"whomayapply",
+}
+
+// CodeListValue is a single value of any codelist. Fields specific to one
+// codelist (ex., JobFamily for occupationalseries) are kept in Fields.
+type CodeListValue struct {
+	Code         string `json:"Code,omitempty"`
+	Value        string `json:"Value,omitempty"`
+	LastModified string `json:"LastModified,omitempty"`
+	IsDisabled   string `json:"IsDisabled,omitempty"`
+	// Fields holds the codelist specific fields, keyed by their usajobs
+	// name. Values that are not strings in the response (ex., Latitude) are
+	// kept as their JSON text.
+	Fields map[string]string `json:"-"`
+}
+
+// UnmarshalJSON decodes the common fields and collects every other field of
+// the value into Fields.
+func (v *CodeListValue) UnmarshalJSON(b []byte) error {
+	var raw map[string]json.RawMessage
+	err := json.Unmarshal(b, &raw)
+	if err != nil {
+		return err
+	}
+
+	*v = CodeListValue{}
+	for key, value := range raw {
+		var s string
+		if json.Unmarshal(value, &s) != nil {
+			s = string(value)
+			if s == "null" {
+				s = ""
+			}
+		}
+
+		switch key {
+		case "Code":
+			v.Code = s
+		case "Value":
+			v.Value = s
+		case "LastModified":
+			v.LastModified = s
+		case "IsDisabled":
+			v.IsDisabled = s
+		default:
+			if v.Fields == nil {
+				v.Fields = map[string]string{}
+			}
+			v.Fields[key] = s
+		}
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes the value with its codelist specific fields alongside
+// the common fields, matching the usajobs response.
+func (v CodeListValue) MarshalJSON() ([]byte, error) {
+	m := make(map[string]string, len(v.Fields)+4)
+	for key, value := range v.Fields {
+		m[key] = value
+	}
+
+	set := func(key, value string) {
+		if value != "" {
+			m[key] = value
+		}
+	}
+	set("Code", v.Code)
+	set("Value", v.Value)
+	set("LastModified", v.LastModified)
+	set("IsDisabled", v.IsDisabled)
+
+	return json.Marshal(m)
+}
+
+// CodeListResponse is the response shape shared by every /codelist endpoint.
+type CodeListResponse struct {
+	CodeList []struct {
+		ValidValue []CodeListValue `json:"ValidValue,omitempty"`
+		ID         string          `json:"id,omitempty"`
+	} `json:"CodeList,omitempty"`
+	DateGenerated string `json:"DateGenerated,omitempty"`
+}
+
+// Values returns the values of every codelist in the response.
+func (r *CodeListResponse) Values() []CodeListValue {
+	var values []CodeListValue
+	for _, c := range r.CodeList {
+		values = append(values, c.ValidValue...)
+	}
+	return values
+}
+
+// FieldNames returns the names of the codelist specific fields used by any
+// value in the response, sorted.
+func (r *CodeListResponse) FieldNames() []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, v := range r.Values() {
+		for name := range v.Fields {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CodeList executes a request to the usajobs /codelist/{name} endpoint, for
+// example CodeList("occupationalseries").
+func (c *Client) CodeList(name string) (*http.Response, *CodeListResponse, error) {
+	return c.CodeListContext(context.Background(), name)
+}
+
+// CodeListContext is CodeList with a context that controls the lifetime of
+// the request.
+func (c *Client) CodeListContext(ctx context.Context, name string) (*http.Response, *CodeListResponse, error) {
+	if name == "" {
+		return nil, nil, errors.New("codelist name required")
+	}
+
+	usajobsEndpoint := "/codelist/" + url.PathEscape(strings.ToLower(name))
+	responseObject := new(CodeListResponse)
+	r, object, err := c.NewResponseWithContext(ctx, usajobsEndpoint, nil, responseObject)
+	return r, object.(*CodeListResponse), err
+}
+
+// CodeListSummary describes a single codelist.
+type CodeListSummary struct {
+	Name          string `json:"name"`
+	Count         int    `json:"count"`
+	DateGenerated string `json:"dateGenerated"`
+}
+
+// CodeListCatalog requests every codelist in CodeListNames and returns a
+// summary of each, in the same order. Summaries of the codelists that were
+// fetched are returned alongside any errors, which are joined.
+func (c *Client) CodeListCatalog() ([]CodeListSummary, error) {
+	summaries := make([]CodeListSummary, len(CodeListNames))
+	errs := make([]error, len(CodeListNames))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, codeListCatalogConcurrency)
+	for i, name := range CodeListNames {
+		summaries[i].Name = name
+
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			r, data, err := c.CodeList(name)
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", name, err)
+				return
+			}
+
+			if r.StatusCode != http.StatusOK {
+				errs[i] = fmt.Errorf("%s: bad response from usajobs: %s", name, r.Status)
+				return
+			}
+
+			summaries[i].Count = len(data.Values())
+			summaries[i].DateGenerated = data.DateGenerated
+		}(i, name)
+	}
+	wg.Wait()
+
+	return summaries, errors.Join(errs...)
+}
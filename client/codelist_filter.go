@@ -0,0 +1,145 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package usajobs
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// codeListDateLayout is the layout of the LastModified date of codelist
+// values.
+const codeListDateLayout = "2006-01-02T15:04:05.999999999"
+
+// codeListParentFields are the codelist specific fields that refer to the
+// parent of a value in hierarchical codelists (ex., the agency of an
+// agencysubelement or the country of a countrysubdivision).
+var codeListParentFields = []string{"ParentCode", "CountryCode", "CountrySubdivisionCode", "GeoLocCode"}
+
+// CodeListFilter selects values of a codelist. The zero value selects every
+// value that is not disabled.
+type CodeListFilter struct {
+	// Contains selects values whose code or value contains it, ignoring case.
+	Contains string
+	// Pattern selects values whose code or value matches it.
+	Pattern *regexp.Regexp
+	// Parent selects values of hierarchical codelists whose parent is Parent,
+	// ignoring case (ex., "AF" for agencysubelements, "US" for
+	// countrysubdivisions or "Texas" for geoloccodes).
+	Parent string
+	// IncludeDisabled selects values usajobs has disabled.
+	IncludeDisabled bool
+	// ModifiedSince selects values modified at or after it, when it is not
+	// the zero time.
+	ModifiedSince time.Time
+	// Limit is the maximum number of values selected, or no limit when 0.
+	Limit int
+}
+
+// Disabled reports whether usajobs has disabled the value.
+func (v CodeListValue) Disabled() bool {
+	return strings.EqualFold(strings.TrimSpace(v.IsDisabled), "yes")
+}
+
+// Modified returns when the value was last modified, or the zero time when
+// it cannot be parsed.
+func (v CodeListValue) Modified() time.Time {
+	t, err := time.Parse(codeListDateLayout, strings.TrimSpace(v.LastModified))
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// Match reports whether the filter selects v, without regard to Limit.
+func (f CodeListFilter) Match(v CodeListValue) bool {
+	if !f.IncludeDisabled && v.Disabled() {
+		return false
+	}
+
+	if f.Contains != "" {
+		s := strings.ToLower(f.Contains)
+		if !strings.Contains(strings.ToLower(v.Code), s) && !strings.Contains(strings.ToLower(v.Value), s) {
+			return false
+		}
+	}
+
+	if f.Pattern != nil && !f.Pattern.MatchString(v.Code) && !f.Pattern.MatchString(v.Value) {
+		return false
+	}
+
+	if f.Parent != "" {
+		parent := strings.TrimSpace(f.Parent)
+		found := false
+		for _, field := range codeListParentFields {
+			if strings.EqualFold(strings.TrimSpace(v.Fields[field]), parent) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if !f.ModifiedSince.IsZero() {
+		modified := v.Modified()
+		if modified.IsZero() || modified.Before(f.ModifiedSince) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Apply returns the values the filter selects, in order, up to Limit.
+func (f CodeListFilter) Apply(values []CodeListValue) []CodeListValue {
+	var selected []CodeListValue
+	for _, v := range values {
+		if f.Limit > 0 && len(selected) >= f.Limit {
+			break
+		}
+		if f.Match(v) {
+			selected = append(selected, v)
+		}
+	}
+	return selected
+}
+
+// Filter returns a copy of the response with only the values the filter
+// selects. Limit applies to the values of every codelist in the response
+// combined.
+func (r *CodeListResponse) Filter(f CodeListFilter) *CodeListResponse {
+	filtered := &CodeListResponse{DateGenerated: r.DateGenerated}
+	filtered.CodeList = append(filtered.CodeList, r.CodeList...)
+
+	remaining := f.Limit
+	for i := range filtered.CodeList {
+		if f.Limit > 0 {
+			f.Limit = remaining
+			if remaining == 0 {
+				filtered.CodeList[i].ValidValue = nil
+				continue
+			}
+		}
+
+		filtered.CodeList[i].ValidValue = f.Apply(filtered.CodeList[i].ValidValue)
+		remaining -= len(filtered.CodeList[i].ValidValue)
+	}
+
+	return filtered
+}
@@ -0,0 +1,115 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package usajobs_test
+
+import (
+	"encoding/json"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+)
+
+func loadCodeList(t *testing.T, name string) *usajobs.CodeListResponse {
+	t.Helper()
+
+	data, err := os.ReadFile("../testdata/" + name + "-testdata.json")
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	var resp usajobs.CodeListResponse
+	err = json.Unmarshal(data, &resp)
+	if err != nil {
+		t.Fatalf("could not unmarshal test data: %v", err)
+	}
+	return &resp
+}
+
+func TestCodeListFilter(t *testing.T) {
+	values := []usajobs.CodeListValue{
+		{Code: "AF00", Value: "Department of the Air Force - Agency Wide", IsDisabled: "No", LastModified: "2021-05-14T11:04:18.77", Fields: map[string]string{"ParentCode": "AF"}},
+		{Code: "AF1A", Value: "Air Force C2 & Intelligence", IsDisabled: "No", LastModified: "2021-04-30T11:38:46.643", Fields: map[string]string{"ParentCode": "AF"}},
+		{Code: "AR00", Value: "Department of the Army - Agency Wide", IsDisabled: "No", LastModified: "2019-01-01T00:00:00", Fields: map[string]string{"ParentCode": "AR"}},
+		{Code: "AFZZ", Value: "Retired Air Force Command", IsDisabled: "Yes", LastModified: "2022-01-01T00:00:00", Fields: map[string]string{"ParentCode": "AF"}},
+	}
+
+	tests := []struct {
+		name   string
+		filter usajobs.CodeListFilter
+		want   []string
+	}{
+		{"default excludes disabled", usajobs.CodeListFilter{}, []string{"AF00", "AF1A", "AR00"}},
+		{"include disabled", usajobs.CodeListFilter{IncludeDisabled: true}, []string{"AF00", "AF1A", "AR00", "AFZZ"}},
+		{"contains value", usajobs.CodeListFilter{Contains: "army"}, []string{"AR00"}},
+		{"contains code", usajobs.CodeListFilter{Contains: "af1"}, []string{"AF1A"}},
+		{"pattern", usajobs.CodeListFilter{Pattern: regexp.MustCompile(`^A[FR]00$`)}, []string{"AF00", "AR00"}},
+		{"parent", usajobs.CodeListFilter{Parent: "af", IncludeDisabled: true}, []string{"AF00", "AF1A", "AFZZ"}},
+		{"modified since", usajobs.CodeListFilter{ModifiedSince: time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)}, []string{"AF00"}},
+		{"limit", usajobs.CodeListFilter{Limit: 2}, []string{"AF00", "AF1A"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, v := range tt.filter.Apply(values) {
+				got = append(got, v.Code)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("expected %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestCodeListResponseFilter(t *testing.T) {
+	resp := loadCodeList(t, "countrysubdivisions")
+
+	filtered := resp.Filter(usajobs.CodeListFilter{Parent: "US"})
+	values := filtered.Values()
+	if len(values) == 0 {
+		t.Fatal("expected subdivisions of the US")
+	}
+	for _, v := range values {
+		if v.Fields["ParentCode"] != "US" || v.Disabled() {
+			t.Errorf("unexpected value %+v", v)
+		}
+	}
+
+	if filtered.DateGenerated != resp.DateGenerated || len(resp.Values()) <= len(values) {
+		t.Error("expected the filter to return a copy of the response")
+	}
+
+	limited := resp.Filter(usajobs.CodeListFilter{IncludeDisabled: true, Limit: 5})
+	if len(limited.Values()) != 5 {
+		t.Errorf("expected 5 values, got %d", len(limited.Values()))
+	}
+
+	geo := loadCodeList(t, "geoloccodes").Filter(usajobs.CodeListFilter{Parent: "texas"})
+	for _, v := range geo.Values() {
+		if v.Fields["CountrySubdivisionCode"] != "Texas" {
+			t.Errorf("unexpected value %+v", v)
+		}
+	}
+}
@@ -44,10 +44,14 @@ type GeoLocCodesOptions struct {
 type GeoLocCodesResponse struct {
 	CodeList []struct {
 		ValidValue []struct {
-			Code         string `json:"Code,omitempty"`
-			Value        string `json:"Value,omitempty"`
-			LastModified string `json:"LastModified,omitempty"`
-			IsDisabled   string `json:"IsDisabled,omitempty"`
+			Code                   string `json:"Code,omitempty"`
+			Value                  string `json:"Value,omitempty"`
+			CountryCode            string `json:"CountryCode,omitempty"`
+			CountrySubdivisionCode string `json:"CountrySubdivisionCode,omitempty"`
+			Latitude               string `json:"Latitude,omitempty"`
+			Longitude              string `json:"Longitude,omitempty"`
+			LastModified           string `json:"LastModified,omitempty"`
+			IsDisabled             string `json:"IsDisabled,omitempty"`
 		} `json:"ValidValue,omitempty"`
 		ID string `json:"id,omitempty"`
 	} `json:"CodeList,omitempty"`
@@ -44,10 +44,15 @@ type PostalCodesOptions struct {
 type PostalCodesResponse struct {
 	CodeList []struct {
 		ValidValue []struct {
-			Code         string `json:"Code,omitempty"`
-			Value        string `json:"Value,omitempty"`
-			LastModified string `json:"LastModified,omitempty"`
-			IsDisabled   string `json:"IsDisabled,omitempty"`
+			Code                   string `json:"Code,omitempty"`
+			Value                  string `json:"Value,omitempty"`
+			GeoLocCode             string `json:"GeoLocCode,omitempty"`
+			CityName               string `json:"CityName,omitempty"`
+			CountrySubdivisionCode string `json:"CountrySubdivisionCode,omitempty"`
+			Latitude               string `json:"Latitude,omitempty"`
+			Longitude              string `json:"Longitude,omitempty"`
+			LastModified           string `json:"LastModified,omitempty"`
+			IsDisabled             string `json:"IsDisabled,omitempty"`
 		} `json:"ValidValue,omitempty"`
 		ID string `json:"id,omitempty"`
 	} `json:"CodeList,omitempty"`
@@ -0,0 +1,172 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package usajobs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrUnknownCodeName is returned when a name matches no value of a
+	// codelist.
+	ErrUnknownCodeName = errors.New("no code with that name")
+	// ErrAmbiguousCodeName is returned when a name matches more than one
+	// value of a codelist.
+	ErrAmbiguousCodeName = errors.New("more than one code with that name")
+)
+
+// maxCodeSuggestions is the most values suggested by a resolve error.
+const maxCodeSuggestions = 5
+
+// ResolveCode returns the code of values named name. Name may be a code or a
+// value, ignoring case (ex., "3" or "top secret" for securityclearances). A
+// name that is part of exactly one value (ex., "veteran" for hiringpaths)
+// also resolves. Errors wrap ErrUnknownCodeName or ErrAmbiguousCodeName and
+// suggest the closest values.
+func ResolveCode(values []CodeListValue, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.New("code name required")
+	}
+
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v.Code), name) {
+			return strings.TrimSpace(v.Code), nil
+		}
+	}
+
+	var exact, partial []CodeListValue
+	s := strings.ToLower(name)
+	for _, v := range values {
+		value := strings.ToLower(strings.TrimSpace(v.Value))
+		switch {
+		case value == s:
+			exact = append(exact, v)
+		case strings.Contains(value, s):
+			partial = append(partial, v)
+		}
+	}
+
+	for _, matches := range [][]CodeListValue{exact, partial} {
+		switch len(matches) {
+		case 0:
+			continue
+		case 1:
+			return strings.TrimSpace(matches[0].Code), nil
+		default:
+			return "", codeNameError(name, ErrAmbiguousCodeName, matches)
+		}
+	}
+
+	return "", codeNameError(name, ErrUnknownCodeName, closestValues(values, s))
+}
+
+// ResolveCodes returns the codes of a codelist named by names, see
+// ResolveCode.
+func (c *Client) ResolveCodes(list string, names ...string) ([]string, error) {
+	r, data, err := c.CodeList(list)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.StatusCode != http.StatusOK {
+		return nil, errors.New("bad response from usajobs: " + r.Status)
+	}
+
+	values := data.Filter(CodeListFilter{}).Values()
+	codes := make([]string, 0, len(names))
+	for _, name := range names {
+		code, err := ResolveCode(values, name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", list, err)
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+func codeNameError(name string, err error, suggestions []CodeListValue) error {
+	if len(suggestions) == 0 {
+		return fmt.Errorf("%q: %w", name, err)
+	}
+
+	var names []string
+	for i, v := range suggestions {
+		if i == maxCodeSuggestions {
+			names = append(names, fmt.Sprintf("and %d more", len(suggestions)-i))
+			break
+		}
+		names = append(names, fmt.Sprintf("%q (%s)", strings.TrimSpace(v.Value), strings.TrimSpace(v.Code)))
+	}
+	return fmt.Errorf("%q: %w, did you mean %s", name, err, strings.Join(names, ", "))
+}
+
+// closestValues returns the values within a few edits of name, closest
+// first, comparing name with the whole value and with each of its words.
+func closestValues(values []CodeListValue, name string) []CodeListValue {
+	type scored struct {
+		value    CodeListValue
+		distance int
+	}
+
+	limit := max(2, len(name)/3)
+	var near []scored
+	for _, v := range values {
+		value := strings.ToLower(strings.TrimSpace(v.Value))
+		d := editDistance(value, name)
+		for _, word := range strings.Fields(value) {
+			d = min(d, editDistance(word, name))
+		}
+		if d <= limit {
+			near = append(near, scored{v, d})
+		}
+	}
+
+	sort.SliceStable(near, func(i, j int) bool { return near[i].distance < near[j].distance })
+
+	var closest []CodeListValue
+	for _, c := range near {
+		closest = append(closest, c.value)
+	}
+	return closest
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
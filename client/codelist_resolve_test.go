@@ -0,0 +1,120 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package usajobs_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+)
+
+func TestResolveCode(t *testing.T) {
+	clearances := loadCodeList(t, "securityclearances").Filter(usajobs.CodeListFilter{}).Values()
+	paths := loadCodeList(t, "hiringpaths").Filter(usajobs.CodeListFilter{}).Values()
+	series := loadCodeList(t, "occupationalseries").Filter(usajobs.CodeListFilter{}).Values()
+
+	tests := []struct {
+		name    string
+		values  []usajobs.CodeListValue
+		input   string
+		want    string
+		wantErr error
+		suggest string
+	}{
+		{"code", clearances, "3", "3", nil, ""},
+		{"value", clearances, "Top Secret", "3", nil, ""},
+		{"value ignoring case", paths, "veterans", "VET", nil, ""},
+		{"code ignoring case", paths, "fed-competitive", "FED-COMPETITIVE", nil, ""},
+		{"exact value before partial", clearances, "secret", "2", nil, ""},
+		{"partial value", series, "information technology man", "2210", nil, ""},
+		{"ambiguous partial", series, "information technology", "", usajobs.ErrAmbiguousCodeName, `"Information Technology Management" (2210)`},
+		{"ambiguous value", series, "electrician", "", usajobs.ErrAmbiguousCodeName, `"Electrician"`},
+		{"misspelled", clearances, "top secert", "", usajobs.ErrUnknownCodeName, `"Top Secret" (3)`},
+		{"unknown", clearances, "xyzzy", "", usajobs.ErrUnknownCodeName, ""},
+		{"disabled", clearances, "Q - Nonsensitive", "", usajobs.ErrUnknownCodeName, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := usajobs.ResolveCode(tt.values, tt.input)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				if !strings.Contains(err.Error(), tt.suggest) {
+					t.Errorf("expected error to suggest %s, got %v", tt.suggest, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("could not resolve %q: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestResolveCodes(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := os.ReadFile("../testdata/" + strings.TrimPrefix(r.URL.Path, "/codelist/") + "-testdata.json")
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	}))
+	defer mockServer.Close()
+
+	c, err := usajobs.NewClient("test", "test")
+	if err != nil {
+		t.Fatalf("could not create new usajobs client: %v", err)
+	}
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+	c.BaseURL = u
+
+	codes, err := c.ResolveCodes("positionofferingtypes", "Permanent", "15318", "term")
+	if err != nil {
+		t.Fatalf("could not resolve codes: %v", err)
+	}
+	if strings.Join(codes, ",") != "15317,15318,15319" {
+		t.Errorf("expected 15317,15318,15319, got %v", codes)
+	}
+
+	_, err = c.ResolveCodes("positionofferingtypes", "permanant")
+	if !errors.Is(err, usajobs.ErrUnknownCodeName) || !strings.HasPrefix(err.Error(), "positionofferingtypes: ") {
+		t.Errorf("expected an unknown code error for positionofferingtypes, got %v", err)
+	}
+
+	_, err = c.ResolveCodes("nosuchlist", "x")
+	if err == nil {
+		t.Error("expected an error for an unknown codelist")
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package usajobs_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+)
+
+func TestCodeList(t *testing.T) {
+	data, err := os.ReadFile("../testdata/occupationalseries-testdata.json")
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/codelist/occupationalseries" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	}))
+	defer mockServer.Close()
+
+	c, err := usajobs.NewClient("test", "test")
+	if err != nil {
+		t.Fatalf("could not create new usajobs client: %v", err)
+	}
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+	c.BaseURL = u
+
+	r, resp, err := c.CodeList("occupationalseries")
+	if err != nil {
+		t.Fatalf("failed to execute codelist request: %v", err)
+	}
+
+	if r.StatusCode != http.StatusOK || resp.DateGenerated == "" {
+		t.Fatalf("unexpected response %s: %+v", r.Status, resp)
+	}
+
+	var found bool
+	for _, v := range resp.Values() {
+		if v.Code == "2210" {
+			found = v.Value == "Information Technology Management"
+		}
+	}
+	if !found {
+		t.Error("expected 2210 to be Information Technology Management")
+	}
+
+	_, _, err = c.CodeList("")
+	if err == nil {
+		t.Error("expected error for empty codelist name, got nil")
+	}
+}
+
+func TestCodeListValueFields(t *testing.T) {
+	var v usajobs.CodeListValue
+	err := json.Unmarshal([]byte(`{"Code":"AF","Value":"Air Force","ParentCode":"DD","Acronym":"USAF","IsDisabled":"No"}`), &v)
+	if err != nil {
+		t.Fatalf("could not unmarshal codelist value: %v", err)
+	}
+
+	if v.Code != "AF" || v.Value != "Air Force" || v.IsDisabled != "No" {
+		t.Errorf("unexpected common fields: %+v", v)
+	}
+
+	if v.Fields["ParentCode"] != "DD" || v.Fields["Acronym"] != "USAF" || len(v.Fields) != 2 {
+		t.Errorf("unexpected codelist fields: %v", v.Fields)
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("could not marshal codelist value: %v", err)
+	}
+
+	want := `{"Acronym":"USAF","Code":"AF","IsDisabled":"No","ParentCode":"DD","Value":"Air Force"}`
+	if string(b) != want {
+		t.Errorf("expected %s, got %s", want, b)
+	}
+}
+
+func TestCodeListCatalog(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/codelist/")
+		if name == "payplans" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		data, err := os.ReadFile("../testdata/" + name + "-testdata.json")
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	}))
+	defer mockServer.Close()
+
+	c, err := usajobs.NewClient("test", "test")
+	if err != nil {
+		t.Fatalf("could not create new usajobs client: %v", err)
+	}
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+	c.BaseURL = u
+
+	catalog, err := c.CodeListCatalog()
+	if err == nil || !strings.Contains(err.Error(), "payplans") {
+		t.Errorf("expected an error for payplans, got %v", err)
+	}
+
+	if len(catalog) != len(usajobs.CodeListNames) {
+		t.Fatalf("expected %d codelists, got %d", len(usajobs.CodeListNames), len(catalog))
+	}
+
+	for i, s := range catalog {
+		if s.Name != usajobs.CodeListNames[i] {
+			t.Errorf("expected %s at %d, got %s", usajobs.CodeListNames[i], i, s.Name)
+		}
+		if s.Name == "payplans" {
+			continue
+		}
+		if s.Count == 0 || s.DateGenerated == "" {
+			t.Errorf("expected entries and a date generated for %s, got %+v", s.Name, s)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package usajobs
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// earthRadiusMiles is the mean radius of the earth used for great-circle
+// distance calculations.
+const earthRadiusMiles = 3958.8
+
+// Point is a latitude and longitude in decimal degrees.
+type Point struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// Point returns the coordinates of the location. The second return value is
+// false when usajobs did not provide coordinates for the location.
+func (l PositionLocation) Point() (Point, bool) {
+	if l.Latitude == 0 && l.Longitude == 0 {
+		return Point{}, false
+	}
+	return Point{Latitude: l.Latitude, Longitude: l.Longitude}, true
+}
+
+// DistanceMiles returns the great-circle (haversine) distance in miles
+// between two points.
+func DistanceMiles(a, b Point) float64 {
+	rad := math.Pi / 180
+	dLat := (b.Latitude - a.Latitude) * rad
+	dLon := (b.Longitude - a.Longitude) * rad
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(a.Latitude*rad)*math.Cos(b.Latitude*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadiusMiles * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// ItemDistance is a search result paired with its location nearest to an
+// origin point and the distance to that location in miles.
+type ItemDistance struct {
+	Item     SearchResultItem
+	Location PositionLocation
+	Miles    float64
+}
+
+// Nearest returns the location of the search result closest to origin and
+// its distance in miles. The last return value is false when none of the
+// item's locations have coordinates.
+func (i SearchResultItem) Nearest(origin Point) (PositionLocation, float64, bool) {
+	var nearest PositionLocation
+	miles := math.Inf(1)
+	found := false
+
+	for _, l := range i.MatchedObjectDescriptor.PositionLocation {
+		p, ok := l.Point()
+		if !ok {
+			continue
+		}
+
+		d := DistanceMiles(origin, p)
+		if d < miles {
+			nearest, miles, found = l, d, true
+		}
+	}
+
+	return nearest, miles, found
+}
+
+// WithinRadius keeps the search results that have any location within miles
+// of origin and returns them sorted by distance, closest first. A radius of
+// zero or less keeps every result with coordinates and only sorts them.
+func WithinRadius(items []SearchResultItem, origin Point, miles float64) []ItemDistance {
+	var matches []ItemDistance
+	for _, item := range items {
+		l, d, ok := item.Nearest(origin)
+		if !ok || (miles > 0 && d > miles) {
+			continue
+		}
+		matches = append(matches, ItemDistance{Item: item, Location: l, Miles: d})
+	}
+
+	sort.SliceStable(matches, func(a, b int) bool {
+		return matches[a].Miles < matches[b].Miles
+	})
+
+	return matches
+}
+
+// ResolvePoint converts a location string into a Point. The string is either
+// a "latitude,longitude" pair (ex., "30.27,-97.74") or a postal code, which is
+// resolved through the /codelist/postalcodes and /codelist/geoloccodes
+// endpoints.
+func (c *Client) ResolvePoint(location string) (Point, error) {
+	location = strings.TrimSpace(location)
+
+	if lat, lon, ok := strings.Cut(location, ","); ok {
+		p, err := parsePoint(lat, lon)
+		if err != nil {
+			return Point{}, fmt.Errorf("invalid coordinates %q: %w", location, err)
+		}
+		return p, nil
+	}
+
+	return c.ResolvePostalCode(location)
+}
+
+// ResolvePostalCode looks up the coordinates of a postal code using the
+// /codelist/postalcodes endpoint. Postal codes without coordinates of their
+// own fall back to the coordinates of their GeoLocCode from
+// /codelist/geoloccodes.
+func (c *Client) ResolvePostalCode(code string) (Point, error) {
+	r, postalCodes, err := c.PostalCodes.WithOptions(nil)
+	if err != nil {
+		return Point{}, err
+	}
+
+	if r.StatusCode != http.StatusOK {
+		return Point{}, errors.New("bad response from usajobs: " + r.Status)
+	}
+
+	geoLocCode := ""
+	for _, list := range postalCodes.CodeList {
+		for _, v := range list.ValidValue {
+			if v.Code != code {
+				continue
+			}
+
+			if v.Latitude != "" && v.Longitude != "" {
+				return parsePoint(v.Latitude, v.Longitude)
+			}
+			geoLocCode = v.GeoLocCode
+		}
+	}
+
+	if geoLocCode == "" {
+		return Point{}, fmt.Errorf("postal code %q not found", code)
+	}
+
+	r, geoLocCodes, err := c.GeoLocCodes.WithOptions(nil)
+	if err != nil {
+		return Point{}, err
+	}
+
+	if r.StatusCode != http.StatusOK {
+		return Point{}, errors.New("bad response from usajobs: " + r.Status)
+	}
+
+	for _, list := range geoLocCodes.CodeList {
+		for _, v := range list.ValidValue {
+			if v.Code == geoLocCode {
+				return parsePoint(v.Latitude, v.Longitude)
+			}
+		}
+	}
+
+	return Point{}, fmt.Errorf("no coordinates found for postal code %q", code)
+}
+
+func parsePoint(lat, lon string) (Point, error) {
+	latitude, err := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	if err != nil {
+		return Point{}, err
+	}
+
+	longitude, err := strconv.ParseFloat(strings.TrimSpace(lon), 64)
+	if err != nil {
+		return Point{}, err
+	}
+
+	if math.Abs(latitude) > 90 || math.Abs(longitude) > 180 {
+		return Point{}, fmt.Errorf("coordinates out of range: %s,%s", lat, lon)
+	}
+
+	return Point{Latitude: latitude, Longitude: longitude}, nil
+}
@@ -0,0 +1,163 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package usajobs_test
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+)
+
+func TestDistanceMiles(t *testing.T) {
+	austin := usajobs.Point{Latitude: 30.26715, Longitude: -97.74306}
+	dallas := usajobs.Point{Latitude: 32.78306, Longitude: -96.80667}
+
+	d := usajobs.DistanceMiles(austin, dallas)
+	if math.Abs(d-182) > 2 {
+		t.Errorf("expected roughly 182 miles from Austin to Dallas, got %f", d)
+	}
+
+	if usajobs.DistanceMiles(austin, austin) != 0 {
+		t.Errorf("expected 0 miles between the same point")
+	}
+}
+
+func TestWithinRadius(t *testing.T) {
+	data, err := os.ReadFile(searchTestDataPath)
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	var sr usajobs.SearchResponse
+	err = json.Unmarshal(data, &sr)
+	if err != nil {
+		t.Fatalf("could not decode test data: %v", err)
+	}
+
+	// San Antonio, Texas
+	origin := usajobs.Point{Latitude: 29.42412, Longitude: -98.49363}
+	items := sr.SearchResult.SearchResultItems
+
+	near := usajobs.WithinRadius(items, origin, 300)
+	if len(near) != 2 {
+		t.Fatalf("expected %d results within 300 miles, got %d", 2, len(near))
+	}
+
+	if near[0].Item.MatchedObjectID != "800000002" {
+		t.Errorf("expected Austin posting first, got %s", near[0].Item.MatchedObjectID)
+	}
+
+	// the ICE posting is listed in Washington first, but Dallas is nearer
+	if near[1].Location.CityName != "Dallas, Texas" {
+		t.Errorf("expected nearest location to be Dallas, got %s", near[1].Location.CityName)
+	}
+
+	all := usajobs.WithinRadius(items, origin, 0)
+	if len(all) != 3 {
+		t.Fatalf("expected every result to be kept with no radius, got %d", len(all))
+	}
+
+	if all[2].Item.MatchedObjectID != "800000003" {
+		t.Errorf("expected Portland posting last, got %s", all[2].Item.MatchedObjectID)
+	}
+}
+
+func TestResolvePoint(t *testing.T) {
+	postalCodes, err := os.ReadFile("../testdata/postalcodes-testdata.json")
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	geoLocCodes, err := os.ReadFile("../testdata/geoloccodes-testdata.json")
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	// Create a mock server that returns the codelist JSON data
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Log(r.URL.String())
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(r.URL.String(), "/codelist/postalcodes"):
+			w.WriteHeader(http.StatusOK)
+			w.Write(postalCodes)
+		case strings.Contains(r.URL.String(), "/codelist/geoloccodes"):
+			w.WriteHeader(http.StatusOK)
+			w.Write(geoLocCodes)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	c, err := usajobs.NewClient("test", "test")
+	if err != nil {
+		t.Fatalf("could not create new usajobs client: %v", err)
+	}
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+
+	c.BaseURL = u
+
+	p, err := c.ResolvePoint("78701")
+	if err != nil {
+		t.Fatalf("failed to resolve postal code: %v", err)
+	}
+
+	if p.Latitude != 30.27127 || p.Longitude != -97.74103 {
+		t.Errorf("expected 30.27127,-97.74103, got %f,%f", p.Latitude, p.Longitude)
+	}
+
+	// 78210 has no coordinates of its own and falls back to its geoloccode
+	p, err = c.ResolvePoint("78210")
+	if err != nil {
+		t.Fatalf("failed to resolve postal code: %v", err)
+	}
+
+	if p.Latitude != 29.42412 {
+		t.Errorf("expected geoloccode latitude 29.42412, got %f", p.Latitude)
+	}
+
+	p, err = c.ResolvePoint("38.9, -77.0")
+	if err != nil {
+		t.Fatalf("failed to parse coordinates: %v", err)
+	}
+
+	if p.Latitude != 38.9 || p.Longitude != -77.0 {
+		t.Errorf("expected 38.9,-77.0, got %f,%f", p.Latitude, p.Longitude)
+	}
+
+	_, err = c.ResolvePoint("00000")
+	if err == nil {
+		t.Error("expected error for unknown postal code, got nil")
+	}
+
+	_, err = c.ResolvePoint("91,200")
+	if err == nil {
+		t.Error("expected error for out of range coordinates, got nil")
+	}
+}
@@ -0,0 +1,181 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package usajobs
+
+import (
+	"errors"
+	"net/http"
+)
+
+// HistoricJOAService is used for interacting with the /historicjoa endpoint
+// of the usajobs api. The endpoint returns closed (historic) job opportunity
+// announcements and pages results with a continuation token.
+type HistoricJOAService struct {
+	Client *Client
+}
+
+// NewHistoricJOAService instatiates and returns a historic joa service for
+// this client.
+func NewHistoricJOAService(c *Client) *HistoricJOAService {
+	hs := new(HistoricJOAService)
+	hs.Client = c
+	return hs
+}
+
+// HistoricJOAOptions are the url query parameters supported by the
+// /historicjoa usajobs api endpoint. Dates use the YYYY-MM-DD format.
+// ContinuationToken is managed automatically by Pages and All.
+type HistoricJOAOptions struct {
+	HiringAgencyCodes      []string `url:"HiringAgencyCodes,omitempty" del:","`
+	HiringDepartmentCodes  []string `url:"HiringDepartmentCodes,omitempty" del:","`
+	PositionSeries         []string `url:"PositionSeries,omitempty" del:","`
+	AnnouncementNumbers    []string `url:"AnnouncementNumbers,omitempty" del:","`
+	USAJOBSControlNumbers  []string `url:"USAJOBSControlNumbers,omitempty" del:","`
+	StartPositionOpenDate  string   `url:"StartPositionOpenDate,omitempty"`
+	EndPositionOpenDate    string   `url:"EndPositionOpenDate,omitempty"`
+	StartPositionCloseDate string   `url:"StartPositionCloseDate,omitempty"`
+	EndPositionCloseDate   string   `url:"EndPositionCloseDate,omitempty"`
+	ContinuationToken      string   `url:"continuationtoken,omitempty"`
+}
+
+// HistoricJOAResponse is the golang struct implementation of all possible
+// response fields from /historicjoa. Consumers are responsible for ensuring
+// omitted fields do not cause errors in consumer implementations.
+type HistoricJOAResponse struct {
+	Paging HistoricPaging `json:"paging,omitempty"`
+	Data   []HistoricJOA  `json:"data,omitempty"`
+}
+
+// HistoricPaging is the paging information returned by the /historicjoa
+// endpoints. ContinuationToken is empty on the last page.
+type HistoricPaging struct {
+	Metadata struct {
+		TotalCount        int    `json:"totalCount,omitempty"`
+		PageSize          int    `json:"pageSize,omitempty"`
+		ContinuationToken string `json:"continuationToken,omitempty"`
+	} `json:"metadata,omitempty"`
+	Next string `json:"next,omitempty"`
+}
+
+// HistoricJOA is a single historic job opportunity announcement.
+type HistoricJOA struct {
+	USAJOBSControlNumber               int64   `json:"usajobsControlNumber,omitempty"`
+	HiringAgencyCode                   string  `json:"hiringAgencyCode,omitempty"`
+	HiringAgencyName                   string  `json:"hiringAgencyName,omitempty"`
+	HiringDepartmentCode               string  `json:"hiringDepartmentCode,omitempty"`
+	HiringDepartmentName               string  `json:"hiringDepartmentName,omitempty"`
+	AgencyLevel                        int     `json:"agencyLevel,omitempty"`
+	AgencyLevelSort                    string  `json:"agencyLevelSort,omitempty"`
+	AppointmentType                    string  `json:"appointmentType,omitempty"`
+	WorkSchedule                       string  `json:"workSchedule,omitempty"`
+	PayScale                           string  `json:"payScale,omitempty"`
+	SalaryType                         string  `json:"salaryType,omitempty"`
+	Vendor                             string  `json:"vendor,omitempty"`
+	TravelRequirement                  string  `json:"travelRequirement,omitempty"`
+	TeleworkEligible                   string  `json:"teleworkEligible,omitempty"`
+	ServiceType                        string  `json:"serviceType,omitempty"`
+	SecurityClearanceRequired          string  `json:"securityClearanceRequired,omitempty"`
+	SecurityClearance                  string  `json:"securityClearance,omitempty"`
+	WhoMayApply                        string  `json:"whoMayApply,omitempty"`
+	AnnouncementClosingTypeCode        string  `json:"announcementClosingTypeCode,omitempty"`
+	AnnouncementClosingTypeDescription string  `json:"announcementClosingTypeDescription,omitempty"`
+	PositionOpenDate                   string  `json:"positionOpenDate,omitempty"`
+	PositionCloseDate                  string  `json:"positionCloseDate,omitempty"`
+	PositionExpireDate                 string  `json:"positionExpireDate,omitempty"`
+	AnnouncementNumber                 string  `json:"announcementNumber,omitempty"`
+	HiringSubelementName               string  `json:"hiringSubelementName,omitempty"`
+	PositionTitle                      string  `json:"positionTitle,omitempty"`
+	MinimumGrade                       string  `json:"minimumGrade,omitempty"`
+	MaximumGrade                       string  `json:"maximumGrade,omitempty"`
+	PromotionPotential                 string  `json:"promotionPotential,omitempty"`
+	MinimumSalary                      float64 `json:"minimumSalary,omitempty"`
+	MaximumSalary                      float64 `json:"maximumSalary,omitempty"`
+	SupervisoryStatus                  string  `json:"supervisoryStatus,omitempty"`
+	DrugTestRequired                   string  `json:"drugTestRequired,omitempty"`
+	RelocationExpensesReimbursed       string  `json:"relocationExpensesReimbursed,omitempty"`
+	TotalOpenings                      string  `json:"totalOpenings,omitempty"`
+	DisableApplyOnline                 string  `json:"disableApplyOnline,omitempty"`
+	PositionOpeningStatus              string  `json:"positionOpeningStatus,omitempty"`
+	HiringPaths                        []struct {
+		HiringPath string `json:"hiringPath,omitempty"`
+	} `json:"hiringPaths,omitempty"`
+	JobCategories []struct {
+		Series string `json:"series,omitempty"`
+	} `json:"jobCategories,omitempty"`
+	PositionLocations []struct {
+		PositionLocationCity    string `json:"positionLocationCity,omitempty"`
+		PositionLocationState   string `json:"positionLocationState,omitempty"`
+		PositionLocationCountry string `json:"positionLocationCountry,omitempty"`
+	} `json:"positionLocations,omitempty"`
+}
+
+// WithOptions executes a single request to the usajobs /historicjoa endpoint
+// with the provided options and returns one page of results. Pass nil if no
+// options desired.
+func (hs *HistoricJOAService) WithOptions(opt *HistoricJOAOptions) (*http.Response, *HistoricJOAResponse, error) {
+	usajobsEndpoint := "/historicjoa"
+	responseObject := new(HistoricJOAResponse)
+	r, object, err := hs.Client.NewResponse(usajobsEndpoint, opt, responseObject)
+	return r, object.(*HistoricJOAResponse), err
+}
+
+// Pages requests every page of results for the provided options, following
+// the continuation token returned by usajobs, and calls fn with each page as
+// it arrives. Returning an error from fn stops paging and returns that error.
+// Pass nil if no options desired.
+func (hs *HistoricJOAService) Pages(opt *HistoricJOAOptions, fn func(*HistoricJOAResponse) error) error {
+	page := HistoricJOAOptions{}
+	if opt != nil {
+		page = *opt
+	}
+
+	seen := map[string]bool{}
+	for {
+		r, data, err := hs.WithOptions(&page)
+		if err != nil {
+			return err
+		}
+
+		if r.StatusCode != http.StatusOK {
+			return errors.New("bad response from usajobs: " + r.Status)
+		}
+
+		err = fn(data)
+		if err != nil {
+			return err
+		}
+
+		// usajobs returns an empty token on the last page. A token that was
+		// already requested would page forever, so treat it as the end too.
+		token := data.Paging.Metadata.ContinuationToken
+		if token == "" || seen[token] {
+			return nil
+		}
+		seen[token] = true
+		page.ContinuationToken = token
+	}
+}
+
+// All requests every page of results for the provided options and returns
+// the combined announcements. Pass nil if no options desired.
+func (hs *HistoricJOAService) All(opt *HistoricJOAOptions) ([]HistoricJOA, error) {
+	var all []HistoricJOA
+	err := hs.Pages(opt, func(r *HistoricJOAResponse) error {
+		all = append(all, r.Data...)
+		return nil
+	})
+	return all, err
+}
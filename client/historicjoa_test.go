@@ -0,0 +1,155 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package usajobs_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+)
+
+// newHistoricJOAServer returns a mock server that serves the first page of
+// historic joa test data and the second page once the continuation token
+// from the first page is requested.
+func newHistoricJOAServer(t *testing.T) *httptest.Server {
+	page1, err := os.ReadFile("../testdata/historicjoa-page1-testdata.json")
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	page2, err := os.ReadFile("../testdata/historicjoa-page2-testdata.json")
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Log(r.URL.String())
+
+		if !strings.HasPrefix(r.URL.Path, "/historicjoa") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if r.URL.Query().Get("continuationtoken") == "Q1RPS0VOLXBhZ2UtMg==" {
+			w.Write(page2)
+			return
+		}
+		w.Write(page1)
+	}))
+}
+
+func TestHistoricJOA(t *testing.T) {
+	mockServer := newHistoricJOAServer(t)
+	defer mockServer.Close()
+
+	c, err := usajobs.NewClient("test", "test")
+	if err != nil {
+		t.Fatalf("could not create new usajobs client: %v", err)
+	}
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+
+	c.BaseURL = u
+
+	_, res, err := c.HistoricJOA.WithOptions(nil)
+	if err != nil {
+		t.Fatalf("failed to execute historicjoa request: %v", err.Error())
+	}
+
+	if len(res.Data) != 2 {
+		t.Fatalf("expected %d announcements, got %d", 2, len(res.Data))
+	}
+
+	if res.Paging.Metadata.ContinuationToken == "" {
+		t.Fatal("expected continuation token on first page, got empty string")
+	}
+}
+
+func TestHistoricJOAAll(t *testing.T) {
+	mockServer := newHistoricJOAServer(t)
+	defer mockServer.Close()
+
+	c, err := usajobs.NewClient("test", "test")
+	if err != nil {
+		t.Fatalf("could not create new usajobs client: %v", err)
+	}
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+
+	c.BaseURL = u
+
+	opt := usajobs.HistoricJOAOptions{
+		PositionSeries:        []string{"2210", "0854"},
+		StartPositionOpenDate: "2023-01-01",
+		EndPositionOpenDate:   "2023-01-31",
+	}
+
+	all, err := c.HistoricJOA.All(&opt)
+	if err != nil {
+		t.Fatalf("failed to page historicjoa results: %v", err.Error())
+	}
+
+	if len(all) != 3 {
+		t.Fatalf("expected %d announcements, got %d", 3, len(all))
+	}
+
+	if all[2].USAJOBSControlNumber != 700000003 {
+		t.Errorf("expected %d, got %d", 700000003, all[2].USAJOBSControlNumber)
+	}
+
+	if opt.ContinuationToken != "" {
+		t.Errorf("expected caller options to be left unchanged, got token %s", opt.ContinuationToken)
+	}
+}
+
+func TestHistoricJOABadStatus(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{}`))
+	}))
+	defer mockServer.Close()
+
+	c, err := usajobs.NewClient("test", "test")
+	if err != nil {
+		t.Fatalf("could not create new usajobs client: %v", err)
+	}
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+
+	c.BaseURL = u
+
+	_, err = c.HistoricJOA.All(nil)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
@@ -16,9 +16,11 @@ limitations under the License.
 package usajobs
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/google/go-querystring/query"
 )
@@ -29,40 +31,130 @@ type SearchService struct {
 	Client *Client
 }
 
-// SearchOptions are most of the url parameters the usajobs api /search api
-// supports. SalaryBucket, GradeBucket, and MissionCriticalTags are query
-// parameters that are not currently supported, mostly because they are
-// duplicative of other options already supported. Submit an issue or PR if
-// this is breaking something you would like to do.
+// SearchOptions are the url parameters the usajobs api /search api supports.
+// Options that only accept a known set of values are typed; the values for
+// most of those come from the codelist endpoints noted on each type.
 type SearchOptions struct {
-	Keyword                   string   `url:"Keyword,omitempty"`
-	PositionTitle             string   `url:"PositionTitle,omitempty"`
-	RemunerationMinimumAmount string   `url:"RemunerationMinimumAmount,omitempty"`
-	RemunerationMaximumAmount string   `url:"RemunerationMaximumAmount,omitempty"`
-	PayGradeHigh              string   `url:"PayGradeHigh,omitempty"`
-	PayGradeLow               string   `url:"PayGradeLow,omitempty"`
-	JobCategoryCode           []string `url:"JobCategoryCode,omitempty" del:";"`
-	LocationName              []string `url:"LocationName,omitempty" del:";"`
-	PostingChannel            []string `url:"PostingChannel,omitempty" del:";"`
-	Organization              []string `url:"Organization,omitempty" del:";"`
-	PositionOfferingTypeCode  []string `url:"PositionOfferingTypeCode,omitempty" del:";"`
-	TravelPercentage          int      `url:"TravelPercentage,omitempty"`
-	PositionScheduleTypeCode  []int    `url:"PositionSchedule,omitempty" del:";"`
-	RelocationIndicator       bool     `url:"RelocationIndicator,omitempty"`
-	SecurityClearanceRequired []int    `url:"SecurityClearanceRequired,omitempty" del:";"`
-	SupervisoryStatus         string   `url:"SupervisoryStatus,omitempty"`
-	DatePosted                int      `url:"DatePosted,omitempty"`
-	JobGradeCode              []int    `url:"JobGradeCode,omitempty" del:";"`
-	SortField                 string   `url:"SortField,omitempty"`
-	SortDirection             string   `url:"SortDirection,omitempty"`
-	Page                      int      `url:"Page,omitempty"`
-	ResultsPerPage            int      `url:"ResultsPerPage,omitempty"`
-	WhoMayApply               string   `url:"WhoMayApply,omitempty"`
-	Radius                    int      `url:"Radius,omitempty"`
-	Fields                    string   `url:"Fields,omitempty"`
-	HiringPath                []string `url:"HiringPath,omitempty" del:";"`
-	PositionSensitivity       []int    `url:"PositionSensitivity,omitempty" del:";"`
-	RemoteIndicator           bool     `url:"RemoteIndicator,omitempty"`
+	Keyword                   string               `url:"Keyword,omitempty"`
+	PositionTitle             string               `url:"PositionTitle,omitempty"`
+	RemunerationMinimumAmount string               `url:"RemunerationMinimumAmount,omitempty"`
+	RemunerationMaximumAmount string               `url:"RemunerationMaximumAmount,omitempty"`
+	PayGradeHigh              string               `url:"PayGradeHigh,omitempty"`
+	PayGradeLow               string               `url:"PayGradeLow,omitempty"`
+	JobCategoryCode           []string             `url:"JobCategoryCode,omitempty" del:";"`
+	LocationName              []string             `url:"LocationName,omitempty" del:";"`
+	PostingChannel            []PostingChannel     `url:"PostingChannel,omitempty" del:";"`
+	Organization              []string             `url:"Organization,omitempty" del:";"`
+	PositionOfferingTypeCode  []string             `url:"PositionOfferingTypeCode,omitempty" del:";"`
+	TravelPercentage          int                  `url:"TravelPercentage,omitempty"`
+	PositionScheduleTypeCode  []int                `url:"PositionSchedule,omitempty" del:";"`
+	RelocationIndicator       bool                 `url:"RelocationIndicator,omitempty"`
+	SecurityClearanceRequired []int                `url:"SecurityClearanceRequired,omitempty" del:";"`
+	SupervisoryStatus         SupervisoryStatus    `url:"SupervisoryStatus,omitempty"`
+	DatePosted                int                  `url:"DatePosted,omitempty"`
+	JobGradeCode              []int                `url:"JobGradeCode,omitempty" del:";"`
+	SortField                 string               `url:"SortField,omitempty"`
+	SortDirection             string               `url:"SortDirection,omitempty"`
+	Page                      int                  `url:"Page,omitempty"`
+	ResultsPerPage            int                  `url:"ResultsPerPage,omitempty"`
+	WhoMayApply               WhoMayApply          `url:"WhoMayApply,omitempty"`
+	Radius                    int                  `url:"Radius,omitempty"`
+	Fields                    SearchFields         `url:"Fields,omitempty"`
+	SalaryBucket              []SalaryBucket       `url:"SalaryBucket,omitempty" del:";"`
+	GradeBucket               []GradeBucket        `url:"GradeBucket,omitempty" del:";"`
+	HiringPath                []string             `url:"HiringPath,omitempty" del:";"`
+	MissionCriticalTags       []MissionCriticalTag `url:"MissionCriticalTags,omitempty" del:";"`
+	PositionSensitivity       []int                `url:"PositionSensitivity,omitempty" del:";"`
+	RemoteIndicator           bool                 `url:"RemoteIndicator,omitempty"`
+}
+
+// SearchFields controls how much of each job announcement /search returns.
+type SearchFields string
+
+const (
+	// SearchFieldsMin is the default and omits most of UserArea.Details.
+	SearchFieldsMin SearchFields = "Min"
+	// SearchFieldsFull returns the full job announcement for every result.
+	SearchFieldsFull SearchFields = "Full"
+)
+
+// SupervisoryStatus filters jobs by whether the position is supervisory.
+type SupervisoryStatus string
+
+const (
+	SupervisoryStatusYes SupervisoryStatus = "Y"
+	SupervisoryStatusNo  SupervisoryStatus = "N"
+)
+
+// WhoMayApply sets the type of search: jobs open to the public, jobs open to
+// current and former federal employees (status), or both.
+type WhoMayApply string
+
+const (
+	WhoMayApplyAll    WhoMayApply = "All"
+	WhoMayApplyPublic WhoMayApply = "Public"
+	WhoMayApplyStatus WhoMayApply = "Status"
+)
+
+// PostingChannel is the channel a job announcement was posted through. The
+// public usajobs site is "USAJOBS"; other channels are agency specific.
+type PostingChannel string
+
+// SalaryBucket is a RefinementToken from the SalaryBucket refiners returned
+// in a SearchResponse.
+type SalaryBucket string
+
+// GradeBucket is a RefinementToken from the GradeBucket refiners returned in
+// a SearchResponse.
+type GradeBucket string
+
+// MissionCriticalTag is a Code from the /codelist/missioncriticalcodes
+// endpoint (ex., "01" for Cyber Security).
+type MissionCriticalTag string
+
+// ParseSearchFields converts a case-insensitive string (ex., "full") into a
+// SearchFields value. An empty string returns an empty value.
+func ParseSearchFields(s string) (SearchFields, error) {
+	switch strings.ToLower(s) {
+	case "":
+		return "", nil
+	case "min":
+		return SearchFieldsMin, nil
+	case "full":
+		return SearchFieldsFull, nil
+	}
+	return "", fmt.Errorf("invalid fields value %q, expected min or full", s)
+}
+
+// ParseSupervisoryStatus converts a case-insensitive yes/no style string
+// (ex., "Y", "yes", "true") into a SupervisoryStatus value. An empty string
+// returns an empty value.
+func ParseSupervisoryStatus(s string) (SupervisoryStatus, error) {
+	switch strings.ToLower(s) {
+	case "":
+		return "", nil
+	case "y", "yes", "true":
+		return SupervisoryStatusYes, nil
+	case "n", "no", "false":
+		return SupervisoryStatusNo, nil
+	}
+	return "", fmt.Errorf("invalid supervisory status %q, expected Y or N", s)
+}
+
+// ParseWhoMayApply converts a case-insensitive string (ex., "public") into a
+// WhoMayApply value. An empty string returns an empty value.
+func ParseWhoMayApply(s string) (WhoMayApply, error) {
+	switch strings.ToLower(s) {
+	case "":
+		return "", nil
+	case "all":
+		return WhoMayApplyAll, nil
+	case "public":
+		return WhoMayApplyPublic, nil
+	case "status":
+		return WhoMayApplyStatus, nil
+	}
+	return "", fmt.Errorf("invalid who may apply value %q, expected All, Public, or Status", s)
 }
 
 // SearchResponse is the golang struct implementation of all possible response
@@ -73,123 +165,17 @@ type SearchResponse struct {
 	SearchParameters struct {
 	} `json:"SearchParameters,omitempty"`
 	SearchResult struct {
-		SearchResultCount    int `json:"SearchResultCount,omitempty"`
-		SearchResultCountAll int `json:"SearchResultCountAll,omitempty"`
-		SearchResultItems    []struct {
-			MatchedObjectID         string `json:"MatchedObjectId,omitempty"`
-			MatchedObjectDescriptor struct {
-				PositionID              string   `json:"PositionID,omitempty"`
-				PositionTitle           string   `json:"PositionTitle,omitempty"`
-				PositionURI             string   `json:"PositionURI,omitempty"`
-				ApplyURI                []string `json:"ApplyURI,omitempty"`
-				PositionLocationDisplay string   `json:"PositionLocationDisplay,omitempty"`
-				PositionLocation        []struct {
-					LocationName           string  `json:"LocationName,omitempty"`
-					CountryCode            string  `json:"CountryCode,omitempty"`
-					CountrySubDivisionCode string  `json:"CountrySubDivisionCode,omitempty"`
-					CityName               string  `json:"CityName,omitempty"`
-					Longitude              float64 `json:"Longitude,omitempty"`
-					Latitude               float64 `json:"Latitude,omitempty"`
-				} `json:"PositionLocation,omitempty"`
-				OrganizationName string `json:"OrganizationName,omitempty"`
-				DepartmentName   string `json:"DepartmentName,omitempty"`
-				JobCategory      []struct {
-					Name string `json:"Name,omitempty"`
-					Code string `json:"Code,omitempty"`
-				} `json:"JobCategory,omitempty"`
-				JobGrade []struct {
-					Code string `json:"Code,omitempty"`
-				} `json:"JobGrade,omitempty"`
-				PositionSchedule []struct {
-					Name string `json:"Name,omitempty"`
-					Code string `json:"Code,omitempty"`
-				} `json:"PositionSchedule,omitempty"`
-				PositionOfferingType []struct {
-					Name string `json:"Name,omitempty"`
-					Code string `json:"Code,omitempty"`
-				} `json:"PositionOfferingType,omitempty"`
-				QualificationSummary string `json:"QualificationSummary,omitempty"`
-				PositionRemuneration []struct {
-					MinimumRange     string `json:"MinimumRange,omitempty"`
-					MaximumRange     string `json:"MaximumRange,omitempty"`
-					RateIntervalCode string `json:"RateIntervalCode,omitempty"`
-					Description      string `json:"Description,omitempty"`
-				} `json:"PositionRemuneration,omitempty"`
-				PositionStartDate            string `json:"PositionStartDate,omitempty"`
-				PositionEndDate              string `json:"PositionEndDate,omitempty"`
-				PublicationStartDate         string `json:"PublicationStartDate,omitempty"`
-				ApplicationCloseDate         string `json:"ApplicationCloseDate,omitempty"`
-				PositionFormattedDescription []struct {
-					Content          string `json:"Content,omitempty"`
-					Label            string `json:"Label,omitempty"`
-					LabelDescription string `json:"LabelDescription,omitempty"`
-				} `json:"PositionFormattedDescription,omitempty"`
-				UserArea struct {
-					Details struct {
-						MajorDuties       []string `json:"MajorDuties,omitempty"`
-						Education         string   `json:"Education,omitempty"`
-						Requirements      string   `json:"Requirements,omitempty"`
-						Evaluations       string   `json:"Evaluations,omitempty"`
-						HowToApply        string   `json:"HowToApply,omitempty"`
-						WhatToExpectNext  string   `json:"WhatToExpectNext,omitempty"`
-						RequiredDocuments string   `json:"RequiredDocuments,omitempty"`
-						Benefits          string   `json:"Benefits,omitempty"`
-						BenefitsURL       string   `json:"BenefitsUrl,omitempty"`
-						OtherInformation  string   `json:"OtherInformation,omitempty"`
-						KeyRequirements   []any    `json:"KeyRequirements,omitempty"`
-						JobSummary        string   `json:"JobSummary,omitempty"`
-						WhoMayApply       struct {
-							Name string `json:"Name,omitempty"`
-							Code string `json:"Code,omitempty"`
-						} `json:"WhoMayApply,omitempty"`
-						LowGrade          string `json:"LowGrade,omitempty"`
-						HighGrade         string `json:"HighGrade,omitempty"`
-						SubAgencyName     string `json:"SubAgencyName,omitempty"`
-						OrganizationCodes string `json:"OrganizationCodes,omitempty"`
-					} `json:"Details,omitempty"`
-					IsRadialSearch bool `json:"IsRadialSearch,omitempty"`
-				} `json:"UserArea,omitempty"`
-			} `json:"MatchedObjectDescriptor,omitempty"`
-			RelevanceRank float64 `json:"RelevanceRank,omitempty"`
-		} `json:"SearchResultItems,omitempty"`
-		UserArea struct {
+		SearchResultCount    int                `json:"SearchResultCount,omitempty"`
+		SearchResultCountAll int                `json:"SearchResultCountAll,omitempty"`
+		SearchResultItems    []SearchResultItem `json:"SearchResultItems,omitempty"`
+		UserArea             struct {
 			Refiners struct {
-				Organization []struct {
-					RefinementName  string `json:"RefinementName,omitempty"`
-					RefinementCount string `json:"RefinementCount,omitempty"`
-					RefinementToken string `json:"RefinementToken,omitempty"`
-					RefinementValue string `json:"RefinementValue,omitempty"`
-				} `json:"Organization,omitempty"`
-				GradeBucket []struct {
-					RefinementName  string `json:"RefinementName,omitempty"`
-					RefinementCount string `json:"RefinementCount,omitempty"`
-					RefinementToken string `json:"RefinementToken,omitempty"`
-					RefinementValue string `json:"RefinementValue,omitempty"`
-				} `json:"GradeBucket,omitempty"`
-				SalaryBucket []struct {
-					RefinementName  string `json:"RefinementName,omitempty"`
-					RefinementCount string `json:"RefinementCount,omitempty"`
-					RefinementToken string `json:"RefinementToken,omitempty"`
-					RefinementValue string `json:"RefinementValue,omitempty"`
-				} `json:"SalaryBucket,omitempty"`
-				PositionOfferingTypeCode []struct {
-					RefinementName  string `json:"RefinementName,omitempty"`
-					RefinementCount string `json:"RefinementCount,omitempty"`
-					RefinementToken string `json:"RefinementToken,omitempty"`
-					RefinementValue string `json:"RefinementValue,omitempty"`
-				} `json:"PositionOfferingTypeCode,omitempty"`
-				PositionScheduleTypeCode []struct {
-					RefinementName  string `json:"RefinementName,omitempty"`
-					RefinementCount string `json:"RefinementCount,omitempty"`
-					RefinementToken string `json:"RefinementToken,omitempty"`
-					RefinementValue string `json:"RefinementValue,omitempty"`
-				} `json:"PositionScheduleTypeCode,omitempty"`
-				JobCategoryCode []struct {
-					RefinementName  string `json:"RefinementName,omitempty"`
-					RefinementCount string `json:"RefinementCount,omitempty"`
-					RefinementToken string `json:"RefinementToken,omitempty"`
-					RefinementValue string `json:"RefinementValue,omitempty"`
-				} `json:"JobCategoryCode,omitempty"`
+				Organization             []Refinement `json:"Organization,omitempty"`
+				GradeBucket              []Refinement `json:"GradeBucket,omitempty"`
+				SalaryBucket             []Refinement `json:"SalaryBucket,omitempty"`
+				PositionOfferingTypeCode []Refinement `json:"PositionOfferingTypeCode,omitempty"`
+				PositionScheduleTypeCode []Refinement `json:"PositionScheduleTypeCode,omitempty"`
+				JobCategoryCode          []Refinement `json:"JobCategoryCode,omitempty"`
 			} `json:"Refiners,omitempty"`
 			NumberOfPages  string `json:"NumberOfPages,omitempty"`
 			IsRadialSearch bool   `json:"IsRadialSearch,omitempty"`
@@ -197,6 +183,129 @@ type SearchResponse struct {
 	} `json:"SearchResult,omitempty"`
 }
 
+// SearchResultItem is a single job announcement returned by /search.
+type SearchResultItem struct {
+	MatchedObjectID         string                  `json:"MatchedObjectId,omitempty"`
+	MatchedObjectDescriptor MatchedObjectDescriptor `json:"MatchedObjectDescriptor,omitempty"`
+	RelevanceRank           float64                 `json:"RelevanceRank,omitempty"`
+}
+
+// MatchedObjectDescriptor is the job announcement content of a search result.
+type MatchedObjectDescriptor struct {
+	PositionID              string             `json:"PositionID,omitempty"`
+	PositionTitle           string             `json:"PositionTitle,omitempty"`
+	PositionURI             string             `json:"PositionURI,omitempty"`
+	ApplyURI                []string           `json:"ApplyURI,omitempty"`
+	PositionLocationDisplay string             `json:"PositionLocationDisplay,omitempty"`
+	PositionLocation        []PositionLocation `json:"PositionLocation,omitempty"`
+	OrganizationName        string             `json:"OrganizationName,omitempty"`
+	DepartmentName          string             `json:"DepartmentName,omitempty"`
+	JobCategory             []struct {
+		Name string `json:"Name,omitempty"`
+		Code string `json:"Code,omitempty"`
+	} `json:"JobCategory,omitempty"`
+	JobGrade []struct {
+		Code string `json:"Code,omitempty"`
+	} `json:"JobGrade,omitempty"`
+	PositionSchedule []struct {
+		Name string `json:"Name,omitempty"`
+		Code string `json:"Code,omitempty"`
+	} `json:"PositionSchedule,omitempty"`
+	PositionOfferingType []struct {
+		Name string `json:"Name,omitempty"`
+		Code string `json:"Code,omitempty"`
+	} `json:"PositionOfferingType,omitempty"`
+	QualificationSummary         string                 `json:"QualificationSummary,omitempty"`
+	PositionRemuneration         []PositionRemuneration `json:"PositionRemuneration,omitempty"`
+	PositionStartDate            string                 `json:"PositionStartDate,omitempty"`
+	PositionEndDate              string                 `json:"PositionEndDate,omitempty"`
+	PublicationStartDate         string                 `json:"PublicationStartDate,omitempty"`
+	ApplicationCloseDate         string                 `json:"ApplicationCloseDate,omitempty"`
+	PositionFormattedDescription []struct {
+		Content          string `json:"Content,omitempty"`
+		Label            string `json:"Label,omitempty"`
+		LabelDescription string `json:"LabelDescription,omitempty"`
+	} `json:"PositionFormattedDescription,omitempty"`
+	UserArea struct {
+		Details        SearchItemDetails `json:"Details,omitempty"`
+		IsRadialSearch bool              `json:"IsRadialSearch,omitempty"`
+	} `json:"UserArea,omitempty"`
+}
+
+// SearchItemDetails is the UserArea.Details section of a search result item.
+type SearchItemDetails struct {
+	MajorDuties       []string `json:"MajorDuties,omitempty"`
+	Education         string   `json:"Education,omitempty"`
+	Requirements      string   `json:"Requirements,omitempty"`
+	Evaluations       string   `json:"Evaluations,omitempty"`
+	HowToApply        string   `json:"HowToApply,omitempty"`
+	WhatToExpectNext  string   `json:"WhatToExpectNext,omitempty"`
+	RequiredDocuments string   `json:"RequiredDocuments,omitempty"`
+	Benefits          string   `json:"Benefits,omitempty"`
+	BenefitsURL       string   `json:"BenefitsUrl,omitempty"`
+	OtherInformation  string   `json:"OtherInformation,omitempty"`
+	KeyRequirements   []any    `json:"KeyRequirements,omitempty"`
+	JobSummary        string   `json:"JobSummary,omitempty"`
+	WhoMayApply       struct {
+		Name string `json:"Name,omitempty"`
+		Code string `json:"Code,omitempty"`
+	} `json:"WhoMayApply,omitempty"`
+	LowGrade                   string   `json:"LowGrade,omitempty"`
+	HighGrade                  string   `json:"HighGrade,omitempty"`
+	PromotionPotential         string   `json:"PromotionPotential,omitempty"`
+	SubAgencyName              string   `json:"SubAgencyName,omitempty"`
+	OrganizationCodes          string   `json:"OrganizationCodes,omitempty"`
+	Relocation                 string   `json:"Relocation,omitempty"`
+	HiringPath                 []string `json:"HiringPath,omitempty"`
+	TotalOpenings              string   `json:"TotalOpenings,omitempty"`
+	AgencyMarketingStatement   string   `json:"AgencyMarketingStatement,omitempty"`
+	TravelCode                 string   `json:"TravelCode,omitempty"`
+	ApplyOnlineURL             string   `json:"ApplyOnlineUrl,omitempty"`
+	DetailStatusURL            string   `json:"DetailStatusUrl,omitempty"`
+	BenefitsDisplayDefaultText bool     `json:"BenefitsDisplayDefaultText,omitempty"`
+	WithinArea                 string   `json:"WithinArea,omitempty"`
+	CommuteDistance            string   `json:"CommuteDistance,omitempty"`
+	ServiceType                string   `json:"ServiceType,omitempty"`
+	AnnouncementClosingType    string   `json:"AnnouncementClosingType,omitempty"`
+	AgencyContactEmail         string   `json:"AgencyContactEmail,omitempty"`
+	AgencyContactPhone         string   `json:"AgencyContactPhone,omitempty"`
+	SecurityClearance          string   `json:"SecurityClearance,omitempty"`
+	DrugTestRequired           string   `json:"DrugTestRequired,omitempty"`
+	// PositionSensitivity is misspelled by the usajobs api.
+	PositionSensitivity string   `json:"PositionSensitivitiy,omitempty"`
+	AdjudicationType    []string `json:"AdjudicationType,omitempty"`
+	TeleworkEligible    bool     `json:"TeleworkEligible,omitempty"`
+	RemoteIndicator     bool     `json:"RemoteIndicator,omitempty"`
+}
+
+// PositionRemuneration is the pay range of a job announcement.
+type PositionRemuneration struct {
+	MinimumRange     string `json:"MinimumRange,omitempty"`
+	MaximumRange     string `json:"MaximumRange,omitempty"`
+	RateIntervalCode string `json:"RateIntervalCode,omitempty"`
+	Description      string `json:"Description,omitempty"`
+}
+
+// PositionLocation is a single location a job announcement is hiring for.
+type PositionLocation struct {
+	LocationName           string  `json:"LocationName,omitempty"`
+	CountryCode            string  `json:"CountryCode,omitempty"`
+	CountrySubDivisionCode string  `json:"CountrySubDivisionCode,omitempty"`
+	CityName               string  `json:"CityName,omitempty"`
+	Longitude              float64 `json:"Longitude,omitempty"`
+	Latitude               float64 `json:"Latitude,omitempty"`
+}
+
+// Refinement is a single facet returned in the refiners of a SearchResponse.
+// RefinementToken is the value to pass back in the matching SearchOptions
+// field (ex., a GradeBucket token in SearchOptions.GradeBucket).
+type Refinement struct {
+	RefinementName  string `json:"RefinementName,omitempty"`
+	RefinementCount string `json:"RefinementCount,omitempty"`
+	RefinementToken string `json:"RefinementToken,omitempty"`
+	RefinementValue string `json:"RefinementValue,omitempty"`
+}
+
 // NewSearchService instatiates and returns a search service for this client.
 func NewSearchService(c *Client) *SearchService {
 	ss := new(SearchService)
@@ -207,6 +316,12 @@ func NewSearchService(c *Client) *SearchService {
 // WithOptions executes a request to the usajobs /search endpoint with the
 // provided search options. Pass nil if no search options desired.
 func (s *SearchService) WithOptions(opt *SearchOptions) (*http.Response, SearchResponse, error) {
+	return s.WithOptionsContext(context.Background(), opt)
+}
+
+// WithOptionsContext is WithOptions with a context that controls the
+// lifetime of the request.
+func (s *SearchService) WithOptionsContext(ctx context.Context, opt *SearchOptions) (*http.Response, SearchResponse, error) {
 
 	usajobsEndpoint := "/search"
 	sr := SearchResponse{}
@@ -220,7 +335,7 @@ func (s *SearchService) WithOptions(opt *SearchOptions) (*http.Response, SearchR
 		requestURL = fmt.Sprintf("%s?%s", usajobsEndpoint, qs.Encode())
 	}
 
-	req, err := s.Client.NewRequest("GET", requestURL)
+	req, err := s.Client.NewRequestWithContext(ctx, "GET", requestURL)
 	if err != nil {
 		return nil, sr, err
 	}
@@ -0,0 +1,278 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package usajobs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FieldSeparator joins the values of repeated fields (ex., locations) when a
+// search result item is flattened into a single row.
+const FieldSeparator = "; "
+
+// SearchItemField is a single column of a flattened search result item.
+type SearchItemField struct {
+	Name        string
+	Description string
+	Value       func(SearchResultItem) string
+}
+
+// SearchItemFields lists every field of a search result item in the order
+// they are displayed.
+var SearchItemFields = []SearchItemField{
+	{"MATCHED_OBJECT_ID", "usajobs control number", func(i SearchResultItem) string { return i.MatchedObjectID }},
+	{"POSITION_ID", "job announcement number", func(i SearchResultItem) string { return i.MatchedObjectDescriptor.PositionID }},
+	{"JOB_TITLE", "position title", func(i SearchResultItem) string { return i.MatchedObjectDescriptor.PositionTitle }},
+	{"DEPARTMENT", "department name", func(i SearchResultItem) string { return i.MatchedObjectDescriptor.DepartmentName }},
+	{"ORGANIZATION", "hiring organization name", func(i SearchResultItem) string { return i.MatchedObjectDescriptor.OrganizationName }},
+	{"SUB_AGENCY", "sub agency name", func(i SearchResultItem) string { return details(i).SubAgencyName }},
+	{"ORGANIZATION_CODES", "hiring organization codes", func(i SearchResultItem) string { return details(i).OrganizationCodes }},
+	{"LOCATION_DISPLAY", "location summary", func(i SearchResultItem) string {
+		return i.MatchedObjectDescriptor.PositionLocationDisplay
+	}},
+	{"LOCATIONS", "location names", func(i SearchResultItem) string {
+		return joinLocations(i, func(l PositionLocation) string { return l.LocationName })
+	}},
+	{"CITIES", "location city names", func(i SearchResultItem) string {
+		return joinLocations(i, func(l PositionLocation) string { return l.CityName })
+	}},
+	{"STATES", "location states or country subdivisions", func(i SearchResultItem) string {
+		return joinLocations(i, func(l PositionLocation) string { return l.CountrySubDivisionCode })
+	}},
+	{"COUNTRIES", "location countries", func(i SearchResultItem) string {
+		return joinLocations(i, func(l PositionLocation) string { return l.CountryCode })
+	}},
+	{"COORDINATES", "location latitude,longitude pairs", func(i SearchResultItem) string {
+		return joinLocations(i, func(l PositionLocation) string {
+			p, ok := l.Point()
+			if !ok {
+				return ""
+			}
+			return fmt.Sprintf("%g,%g", p.Latitude, p.Longitude)
+		})
+	}},
+	{"JOB_CATEGORIES", "occupational series names", func(i SearchResultItem) string {
+		var s []string
+		for _, c := range i.MatchedObjectDescriptor.JobCategory {
+			s = append(s, c.Name)
+		}
+		return join(s)
+	}},
+	{"JOB_CATEGORY_CODES", "occupational series codes", func(i SearchResultItem) string {
+		var s []string
+		for _, c := range i.MatchedObjectDescriptor.JobCategory {
+			s = append(s, c.Code)
+		}
+		return join(s)
+	}},
+	{"PAY_PLANS", "pay plan codes (ex., GS)", func(i SearchResultItem) string {
+		var s []string
+		for _, g := range i.MatchedObjectDescriptor.JobGrade {
+			s = append(s, g.Code)
+		}
+		return join(s)
+	}},
+	{"LOW_GRADE", "lowest grade advertised", func(i SearchResultItem) string { return details(i).LowGrade }},
+	{"HIGH_GRADE", "highest grade advertised", func(i SearchResultItem) string { return details(i).HighGrade }},
+	{"PROMOTION_POTENTIAL", "highest grade the position can be promoted to", func(i SearchResultItem) string {
+		return details(i).PromotionPotential
+	}},
+	{"MIN_SALARY", "minimum salary", func(i SearchResultItem) string {
+		var s []string
+		for _, r := range i.MatchedObjectDescriptor.PositionRemuneration {
+			s = append(s, r.MinimumRange)
+		}
+		return joinAligned(s)
+	}},
+	{"MAX_SALARY", "maximum salary", func(i SearchResultItem) string {
+		var s []string
+		for _, r := range i.MatchedObjectDescriptor.PositionRemuneration {
+			s = append(s, r.MaximumRange)
+		}
+		return joinAligned(s)
+	}},
+	{"SALARY_INTERVAL", "interval the salary is paid over (ex., Per Year)", func(i SearchResultItem) string {
+		var s []string
+		for _, r := range i.MatchedObjectDescriptor.PositionRemuneration {
+			s = append(s, r.Description)
+		}
+		return joinAligned(s)
+	}},
+	{"SCHEDULES", "work schedules (ex., Full-time)", func(i SearchResultItem) string {
+		var s []string
+		for _, p := range i.MatchedObjectDescriptor.PositionSchedule {
+			s = append(s, p.Name)
+		}
+		return join(s)
+	}},
+	{"OFFERING_TYPES", "appointment types (ex., Permanent)", func(i SearchResultItem) string {
+		var s []string
+		for _, p := range i.MatchedObjectDescriptor.PositionOfferingType {
+			s = append(s, p.Name)
+		}
+		return join(s)
+	}},
+	{"WHO_MAY_APPLY", "who may apply", func(i SearchResultItem) string { return details(i).WhoMayApply.Name }},
+	{"HIRING_PATHS", "hiring paths", func(i SearchResultItem) string { return join(details(i).HiringPath) }},
+	{"TOTAL_OPENINGS", "number of openings", func(i SearchResultItem) string { return details(i).TotalOpenings }},
+	{"PUBLICATION_START_DATE", "date the announcement opened", func(i SearchResultItem) string {
+		return i.MatchedObjectDescriptor.PublicationStartDate
+	}},
+	{"CLOSE_DATE", "date the announcement closes", func(i SearchResultItem) string {
+		return i.MatchedObjectDescriptor.ApplicationCloseDate
+	}},
+	{"POSITION_START_DATE", "position start date", func(i SearchResultItem) string {
+		return i.MatchedObjectDescriptor.PositionStartDate
+	}},
+	{"POSITION_END_DATE", "position end date", func(i SearchResultItem) string {
+		return i.MatchedObjectDescriptor.PositionEndDate
+	}},
+	{"TELEWORK_ELIGIBLE", "telework eligible", func(i SearchResultItem) string {
+		return strconv.FormatBool(details(i).TeleworkEligible)
+	}},
+	{"REMOTE", "remote job", func(i SearchResultItem) string { return strconv.FormatBool(details(i).RemoteIndicator) }},
+	{"RELOCATION", "relocation expenses reimbursed", func(i SearchResultItem) string { return details(i).Relocation }},
+	{"TRAVEL", "travel required", func(i SearchResultItem) string { return details(i).TravelCode }},
+	{"SECURITY_CLEARANCE", "security clearance required", func(i SearchResultItem) string {
+		return details(i).SecurityClearance
+	}},
+	{"DRUG_TEST_REQUIRED", "drug test required", func(i SearchResultItem) string { return details(i).DrugTestRequired }},
+	{"POSITION_SENSITIVITY", "position sensitivity and risk code", func(i SearchResultItem) string {
+		return details(i).PositionSensitivity
+	}},
+	{"ADJUDICATION_TYPES", "background investigation adjudication types", func(i SearchResultItem) string {
+		return join(details(i).AdjudicationType)
+	}},
+	{"SERVICE_TYPE", "service type code", func(i SearchResultItem) string { return details(i).ServiceType }},
+	{"ANNOUNCEMENT_CLOSING_TYPE", "announcement closing type code", func(i SearchResultItem) string {
+		return details(i).AnnouncementClosingType
+	}},
+	{"AGENCY_CONTACT_EMAIL", "agency contact email", func(i SearchResultItem) string { return details(i).AgencyContactEmail }},
+	{"AGENCY_CONTACT_PHONE", "agency contact phone", func(i SearchResultItem) string { return details(i).AgencyContactPhone }},
+	{"URL", "job announcement url", func(i SearchResultItem) string { return i.MatchedObjectDescriptor.PositionURI }},
+	{"APPLY_URL", "urls to apply through", func(i SearchResultItem) string {
+		return join(i.MatchedObjectDescriptor.ApplyURI)
+	}},
+	{"APPLY_ONLINE_URL", "online application url", func(i SearchResultItem) string { return details(i).ApplyOnlineURL }},
+	{"DETAIL_STATUS_URL", "application status url", func(i SearchResultItem) string { return details(i).DetailStatusURL }},
+	{"BENEFITS_URL", "benefits url", func(i SearchResultItem) string { return details(i).BenefitsURL }},
+	{"QUALIFICATION_SUMMARY", "qualification summary", func(i SearchResultItem) string {
+		return i.MatchedObjectDescriptor.QualificationSummary
+	}},
+	{"JOB_SUMMARY", "job summary", func(i SearchResultItem) string { return details(i).JobSummary }},
+	{"MAJOR_DUTIES", "major duties", func(i SearchResultItem) string { return join(details(i).MajorDuties) }},
+	{"KEY_REQUIREMENTS", "key requirements", func(i SearchResultItem) string {
+		var s []string
+		for _, r := range details(i).KeyRequirements {
+			s = append(s, fmt.Sprint(r))
+		}
+		return join(s)
+	}},
+	{"EDUCATION", "education", func(i SearchResultItem) string { return details(i).Education }},
+	{"REQUIREMENTS", "requirements", func(i SearchResultItem) string { return details(i).Requirements }},
+	{"EVALUATIONS", "how applicants are evaluated", func(i SearchResultItem) string { return details(i).Evaluations }},
+	{"HOW_TO_APPLY", "how to apply", func(i SearchResultItem) string { return details(i).HowToApply }},
+	{"WHAT_TO_EXPECT_NEXT", "what to expect next", func(i SearchResultItem) string { return details(i).WhatToExpectNext }},
+	{"REQUIRED_DOCUMENTS", "required documents", func(i SearchResultItem) string { return details(i).RequiredDocuments }},
+	{"BENEFITS", "benefits", func(i SearchResultItem) string { return details(i).Benefits }},
+	{"OTHER_INFORMATION", "other information", func(i SearchResultItem) string { return details(i).OtherInformation }},
+	{"AGENCY_MARKETING_STATEMENT", "agency marketing statement", func(i SearchResultItem) string {
+		return details(i).AgencyMarketingStatement
+	}},
+	{"RELEVANCE_RANK", "search relevance rank", func(i SearchResultItem) string {
+		return strconv.FormatFloat(i.RelevanceRank, 'f', -1, 64)
+	}},
+}
+
+// LookupSearchItemFields returns the fields with the provided names, matched
+// case-insensitively, in the order provided.
+func LookupSearchItemFields(names []string) ([]SearchItemField, error) {
+	var fields []SearchItemField
+	for _, name := range names {
+		f, ok := lookupSearchItemField(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown search field %q, valid fields are: %s", name, strings.Join(SearchItemFieldNames(), ", "))
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// SearchItemFieldNames returns the name of every search item field.
+func SearchItemFieldNames() []string {
+	names := make([]string, 0, len(SearchItemFields))
+	for _, f := range SearchItemFields {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+// Row returns the values of the provided fields for the search result item.
+func (i SearchResultItem) Row(fields []SearchItemField) []string {
+	row := make([]string, 0, len(fields))
+	for _, f := range fields {
+		row = append(row, f.Value(i))
+	}
+	return row
+}
+
+func lookupSearchItemField(name string) (SearchItemField, bool) {
+	name = strings.ReplaceAll(strings.TrimSpace(name), "-", "_")
+	for _, f := range SearchItemFields {
+		if strings.EqualFold(f.Name, name) {
+			return f, true
+		}
+	}
+	return SearchItemField{}, false
+}
+
+func details(i SearchResultItem) SearchItemDetails {
+	return i.MatchedObjectDescriptor.UserArea.Details
+}
+
+func joinLocations(i SearchResultItem, value func(PositionLocation) string) string {
+	var s []string
+	for _, l := range i.MatchedObjectDescriptor.PositionLocation {
+		s = append(s, value(l))
+	}
+	return joinAligned(s)
+}
+
+// joinAligned flattens a repeated field whose values line up with another
+// repeated field (ex., location names and coordinates), so empty values are
+// kept to preserve their position.
+func joinAligned(values []string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return strings.Join(values, FieldSeparator)
+		}
+	}
+	return ""
+}
+
+// join flattens a repeated field, dropping empty values.
+func join(values []string) string {
+	var s []string
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			s = append(s, v)
+		}
+	}
+	return strings.Join(s, FieldSeparator)
+}
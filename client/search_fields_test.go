@@ -0,0 +1,91 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package usajobs_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+)
+
+func TestSearchItemFields(t *testing.T) {
+	b, err := os.ReadFile(searchTestDataPath)
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	var data usajobs.SearchResponse
+	err = json.Unmarshal(b, &data)
+	if err != nil {
+		t.Fatalf("could not unmarshal test data: %v", err)
+	}
+	item := data.SearchResult.SearchResultItems[0]
+
+	fields, err := usajobs.LookupSearchItemFields([]string{"position-id", "Locations", "COORDINATES", "HIRING_PATHS", "MIN_SALARY", "PROMOTION_POTENTIAL", "REMOTE"})
+	if err != nil {
+		t.Fatalf("could not look up fields: %v", err)
+	}
+
+	want := []string{
+		"ICE-24-12345-MP",
+		"Washington, District of Columbia; Dallas, Texas",
+		"38.89037,-77.03196; 32.78306,-96.80667",
+		"fed-competitive; vet",
+		"117962.0",
+		"14",
+		"false",
+	}
+
+	row := item.Row(fields)
+	for i := range want {
+		if row[i] != want[i] {
+			t.Errorf("%s: expected %q, got %q", fields[i].Name, want[i], row[i])
+		}
+	}
+
+	_, err = usajobs.LookupSearchItemFields([]string{"JOB_TITLE", "SALARY"})
+	if err == nil {
+		t.Error("expected error for unknown field, got nil")
+	}
+
+	seen := map[string]bool{}
+	for _, name := range usajobs.SearchItemFieldNames() {
+		if seen[name] {
+			t.Errorf("duplicate field name %s", name)
+		}
+		seen[name] = true
+	}
+}
+
+func TestSearchItemFieldsAligned(t *testing.T) {
+	var item usajobs.SearchResultItem
+	item.MatchedObjectDescriptor.PositionLocation = []usajobs.PositionLocation{
+		{LocationName: "Anywhere in the U.S. (remote job)"},
+		{LocationName: "Austin, Texas", Latitude: 30.26715, Longitude: -97.74306},
+	}
+
+	fields, err := usajobs.LookupSearchItemFields([]string{"LOCATIONS", "COORDINATES"})
+	if err != nil {
+		t.Fatalf("could not look up fields: %v", err)
+	}
+
+	row := item.Row(fields)
+	if row[1] != "; 30.26715,-97.74306" {
+		t.Errorf("expected coordinates to stay aligned with locations, got %q", row[1])
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package usajobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrPostingNotFound is returned by ByID when no posting has the id.
+var ErrPostingNotFound = errors.New("posting not found")
+
+// ByID returns the full announcement of a single posting by its
+// MatchedObjectID (the usajobs control number, ex., 800000001) or its
+// PositionID (ex., ICE-24-12345-MP). /search cannot filter by either, so the
+// id is searched as a keyword and the results are matched against it.
+func (s *SearchService) ByID(ctx context.Context, id string) (*SearchResultItem, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, errors.New("posting id required")
+	}
+
+	opt := SearchOptions{
+		Keyword:        id,
+		Fields:         SearchFieldsFull,
+		ResultsPerPage: 500,
+	}
+
+	r, data, err := s.WithOptionsContext(ctx, &opt)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.StatusCode != http.StatusOK {
+		return nil, errors.New("bad response from usajobs: " + r.Status)
+	}
+
+	for _, item := range data.SearchResult.SearchResultItems {
+		if item.MatchedObjectID == id || strings.EqualFold(item.MatchedObjectDescriptor.PositionID, id) {
+			return &item, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s: %w", id, ErrPostingNotFound)
+}
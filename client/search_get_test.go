@@ -0,0 +1,77 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package usajobs_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+)
+
+func TestSearchByID(t *testing.T) {
+	data, err := os.ReadFile(searchTestDataPath)
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("Fields") != "Full" || r.URL.Query().Get("Keyword") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	}))
+	defer mockServer.Close()
+
+	c, err := usajobs.NewClient("test", "test")
+	if err != nil {
+		t.Fatalf("could not create new usajobs client: %v", err)
+	}
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+	c.BaseURL = u
+
+	for _, id := range []string{"800000001", "ICE-24-12345-MP", "ice-24-12345-mp"} {
+		item, err := c.Search.ByID(context.Background(), id)
+		if err != nil {
+			t.Fatalf("failed to get %s: %v", id, err)
+		}
+		if item.MatchedObjectID != "800000001" {
+			t.Errorf("expected posting 800000001 for %s, got %s", id, item.MatchedObjectID)
+		}
+	}
+
+	_, err = c.Search.ByID(context.Background(), "123")
+	if !errors.Is(err, usajobs.ErrPostingNotFound) {
+		t.Errorf("expected ErrPostingNotFound, got %v", err)
+	}
+
+	_, err = c.Search.ByID(context.Background(), " ")
+	if err == nil {
+		t.Error("expected error for empty id, got nil")
+	}
+}
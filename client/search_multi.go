@@ -0,0 +1,98 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package usajobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// multiSearchConcurrency is the number of /search requests Multi keeps in
+// flight at once.
+const multiSearchConcurrency = 4
+
+// MultiSearchResult is a single posting returned by Multi along with the
+// indexes of every SearchOptions query that returned it.
+type MultiSearchResult struct {
+	Item    SearchResultItem
+	Queries []int
+}
+
+// Multi runs each of the provided searches concurrently and de-duplicates the
+// postings they return by MatchedObjectID. Results are ordered by the first
+// query that returned them and then by their position in that query's
+// results, so the output does not depend on which request finished first.
+// Postings from searches that succeeded are returned alongside any errors,
+// which are joined. Cancelling ctx stops any searches still in flight.
+func (s *SearchService) Multi(ctx context.Context, opts []SearchOptions) ([]MultiSearchResult, error) {
+	responses := make([][]SearchResultItem, len(opts))
+	errs := make([]error, len(opts))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, multiSearchConcurrency)
+	for i := range opts {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = fmt.Errorf("query %d: %w", i, ctx.Err())
+				return
+			}
+
+			r, data, err := s.WithOptionsContext(ctx, &opts[i])
+			if err != nil {
+				errs[i] = fmt.Errorf("query %d: %w", i, err)
+				return
+			}
+
+			if r.StatusCode != http.StatusOK {
+				errs[i] = fmt.Errorf("query %d: bad response from usajobs: %s", i, r.Status)
+				return
+			}
+
+			responses[i] = data.SearchResult.SearchResultItems
+		}(i)
+	}
+	wg.Wait()
+
+	var results []MultiSearchResult
+	index := map[string]int{}
+	for q, items := range responses {
+		for _, item := range items {
+			i, ok := index[item.MatchedObjectID]
+			if !ok {
+				index[item.MatchedObjectID] = len(results)
+				results = append(results, MultiSearchResult{Item: item, Queries: []int{q}})
+				continue
+			}
+
+			// only record a query once even if it returned the posting twice
+			last := results[i].Queries[len(results[i].Queries)-1]
+			if last != q {
+				results[i].Queries = append(results[i].Queries, q)
+			}
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package usajobs_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+)
+
+func TestSearchMulti(t *testing.T) {
+	data, err := os.ReadFile(searchTestDataPath)
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	var full usajobs.SearchResponse
+	err = json.Unmarshal(data, &full)
+	if err != nil {
+		t.Fatalf("could not decode test data: %v", err)
+	}
+
+	// each keyword returns a different slice of the test data so postings
+	// overlap between queries.
+	slices := map[string][]usajobs.SearchResultItem{
+		"security": full.SearchResult.SearchResultItems[0:2],
+		"software": full.SearchResult.SearchResultItems[1:3],
+		"nurse":    full.SearchResult.SearchResultItems[2:3],
+	}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Log(r.URL.String())
+
+		items, ok := slices[r.URL.Query().Get("Keyword")]
+		if !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{}`))
+			return
+		}
+
+		sr := usajobs.SearchResponse{}
+		sr.SearchResult.SearchResultItems = items
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(sr)
+	}))
+	defer mockServer.Close()
+
+	c, err := usajobs.NewClient("test", "test")
+	if err != nil {
+		t.Fatalf("could not create new usajobs client: %v", err)
+	}
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+
+	c.BaseURL = u
+
+	opts := []usajobs.SearchOptions{
+		{Keyword: "security"},
+		{Keyword: "software"},
+		{Keyword: "nurse"},
+	}
+
+	results, err := c.Search.Multi(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("failed to execute searches: %v", err.Error())
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("expected %d unique postings, got %d", 3, len(results))
+	}
+
+	expected := map[string][]int{
+		"800000001": {0},
+		"800000002": {0, 1},
+		"800000003": {1, 2},
+	}
+
+	for i, id := range []string{"800000001", "800000002", "800000003"} {
+		if results[i].Item.MatchedObjectID != id {
+			t.Fatalf("expected posting %s at index %d, got %s", id, i, results[i].Item.MatchedObjectID)
+		}
+
+		if len(results[i].Queries) != len(expected[id]) {
+			t.Fatalf("expected queries %v for %s, got %v", expected[id], id, results[i].Queries)
+		}
+
+		for j, q := range expected[id] {
+			if results[i].Queries[j] != q {
+				t.Errorf("expected queries %v for %s, got %v", expected[id], id, results[i].Queries)
+			}
+		}
+	}
+
+	// a failed query still returns postings from the queries that succeeded
+	results, err = c.Search.Multi(context.Background(), append(opts, usajobs.SearchOptions{Keyword: "broken"}))
+	if err == nil {
+		t.Fatal("expected error for failed query, got nil")
+	}
+
+	if len(results) != 3 {
+		t.Errorf("expected %d unique postings alongside the error, got %d", 3, len(results))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = c.Search.Multi(ctx, opts)
+	if err == nil {
+		t.Fatal("expected error for cancelled context, got nil")
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package usajobs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// SkipPages can be returned by the function passed to Pages to stop paging
+// without Pages returning an error.
+var SkipPages = errors.New("skip remaining pages")
+
+// PageCount returns the number of pages of results usajobs reported for the
+// search, or 0 when it was not reported.
+func (r *SearchResponse) PageCount() int {
+	n, err := strconv.Atoi(r.SearchResult.UserArea.NumberOfPages)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// Pages requests each page of results for the provided options, starting at
+// opt.Page (or the first page), and calls fn with the page number and
+// response as each page arrives. Paging stops after the last page reported by
+// usajobs or a page without results. Returning SkipPages from fn stops paging
+// early, and returning any other error stops paging and returns that error.
+// Pass nil if no options desired.
+func (s *SearchService) Pages(ctx context.Context, opt *SearchOptions, fn func(page int, r *SearchResponse) error) error {
+	page := SearchOptions{}
+	if opt != nil {
+		page = *opt
+	}
+	if page.Page < 1 {
+		page.Page = 1
+	}
+
+	for {
+		r, data, err := s.WithOptionsContext(ctx, &page)
+		if err != nil {
+			return err
+		}
+
+		if r.StatusCode != http.StatusOK {
+			return errors.New("bad response from usajobs: " + r.Status)
+		}
+
+		err = fn(page.Page, &data)
+		if errors.Is(err, SkipPages) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(data.SearchResult.SearchResultItems) == 0 || page.Page >= data.PageCount() {
+			return nil
+		}
+		page.Page++
+	}
+}
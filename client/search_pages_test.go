@@ -0,0 +1,124 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package usajobs_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"testing"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+)
+
+func TestSearchPages(t *testing.T) {
+	data, err := os.ReadFile(searchTestDataPath)
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	var full usajobs.SearchResponse
+	err = json.Unmarshal(data, &full)
+	if err != nil {
+		t.Fatalf("could not decode test data: %v", err)
+	}
+	items := full.SearchResult.SearchResultItems
+
+	// each page returns a single posting of the test data
+	var requested []int
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, err := strconv.Atoi(r.URL.Query().Get("Page"))
+		if err != nil || page < 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		requested = append(requested, page)
+
+		sr := usajobs.SearchResponse{}
+		sr.SearchResult.SearchResultCountAll = len(items)
+		sr.SearchResult.UserArea.NumberOfPages = strconv.Itoa(len(items))
+		if page <= len(items) {
+			sr.SearchResult.SearchResultItems = items[page-1 : page]
+			sr.SearchResult.SearchResultCount = 1
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(sr)
+	}))
+	defer mockServer.Close()
+
+	c, err := usajobs.NewClient("test", "test")
+	if err != nil {
+		t.Fatalf("could not create new usajobs client: %v", err)
+	}
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+	c.BaseURL = u
+
+	var got []string
+	err = c.Search.Pages(context.Background(), nil, func(page int, r *usajobs.SearchResponse) error {
+		if r.PageCount() != len(items) {
+			t.Errorf("expected %d pages, got %d", len(items), r.PageCount())
+		}
+		for _, item := range r.SearchResult.SearchResultItems {
+			got = append(got, item.MatchedObjectID)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to page search results: %v", err)
+	}
+
+	if len(got) != len(items) || len(requested) != len(items) {
+		t.Fatalf("expected %d postings from %d requests, got %v from %v", len(items), len(items), got, requested)
+	}
+	for i := range items {
+		if got[i] != items[i].MatchedObjectID {
+			t.Errorf("expected %s at %d, got %s", items[i].MatchedObjectID, i, got[i])
+		}
+	}
+
+	// start at the second page and stop after it
+	requested = nil
+	err = c.Search.Pages(context.Background(), &usajobs.SearchOptions{Page: 2}, func(page int, r *usajobs.SearchResponse) error {
+		if page != 2 {
+			t.Errorf("expected page 2, got %d", page)
+		}
+		return usajobs.SkipPages
+	})
+	if err != nil {
+		t.Fatalf("expected SkipPages to stop without an error, got %v", err)
+	}
+	if len(requested) != 1 || requested[0] != 2 {
+		t.Errorf("expected only page 2 to be requested, got %v", requested)
+	}
+
+	stop := errors.New("stop")
+	err = c.Search.Pages(context.Background(), nil, func(page int, r *usajobs.SearchResponse) error {
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("expected the error returned by fn, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package usajobs
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownSearchParameter is returned by ParseSearchOptions for url
+// parameters /search does not support.
+var ErrUnknownSearchParameter = errors.New("unknown search parameter")
+
+// ParseSearchOptions is the reverse of encoding SearchOptions as url
+// parameters: it reads the /search parameters in values (ex., Keyword=nurse&
+// JobCategoryCode=0610;0620) into SearchOptions. Parameter names are matched
+// ignoring case, and list parameters may be repeated or separated by ";".
+func ParseSearchOptions(values url.Values) (SearchOptions, error) {
+	var opt SearchOptions
+	v := reflect.ValueOf(&opt).Elem()
+	t := v.Type()
+
+	fields := map[string]int{}
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("url"), ",")
+		fields[strings.ToLower(name)] = i
+	}
+
+	for name, params := range values {
+		i, ok := fields[strings.ToLower(name)]
+		if !ok {
+			return opt, fmt.Errorf("%s: %w", name, ErrUnknownSearchParameter)
+		}
+
+		f := v.Field(i)
+		if f.Kind() != reflect.Slice {
+			err := setSearchOption(f, params[len(params)-1])
+			if err != nil {
+				return opt, fmt.Errorf("invalid %s: %w", name, err)
+			}
+			continue
+		}
+
+		for _, p := range params {
+			for _, s := range strings.Split(p, ";") {
+				s = strings.TrimSpace(s)
+				if s == "" {
+					continue
+				}
+
+				e := reflect.New(f.Type().Elem()).Elem()
+				err := setSearchOption(e, s)
+				if err != nil {
+					return opt, fmt.Errorf("invalid %s: %w", name, err)
+				}
+				f.Set(reflect.Append(f, e))
+			}
+		}
+	}
+	return opt, nil
+}
+
+func setSearchOption(f reflect.Value, s string) error {
+	s = strings.TrimSpace(s)
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(s)
+	case reflect.Int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		f.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		f.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported type %s", f.Type())
+	}
+	return nil
+}
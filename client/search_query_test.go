@@ -0,0 +1,84 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package usajobs_test
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+	"github.com/google/go-querystring/query"
+)
+
+func TestParseSearchOptions(t *testing.T) {
+	want := usajobs.SearchOptions{
+		Keyword:                   "nurse",
+		JobCategoryCode:           []string{"0610", "0620"},
+		LocationName:              []string{"Austin, Texas", "Portland, Oregon"},
+		PositionScheduleTypeCode:  []int{1, 2},
+		SecurityClearanceRequired: []int{3},
+		RemoteIndicator:           true,
+		ResultsPerPage:            50,
+		WhoMayApply:               usajobs.WhoMayApplyPublic,
+		Fields:                    usajobs.SearchFieldsFull,
+	}
+
+	// options round trip through their url encoding
+	values, err := query.Values(want)
+	if err != nil {
+		t.Fatalf("could not encode options: %v", err)
+	}
+	got, err := usajobs.ParseSearchOptions(values)
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", values.Encode(), err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	// names ignore case, and lists may be repeated
+	values = url.Values{
+		"keyword":                   {"nurse"},
+		"JOBCATEGORYCODE":           {"0610", "0620"},
+		"LocationName":              {"Austin, Texas;Portland, Oregon"},
+		"PositionSchedule":          {"1;2"},
+		"SecurityClearanceRequired": {"3"},
+		"RemoteIndicator":           {"true"},
+		"resultsperpage":            {"50"},
+		"WhoMayApply":               {"public"},
+		"Fields":                    {"Full"},
+	}
+	got, err = usajobs.ParseSearchOptions(values)
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", values.Encode(), err)
+	}
+	want.WhoMayApply = "public"
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	_, err = usajobs.ParseSearchOptions(url.Values{"Salary": {"1"}})
+	if !errors.Is(err, usajobs.ErrUnknownSearchParameter) {
+		t.Errorf("expected ErrUnknownSearchParameter, got %v", err)
+	}
+
+	_, err = usajobs.ParseSearchOptions(url.Values{"Page": {"two"}})
+	if err == nil {
+		t.Error("expected an error for an invalid page")
+	}
+}
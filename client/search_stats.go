@@ -0,0 +1,369 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package usajobs
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// StatsDimension is a way of grouping search results in SearchStats.
+type StatsDimension string
+
+// Dimensions search results can be grouped by.
+const (
+	StatsDepartment StatsDimension = "department"
+	StatsAgency     StatsDimension = "agency"
+	StatsSeries     StatsDimension = "series"
+	StatsGrade      StatsDimension = "grade"
+	StatsState      StatsDimension = "state"
+	StatsSchedule   StatsDimension = "schedule"
+	StatsSalary     StatsDimension = "salary"
+)
+
+// StatsDimensions are the dimensions search results can be grouped by, in
+// the order they are reported.
+var StatsDimensions = []StatsDimension{StatsDepartment, StatsAgency, StatsSeries, StatsGrade, StatsState, StatsSchedule, StatsSalary}
+
+// ParseStatsDimension returns the dimension named s, ignoring case.
+func ParseStatsDimension(s string) (StatsDimension, error) {
+	for _, d := range StatsDimensions {
+		if strings.EqualFold(strings.TrimSpace(s), string(d)) {
+			return d, nil
+		}
+	}
+
+	var names []string
+	for _, d := range StatsDimensions {
+		names = append(names, string(d))
+	}
+	return "", fmt.Errorf("invalid stats dimension %q, expected one of %s", s, strings.Join(names, ", "))
+}
+
+// DefaultSalaryBand is the width, in dollars a year, of the salary bands
+// used when none is set.
+const DefaultSalaryBand = 20000
+
+// hoursPerYear is the number of paid hours in a federal work year.
+const hoursPerYear = 2087
+
+// annualRates convert pay at a rate interval code to dollars a year. Pay at
+// other intervals (ex., fee basis or piece work) cannot be compared.
+var annualRates = map[string]float64{
+	"PA": 1,
+	"SY": 1,
+	"PM": 12,
+	"BW": 26,
+	"PD": 260,
+	"PH": hoursPerYear,
+}
+
+// StatsBucket is the number of search results with a value of a dimension.
+type StatsBucket struct {
+	// Key is the value (ex., "Texas" or "GS-13"), or a series code.
+	Key string `json:"key"`
+	// Name describes keys that are codes (ex., the name of a series).
+	Name string `json:"name,omitempty"`
+	// Count is the number of results with the value.
+	Count int `json:"count"`
+	// Percent is Count as a percent of every result.
+	Percent float64 `json:"percent"`
+}
+
+// Label returns the key followed by its name, if any.
+func (b StatsBucket) Label() string {
+	if b.Name == "" {
+		return b.Key
+	}
+	return b.Key + " " + b.Name
+}
+
+// SearchStats summarizes search results.
+type SearchStats struct {
+	// Postings is the number of results summarized.
+	Postings int `json:"postings"`
+	// Remote is the number of results offering remote work.
+	Remote int `json:"remote"`
+	// Telework is the number of results eligible for telework.
+	Telework int `json:"telework"`
+	// MedianGSGrade is the median lowest grade of GS results, or 0 when
+	// there are none.
+	MedianGSGrade float64 `json:"medianGSGrade"`
+	// Salaries are the lowest, median and highest yearly pay of results
+	// paid at an interval that can be converted to a year. The medians are
+	// of the bottom and top of each pay range.
+	LowestSalary    float64 `json:"lowestSalary"`
+	MedianMinSalary float64 `json:"medianMinSalary"`
+	MedianMaxSalary float64 `json:"medianMaxSalary"`
+	HighestSalary   float64 `json:"highestSalary"`
+	// SalaryBand is the width of the salary buckets in dollars a year.
+	SalaryBand int `json:"salaryBand"`
+	// Groups are the buckets of each dimension, largest first, except grade
+	// and salary which are in order. A result with several series or
+	// locations is counted in each of them.
+	Groups map[StatsDimension][]StatsBucket `json:"groups"`
+}
+
+// RemotePercent returns the percent of results offering remote work.
+func (s SearchStats) RemotePercent() float64 {
+	return percent(s.Remote, s.Postings)
+}
+
+// TeleworkPercent returns the percent of results eligible for telework.
+func (s SearchStats) TeleworkPercent() float64 {
+	return percent(s.Telework, s.Postings)
+}
+
+// AnnualSalary returns the bottom and top of a pay range in dollars a year.
+// ok is false when the pay cannot be converted to a year.
+func AnnualSalary(r PositionRemuneration) (low, high float64, ok bool) {
+	rate, ok := annualRates[strings.ToUpper(strings.TrimSpace(r.RateIntervalCode))]
+	if !ok {
+		return 0, 0, false
+	}
+
+	low, err := strconv.ParseFloat(strings.ReplaceAll(r.MinimumRange, ",", ""), 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	high, err = strconv.ParseFloat(strings.ReplaceAll(r.MaximumRange, ",", ""), 64)
+	if err != nil || high < low {
+		high = low
+	}
+	return low * rate, high * rate, true
+}
+
+// SummarizeSearch returns statistics of search results. Salaries are
+// grouped into bands of salaryBand dollars a year by the bottom of their
+// pay range, or DefaultSalaryBand when salaryBand is not positive.
+func SummarizeSearch(items []SearchResultItem, salaryBand int) SearchStats {
+	if salaryBand <= 0 {
+		salaryBand = DefaultSalaryBand
+	}
+
+	stats := SearchStats{
+		Postings:   len(items),
+		SalaryBand: salaryBand,
+		Groups:     map[StatsDimension][]StatsBucket{},
+	}
+
+	counts := map[StatsDimension]map[string]int{}
+	names := map[string]string{}
+	count := func(d StatsDimension, key string) {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			key = "Unknown"
+		}
+		if counts[d] == nil {
+			counts[d] = map[string]int{}
+		}
+		counts[d][key]++
+	}
+
+	var gsGrades, lows, highs []float64
+	// order holds the pay plan and grade, or band, of grade and salary keys
+	order := map[string][2]string{}
+	for _, item := range items {
+		m := item.MatchedObjectDescriptor
+		details := m.UserArea.Details
+
+		if details.RemoteIndicator {
+			stats.Remote++
+		}
+		if details.TeleworkEligible {
+			stats.Telework++
+		}
+
+		count(StatsDepartment, m.DepartmentName)
+
+		agency := details.SubAgencyName
+		if agency == "" {
+			agency = m.OrganizationName
+		}
+		count(StatsAgency, agency)
+
+		series := map[string]bool{}
+		for _, c := range m.JobCategory {
+			if series[c.Code] {
+				continue
+			}
+			series[c.Code] = true
+			count(StatsSeries, c.Code)
+			if c.Name != "" {
+				names[strings.TrimSpace(c.Code)] = strings.TrimSpace(c.Name)
+			}
+		}
+		if len(m.JobCategory) == 0 {
+			count(StatsSeries, "")
+		}
+
+		plan := ""
+		if len(m.JobGrade) > 0 {
+			plan = strings.TrimSpace(m.JobGrade[0].Code)
+		}
+		grade := strings.TrimSpace(details.LowGrade)
+		switch {
+		case plan == "" && grade == "":
+			count(StatsGrade, "")
+		default:
+			key := strings.Trim(plan+"-"+grade, "-")
+			order[key] = [2]string{plan, grade}
+			count(StatsGrade, key)
+		}
+		if strings.EqualFold(plan, "GS") {
+			g, err := strconv.ParseFloat(grade, 64)
+			if err == nil {
+				gsGrades = append(gsGrades, g)
+			}
+		}
+
+		states := map[string]bool{}
+		for _, l := range m.PositionLocation {
+			state := strings.TrimSpace(l.CountrySubDivisionCode)
+			if states[state] {
+				continue
+			}
+			states[state] = true
+			count(StatsState, state)
+		}
+		if len(m.PositionLocation) == 0 {
+			count(StatsState, "")
+		}
+
+		schedules := map[string]bool{}
+		for _, s := range m.PositionSchedule {
+			if schedules[s.Name] {
+				continue
+			}
+			schedules[s.Name] = true
+			count(StatsSchedule, s.Name)
+		}
+		if len(m.PositionSchedule) == 0 {
+			count(StatsSchedule, "")
+		}
+
+		paid := false
+		for _, r := range m.PositionRemuneration {
+			low, high, ok := AnnualSalary(r)
+			if !ok {
+				continue
+			}
+			paid = true
+			lows = append(lows, low)
+			highs = append(highs, high)
+			band := int(low) / salaryBand * salaryBand
+			key := salaryBandKey(band, salaryBand)
+			order[key] = [2]string{"", strconv.Itoa(band)}
+			count(StatsSalary, key)
+			break
+		}
+		if !paid {
+			count(StatsSalary, "")
+		}
+	}
+
+	stats.MedianGSGrade = median(gsGrades)
+	stats.MedianMinSalary = median(lows)
+	stats.MedianMaxSalary = median(highs)
+	if len(lows) > 0 {
+		stats.LowestSalary = slices.Min(lows)
+		stats.HighestSalary = slices.Max(highs)
+	}
+
+	for d, keys := range counts {
+		var buckets []StatsBucket
+		for key, n := range keys {
+			buckets = append(buckets, StatsBucket{Key: key, Name: names[key], Count: n, Percent: percent(n, stats.Postings)})
+		}
+
+		switch d {
+		case StatsGrade, StatsSalary:
+			sort.Slice(buckets, func(i, j int) bool {
+				return orderedBefore(order, buckets[i].Key, buckets[j].Key)
+			})
+		default:
+			sort.Slice(buckets, func(i, j int) bool {
+				if buckets[i].Count != buckets[j].Count {
+					return buckets[i].Count > buckets[j].Count
+				}
+				return buckets[i].Key < buckets[j].Key
+			})
+		}
+		stats.Groups[d] = buckets
+	}
+
+	return stats
+}
+
+// salaryBandKey returns the label of the salary band starting at band (ex.,
+// "$80,000-$99,999").
+func salaryBandKey(band, width int) string {
+	return "$" + thousands(band) + "-$" + thousands(band+width-1)
+}
+
+// thousands formats n with comma separators (ex., 80,000).
+func thousands(n int) string {
+	s := strconv.Itoa(n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
+// orderedBefore orders grade and salary keys by pay plan, then numerically
+// by grade or band. Unknown keys are last.
+func orderedBefore(order map[string][2]string, a, b string) bool {
+	oa, okA := order[a]
+	ob, okB := order[b]
+	if !okA || !okB {
+		return okA && !okB || (!okA && !okB && a < b)
+	}
+	if oa[0] != ob[0] {
+		return oa[0] < ob[0]
+	}
+
+	na, errA := strconv.Atoi(oa[1])
+	nb, errB := strconv.Atoi(ob[1])
+	if errA == nil && errB == nil {
+		return na < nb
+	}
+	return oa[1] < ob[1]
+}
+
+// median sorts values and returns their median, or 0 when there are none.
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sort.Float64s(values)
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2
+	}
+	return values[mid]
+}
+
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) * 100 / float64(total)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package usajobs_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+)
+
+func TestSummarizeSearch(t *testing.T) {
+	b, err := os.ReadFile(searchTestDataPath)
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	var data usajobs.SearchResponse
+	err = json.Unmarshal(b, &data)
+	if err != nil {
+		t.Fatalf("could not unmarshal test data: %v", err)
+	}
+
+	// an hourly posting without a location
+	var hourly usajobs.SearchResultItem
+	hourly.MatchedObjectDescriptor.DepartmentName = "Department of the Army"
+	hourly.MatchedObjectDescriptor.JobGrade = append(hourly.MatchedObjectDescriptor.JobGrade, struct {
+		Code string `json:"Code,omitempty"`
+	}{Code: "GS"})
+	hourly.MatchedObjectDescriptor.UserArea.Details.LowGrade = "7"
+	hourly.MatchedObjectDescriptor.PositionRemuneration = []usajobs.PositionRemuneration{{MinimumRange: "40.00", MaximumRange: "50.00", RateIntervalCode: "PH"}}
+	items := append(data.SearchResult.SearchResultItems, hourly)
+
+	stats := usajobs.SummarizeSearch(items, 0)
+
+	if stats.Postings != 4 || stats.Remote != 1 || stats.Telework != 2 || stats.RemotePercent() != 25 {
+		t.Errorf("expected 4 postings, 1 remote, and 2 telework, got %+v", stats)
+	}
+
+	if stats.MedianGSGrade != 12 {
+		t.Errorf("expected median GS grade 12, got %v", stats.MedianGSGrade)
+	}
+
+	if stats.LowestSalary != 83480 || stats.MedianMinSalary != 91248 || stats.MedianMaxSalary != 135021.5 || stats.HighestSalary != 183500 {
+		t.Errorf("unexpected salaries %v %v %v %v", stats.LowestSalary, stats.MedianMinSalary, stats.MedianMaxSalary, stats.HighestSalary)
+	}
+
+	tests := []struct {
+		dimension usajobs.StatsDimension
+		want      []usajobs.StatsBucket
+	}{
+		{usajobs.StatsDepartment, []usajobs.StatsBucket{{Key: "Department of the Army", Count: 2, Percent: 50}, {Key: "Department of Homeland Security", Count: 1, Percent: 25}, {Key: "Department of Veterans Affairs", Count: 1, Percent: 25}}},
+		{usajobs.StatsGrade, []usajobs.StatsBucket{{Key: "GS-7", Count: 1, Percent: 25}, {Key: "GS-12", Count: 1, Percent: 25}, {Key: "GS-13", Count: 1, Percent: 25}, {Key: "VN-2", Count: 1, Percent: 25}}},
+		{usajobs.StatsState, []usajobs.StatsBucket{{Key: "Texas", Count: 2, Percent: 50}, {Key: "District of Columbia", Count: 1, Percent: 25}, {Key: "Oregon", Count: 1, Percent: 25}, {Key: "Unknown", Count: 1, Percent: 25}}},
+		{usajobs.StatsSalary, []usajobs.StatsBucket{{Key: "$80,000-$99,999", Count: 3, Percent: 75}, {Key: "$100,000-$119,999", Count: 1, Percent: 25}}},
+		{usajobs.StatsSchedule, []usajobs.StatsBucket{{Key: "Full-time", Count: 2, Percent: 50}, {Key: "Part-time", Count: 1, Percent: 25}, {Key: "Unknown", Count: 1, Percent: 25}}},
+	}
+
+	for _, tt := range tests {
+		got := stats.Groups[tt.dimension]
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: expected %+v, got %+v", tt.dimension, tt.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: expected %+v, got %+v", tt.dimension, tt.want, got)
+				break
+			}
+		}
+	}
+
+	series := stats.Groups[usajobs.StatsSeries]
+	if len(series) != 4 || series[0].Label() != "0610 "+series[0].Name || series[0].Name == "" {
+		t.Errorf("expected series with names, got %+v", series)
+	}
+
+	stats = usajobs.SummarizeSearch(items, 50000)
+	if len(stats.Groups[usajobs.StatsSalary]) != 2 || stats.Groups[usajobs.StatsSalary][0].Key != "$50,000-$99,999" {
+		t.Errorf("expected $50,000 bands, got %+v", stats.Groups[usajobs.StatsSalary])
+	}
+}
+
+func TestParseStatsDimension(t *testing.T) {
+	d, err := usajobs.ParseStatsDimension("State")
+	if err != nil || d != usajobs.StatsState {
+		t.Errorf("expected state, got %q %v", d, err)
+	}
+
+	_, err = usajobs.ParseStatsDimension("planet")
+	if err == nil {
+		t.Error("expected error for unknown dimension")
+	}
+}
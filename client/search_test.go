@@ -76,3 +76,83 @@ func TestSearch(t *testing.T) {
 	}
 
 }
+
+func TestSearchOptionsQuery(t *testing.T) {
+	var query url.Values
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"LanguageCode": "EN"}`))
+	}))
+	defer mockServer.Close()
+
+	c, err := usajobs.NewClient("test", "test")
+	if err != nil {
+		t.Fatalf("could not create new usajobs client: %v", err)
+	}
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+
+	c.BaseURL = u
+
+	opt := usajobs.SearchOptions{
+		SalaryBucket:        []usajobs.SalaryBucket{"5", "6"},
+		GradeBucket:         []usajobs.GradeBucket{"13"},
+		MissionCriticalTags: []usajobs.MissionCriticalTag{"01", "02"},
+		SupervisoryStatus:   usajobs.SupervisoryStatusNo,
+		Fields:              usajobs.SearchFieldsFull,
+		WhoMayApply:         usajobs.WhoMayApplyStatus,
+		PostingChannel:      []usajobs.PostingChannel{"USAJOBS"},
+	}
+
+	_, _, err = c.Search.WithOptions(&opt)
+	if err != nil {
+		t.Fatalf("failed to execute search request: %v", err.Error())
+	}
+
+	expected := map[string]string{
+		"SalaryBucket":        "5;6",
+		"GradeBucket":         "13",
+		"MissionCriticalTags": "01;02",
+		"SupervisoryStatus":   "N",
+		"Fields":              "Full",
+		"WhoMayApply":         "Status",
+		"PostingChannel":      "USAJOBS",
+	}
+
+	for k, v := range expected {
+		if query.Get(k) != v {
+			t.Errorf("expected %s=%s, got %s", k, v, query.Get(k))
+		}
+	}
+}
+
+func TestParseSearchValues(t *testing.T) {
+	f, err := usajobs.ParseSearchFields("FULL")
+	if err != nil || f != usajobs.SearchFieldsFull {
+		t.Errorf("expected %s, got %s (%v)", usajobs.SearchFieldsFull, f, err)
+	}
+
+	s, err := usajobs.ParseSupervisoryStatus("no")
+	if err != nil || s != usajobs.SupervisoryStatusNo {
+		t.Errorf("expected %s, got %s (%v)", usajobs.SupervisoryStatusNo, s, err)
+	}
+
+	w, err := usajobs.ParseWhoMayApply("all")
+	if err != nil || w != usajobs.WhoMayApplyAll {
+		t.Errorf("expected %s, got %s (%v)", usajobs.WhoMayApplyAll, w, err)
+	}
+
+	if _, err := usajobs.ParseSupervisoryStatus("maybe"); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if _, err := usajobs.ParseWhoMayApply("anyone"); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
@@ -0,0 +1,273 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package usajobs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// CacheHeader is set on responses passed through a ResponseCache to HIT when
+// the response was cached and MISS when it was requested from usajobs.
+const CacheHeader = "X-Cache"
+
+// ResponseCache is an http.RoundTripper that caches successful GET responses
+// in memory, so clients sharing it share one cache. Concurrent requests for
+// the same uncached url wait for a single request to usajobs. Install it as
+// the Transport of Client.Client:
+//
+//	c.Client.Transport = usajobs.NewResponseCache(c.Client.Transport, 15*time.Minute, 1000)
+type ResponseCache struct {
+	transport  http.RoundTripper
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*cachedResponse
+	calls   map[string]*cacheCall
+	hits    uint64
+	misses  uint64
+}
+
+// CacheStats counts the requests a ResponseCache answered.
+type CacheStats struct {
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+	Entries int    `json:"entries"`
+}
+
+type cachedResponse struct {
+	status  int
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+// cacheCall is a request in flight that other requests for the same url
+// wait for. The waiting requests get its response, cached or not, or its
+// error, so a failing url is requested once rather than once per request.
+type cacheCall struct {
+	done   chan struct{}
+	resp   *cachedResponse
+	cached bool
+	err    error
+}
+
+// NewResponseCache returns a ResponseCache that keeps responses requested
+// through transport for ttl, keeping at most maxEntries responses. A nil
+// transport uses http.DefaultTransport, and a maxEntries of 0 is no limit.
+func NewResponseCache(transport http.RoundTripper, ttl time.Duration, maxEntries int) *ResponseCache {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &ResponseCache{
+		transport:  transport,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    map[string]*cachedResponse{},
+		calls:      map[string]*cacheCall{},
+	}
+}
+
+// RoundTrip returns the cached response to a GET request when there is one,
+// and otherwise requests it. Other requests are never cached.
+func (c *ResponseCache) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || c.ttl <= 0 {
+		return c.transport.RoundTrip(req)
+	}
+
+	// responses depend on the api token they were requested with
+	key := req.Header.Get("Authorization-Key") + " " + req.URL.String()
+
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok && time.Now().Before(e.expires) {
+		c.hits++
+		c.mu.Unlock()
+		return e.response(req, "HIT"), nil
+	}
+
+	if call, ok := c.calls[key]; ok {
+		c.mu.Unlock()
+		select {
+		case <-call.done:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+
+		switch {
+		case call.cached:
+			c.mu.Lock()
+			c.hits++
+			c.mu.Unlock()
+			return call.resp.response(req, "HIT"), nil
+		case call.resp != nil:
+			c.mu.Lock()
+			c.misses++
+			c.mu.Unlock()
+			return call.resp.response(req, "MISS"), nil
+		case errors.Is(call.err, context.Canceled) || errors.Is(call.err, context.DeadlineExceeded):
+			// the request waited for was cancelled by its caller, which
+			// says nothing about this request
+			return c.request(req)
+		}
+		return nil, call.err
+	}
+
+	call := &cacheCall{done: make(chan struct{})}
+	c.calls[key] = call
+	c.misses++
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.calls, key)
+		if call.cached {
+			c.store(key, call.resp)
+		}
+		c.mu.Unlock()
+		close(call.done)
+	}()
+
+	resp, err := c.transport.RoundTrip(req)
+	if err != nil {
+		call.err = err
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		call.err = err
+		return nil, err
+	}
+
+	// failed responses (ex., 429 or 503) are shared with the waiting
+	// requests but not cached
+	call.resp = &cachedResponse{
+		status:  resp.StatusCode,
+		header:  resp.Header.Clone(),
+		body:    body,
+		expires: time.Now().Add(c.ttl),
+	}
+	call.cached = resp.StatusCode == http.StatusOK
+	return call.resp.response(req, "MISS"), nil
+}
+
+// request requests a response without caching it.
+func (c *ResponseCache) request(req *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	c.misses++
+	c.mu.Unlock()
+
+	resp, err := c.transport.RoundTrip(req)
+	if resp != nil {
+		resp.Header.Set(CacheHeader, "MISS")
+	}
+	return resp, err
+}
+
+// store caches a response, first dropping expired responses and then the
+// oldest responses when the cache is full. c.mu must be held.
+func (c *ResponseCache) store(key string, resp *cachedResponse) {
+	if c.maxEntries > 0 && len(c.entries) >= c.maxEntries {
+		now := time.Now()
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+
+		for len(c.entries) >= c.maxEntries {
+			var oldest string
+			for k, e := range c.entries {
+				if oldest == "" || e.expires.Before(c.entries[oldest].expires) {
+					oldest = k
+				}
+			}
+			delete(c.entries, oldest)
+		}
+	}
+	c.entries[key] = resp
+}
+
+// Stats returns the number of requests answered from the cache and from
+// usajobs, and the number of responses cached.
+func (c *ResponseCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{Hits: c.hits, Misses: c.misses, Entries: len(c.entries)}
+}
+
+// Clear drops every cached response.
+func (c *ResponseCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = map[string]*cachedResponse{}
+}
+
+func (e *cachedResponse) response(req *http.Request, cache string) *http.Response {
+	header := e.header.Clone()
+	header.Set(CacheHeader, cache)
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.status, http.StatusText(e.status)),
+		StatusCode:    e.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
+
+// RateLimiter is an http.RoundTripper that limits the rate of requests to
+// usajobs, waiting until a request is allowed or its context is done. Put it
+// behind a ResponseCache so cached responses are not limited:
+//
+//	limited := usajobs.NewRateLimiter(c.Client.Transport, 2, 5)
+//	c.Client.Transport = usajobs.NewResponseCache(limited, 15*time.Minute, 1000)
+type RateLimiter struct {
+	transport http.RoundTripper
+	limiter   *rate.Limiter
+}
+
+// NewRateLimiter returns a RateLimiter allowing perSecond requests through
+// transport a second on average, and bursts of up to burst requests. A nil
+// transport uses http.DefaultTransport.
+func NewRateLimiter(transport http.RoundTripper, perSecond float64, burst int) *RateLimiter {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &RateLimiter{transport: transport, limiter: rate.NewLimiter(rate.Limit(perSecond), max(burst, 1))}
+}
+
+// RoundTrip waits until the request is allowed and then sends it.
+func (l *RateLimiter) RoundTrip(req *http.Request) (*http.Response, error) {
+	err := l.limiter.Wait(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	return l.transport.RoundTrip(req)
+}
@@ -0,0 +1,252 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package usajobs_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+)
+
+func TestResponseCache(t *testing.T) {
+	data, err := os.ReadFile(searchTestDataPath)
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	var requests atomic.Int32
+	release := make(chan struct{})
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Query().Get("Keyword") == "fail" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	}))
+	defer mockServer.Close()
+
+	c, err := usajobs.NewClient("test", "test")
+	if err != nil {
+		t.Fatalf("could not create new usajobs client: %v", err)
+	}
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+	c.BaseURL = u
+
+	cache := usajobs.NewResponseCache(c.Client.Transport, time.Minute, 10)
+	c.Client.Transport = cache
+
+	// concurrent searches for the same url share one request
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r, data, err := c.Search.WithOptions(&usajobs.SearchOptions{Keyword: "nurse"})
+			if err == nil && (r.StatusCode != http.StatusOK || len(data.SearchResult.SearchResultItems) != 3) {
+				err = errors.New("unexpected response: " + r.Status)
+			}
+			errs <- err
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("failed to search: %v", err)
+		}
+	}
+
+	r, _, err := c.Search.WithOptions(&usajobs.SearchOptions{Keyword: "nurse"})
+	if err != nil {
+		t.Fatalf("failed to search: %v", err)
+	}
+	if r.Header.Get(usajobs.CacheHeader) != "HIT" {
+		t.Errorf("expected a cached response, got %s=%q", usajobs.CacheHeader, r.Header.Get(usajobs.CacheHeader))
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("expected 1 request to usajobs, got %d", n)
+	}
+
+	// failed responses are not cached
+	for i := 0; i < 2; i++ {
+		r, _, _ = c.Search.WithOptions(&usajobs.SearchOptions{Keyword: "fail"})
+		if r == nil || r.StatusCode != http.StatusServiceUnavailable || r.Header.Get(usajobs.CacheHeader) != "MISS" {
+			t.Fatalf("expected an uncached failed response, got %+v", r)
+		}
+	}
+	if n := requests.Load(); n != 3 {
+		t.Errorf("expected 3 requests to usajobs, got %d", n)
+	}
+
+	stats := cache.Stats()
+	if stats != (usajobs.CacheStats{Hits: 5, Misses: 3, Entries: 1}) {
+		t.Errorf("unexpected cache stats: %+v", stats)
+	}
+
+	cache.Clear()
+	_, _, err = c.Search.WithOptions(&usajobs.SearchOptions{Keyword: "nurse"})
+	if err != nil {
+		t.Fatalf("failed to search: %v", err)
+	}
+	if n := requests.Load(); n != 4 {
+		t.Errorf("expected a request to usajobs after clearing the cache, got %d requests", n)
+	}
+}
+
+func TestResponseCacheSharesFailures(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte("{}"))
+	}))
+	defer mockServer.Close()
+
+	c, err := usajobs.NewClient("test", "test")
+	if err != nil {
+		t.Fatalf("could not create new usajobs client: %v", err)
+	}
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+	c.BaseURL = u
+	c.Client.Transport = usajobs.NewResponseCache(c.Client.Transport, time.Minute, 10)
+
+	// requests waiting on a throttled request get its response instead of
+	// each requesting usajobs again
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r, _, err := c.Search.WithOptions(&usajobs.SearchOptions{Keyword: "nurse"})
+			if err == nil && r.Status != "429 Too Many Requests" {
+				err = errors.New("unexpected response: " + r.Status)
+			}
+			errs <- err
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("expected the throttled response: %v", err)
+		}
+	}
+
+	if n := requests.Load(); n != 1 {
+		t.Errorf("expected 1 request to usajobs, got %d", n)
+	}
+
+	// the failed response is not cached
+	_, _, err = c.Search.WithOptions(&usajobs.SearchOptions{Keyword: "nurse"})
+	if err != nil {
+		t.Fatalf("failed to search: %v", err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("expected the failed response to be requested again, got %d requests", n)
+	}
+}
+
+func TestResponseCacheMaxEntries(t *testing.T) {
+	var requests atomic.Int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`{}`))
+	}))
+	defer mockServer.Close()
+
+	cache := usajobs.NewResponseCache(nil, time.Minute, 2)
+	client := http.Client{Transport: cache}
+	get := func(path string) {
+		t.Helper()
+		r, err := client.Get(mockServer.URL + path)
+		if err != nil {
+			t.Fatalf("failed to get %s: %v", path, err)
+		}
+		r.Body.Close()
+	}
+
+	for _, path := range []string{"/a", "/b", "/c", "/c", "/a"} {
+		get(path)
+		time.Sleep(time.Millisecond)
+	}
+	if n := requests.Load(); n != 4 {
+		t.Errorf("expected the oldest response to be dropped and 4 requests, got %d", n)
+	}
+	if n := cache.Stats().Entries; n != 2 {
+		t.Errorf("expected 2 cached responses, got %d", n)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer mockServer.Close()
+
+	client := http.Client{Transport: usajobs.NewRateLimiter(nil, 20, 2)}
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		r, err := client.Get(mockServer.URL)
+		if err != nil {
+			t.Fatalf("failed to get: %v", err)
+		}
+		r.Body.Close()
+	}
+
+	// a burst of 2, then a request every 50ms
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("expected requests to be limited, took %v", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, mockServer.URL, nil)
+	_, err := client.Do(req)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the canceled context error, got %v", err)
+	}
+}
@@ -16,6 +16,7 @@ limitations under the License.
 package usajobs
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -56,6 +57,8 @@ type Client struct {
 	// services used for communicating with different aspects of the
 	// usajobs api.
 	Search                        *SearchService
+	HistoricJOA                   *HistoricJOAService
+	AnnouncementText              *AnnouncementTextService
 	Agency                        *AgencySubelementsService
 	AcademicHonors                *AcademicHonorsService
 	AcademicLevels                *AcademicLevelsService
@@ -121,6 +124,8 @@ func NewClient(userAgent, apiToken string) (*Client, error) {
 	}
 
 	c.Search = NewSearchService(&c)
+	c.HistoricJOA = NewHistoricJOAService(&c)
+	c.AnnouncementText = NewAnnouncementTextService(&c)
 	c.Agency = NewAgencySubelementsService(&c)
 	c.AcademicHonors = NewAcademicHonorsService(&c)
 	c.AcademicLevels = NewAcademicLevelsService(&c)
@@ -169,10 +174,16 @@ func NewClient(userAgent, apiToken string) (*Client, error) {
 // the baseurl. New Request also sets the required host headers for interacting
 // with the usajobs api. Returns the created request or an error.
 func (c *Client) NewRequest(method, urlStr string) (*http.Request, error) {
+	return c.NewRequestWithContext(context.Background(), method, urlStr)
+}
+
+// NewRequestWithContext is NewRequest with a context that controls the
+// lifetime of the request.
+func (c *Client) NewRequestWithContext(ctx context.Context, method, urlStr string) (*http.Request, error) {
 
 	combined := c.BaseURL.String() + urlStr
 
-	req, err := http.NewRequest(method, combined, nil)
+	req, err := http.NewRequestWithContext(ctx, method, combined, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -185,6 +196,12 @@ func (c *Client) NewRequest(method, urlStr string) (*http.Request, error) {
 }
 
 func (c *Client) NewResponse(endpoint string, opt interface{}, resp interface{}) (*http.Response, interface{}, error) {
+	return c.NewResponseWithContext(context.Background(), endpoint, opt, resp)
+}
+
+// NewResponseWithContext is NewResponse with a context that controls the
+// lifetime of the request.
+func (c *Client) NewResponseWithContext(ctx context.Context, endpoint string, opt interface{}, resp interface{}) (*http.Response, interface{}, error) {
 
 	requestURL := endpoint
 	if opt != nil {
@@ -195,7 +212,7 @@ func (c *Client) NewResponse(endpoint string, opt interface{}, resp interface{})
 		requestURL = fmt.Sprintf("%s?%s", endpoint, qs.Encode())
 	}
 
-	req, err := c.NewRequest("GET", requestURL)
+	req, err := c.NewRequestWithContext(ctx, "GET", requestURL)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -0,0 +1,9 @@
+#!/bin/bash
+
+ARCHIVE=$(mktemp -d)/archive.db
+
+./dist/go-usajobs_linux_386/usajobs archive add --archive=$ARCHIVE --token=$TOKEN --user-agent=$EMAIL --job-catagory=2210 --max-results=100
+./dist/go-usajobs_linux_386/usajobs archive query --archive=$ARCHIVE --department=army
+./dist/go-usajobs_linux_386/usajobs archive export --archive=$ARCHIVE --history
+./dist/go-usajobs_linux_386/usajobs archive search --archive=$ARCHIVE "software AND NOT (intern OR student)"
+./dist/go-usajobs_linux_386/usajobs history --archive=$ARCHIVE $(./dist/go-usajobs_linux_386/usajobs archive query --archive=$ARCHIVE --limit=1 --format="{{.MatchedObjectID}}")
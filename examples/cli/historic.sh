@@ -0,0 +1,3 @@
+#!/bin/bash
+
+./dist/go-usajobs_linux_386/usajobs historic --series=2210 --open-from=2023-01-01 --open-to=2023-01-07
@@ -0,0 +1,3 @@
+#!/bin/bash
+
+./dist/go-usajobs_linux_386/usajobs list countrysubdivisions --parent=US --limit=10
@@ -0,0 +1,3 @@
+#!/bin/bash
+
+./dist/go-usajobs_linux_386/usajobs search --token=$TOKEN --user-agent=$EMAIL --job-catagory=2210 --display=csv --columns=POSITION_ID,JOB_TITLE,LOCATIONS,MIN_SALARY,MAX_SALARY,HIRING_PATHS,CLOSE_DATE,URL
@@ -0,0 +1,4 @@
+#!/bin/bash
+
+./dist/go-usajobs_linux_386/usajobs search --token=$TOKEN --user-agent=$EMAIL --job-catagory=2210 \
+    --format='{{.PositionTitle | truncate 40}} | {{salary .PositionRemuneration}} | closes {{date "Jan 2" .ApplicationCloseDate}}'
@@ -0,0 +1,3 @@
+#!/bin/bash
+
+./dist/go-usajobs_linux_386/usajobs search --token=$TOKEN --user-agent=$EMAIL --clearance="Top Secret" --hiring-path=veterans --job-category="Information Technology Management"
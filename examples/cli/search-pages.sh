@@ -0,0 +1,3 @@
+#!/bin/bash
+
+./dist/go-usajobs_linux_386/usajobs search --token=$TOKEN --user-agent=$EMAIL --job-catagory=2210 --num-results=100 --max-results=250 --display=ndjson
@@ -0,0 +1,10 @@
+#!/bin/bash
+
+./dist/go-usajobs_linux_386/usajobs serve --token=$TOKEN --user-agent=$EMAIL --addr=localhost:8787 &
+PID=$!
+trap "kill $PID" EXIT
+sleep 1
+
+curl -sf 'localhost:8787/v1/postings?JobCategoryCode=2210&ResultsPerPage=5&columns=job_title,department,url'
+curl -sf 'localhost:8787/v1/codelists/securityclearances?contains=secret'
+curl -sf 'localhost:8787/healthz'
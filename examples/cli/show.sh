@@ -0,0 +1,3 @@
+#!/bin/bash
+
+./dist/go-usajobs_linux_386/usajobs show $(./dist/go-usajobs_linux_386/usajobs search --job-catagory=2210 --num-results=25 --format="{{.MatchedObjectID}}" | head -n 1) --no-pager
@@ -0,0 +1,4 @@
+#!/bin/bash
+
+./dist/go-usajobs_linux_386/usajobs stats --token=$TOKEN --user-agent=$EMAIL --job-catagory=2210 --max-results=1000
+./dist/go-usajobs_linux_386/usajobs stats --token=$TOKEN --user-agent=$EMAIL --job-catagory=2210 --max-results=1000 --by=salary --salary-band=10000 --display=csv
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+)
+
+func main() {
+	// the announcement text endpoint does not require an api token
+	c, err := usajobs.NewClient("not", "required")
+	if err != nil {
+		panic(err.Error())
+	}
+
+	// PositionIDs returned by /search are announcement numbers
+	text, err := c.AnnouncementText.ByPositionIDs([]string{"ASF-23-0007", "ICE-23-001-MP"}, 2)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	for id, announcements := range text {
+		for _, a := range announcements {
+			fmt.Printf("## %s (%d)\n\n", id, a.USAJOBSControlNumber)
+			for _, s := range a.Sections() {
+				fmt.Printf("### %s\n\n%s\n\n", s.Title, s.Content)
+			}
+		}
+	}
+}
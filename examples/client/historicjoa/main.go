@@ -0,0 +1,35 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+)
+
+func main() {
+	// the historicjoa endpoint does not require an api token
+	c, err := usajobs.NewClient("not", "required")
+	if err != nil {
+		panic(err.Error())
+	}
+
+	// IT Specialist announcements opened during the first week of 2023
+	opt := usajobs.HistoricJOAOptions{
+		PositionSeries:        []string{"2210"},
+		StartPositionOpenDate: "2023-01-01",
+		EndPositionOpenDate:   "2023-01-07",
+	}
+
+	r, err := c.HistoricJOA.All(&opt)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	prettyJSON, err := json.MarshalIndent(r, "", "    ")
+	if err != nil {
+		panic(err.Error())
+	}
+
+	fmt.Println(string(prettyJSON))
+}
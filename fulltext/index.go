@@ -0,0 +1,251 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package fulltext is an in-memory inverted index over the text of postings:
+// their title, summary, duties, qualifications and requirements. Queries
+// combine words, "quoted phrases" and prefix* words with AND, OR, NOT and
+// parentheses, and matching postings are ranked with BM25, counting words in
+// the title more than words elsewhere.
+//
+// The index is not persisted. Callers build it from the postings they want
+// to search each time, as the archive search command does for every query.
+package fulltext
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+)
+
+// Field is a part of a posting that is indexed.
+type Field int
+
+const (
+	Title Field = iota
+	Summary
+	Duties
+	Qualifications
+	Requirements
+
+	numFields
+)
+
+var fieldNames = [numFields]string{"title", "summary", "duties", "qualifications", "requirements"}
+
+// String returns the name of the field.
+func (f Field) String() string {
+	if f < 0 || f >= numFields {
+		return fmt.Sprintf("Field(%d)", int(f))
+	}
+	return fieldNames[f]
+}
+
+// FieldWeights is how much more a word counts towards the rank of a posting
+// in each field than in its summary.
+var FieldWeights = [numFields]float64{
+	Title:          3,
+	Summary:        1,
+	Duties:         1,
+	Qualifications: 1,
+	Requirements:   0.5,
+}
+
+// BM25 parameters.
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+var htmlTag = regexp.MustCompile(`<[^>]+>`)
+
+// Hit is a posting matching a query.
+type Hit struct {
+	ID    string  `json:"id"`
+	Score float64 `json:"score"`
+}
+
+// Index is an inverted index over the text of postings. The zero value is not
+// usable; create one with New. An Index is not safe for concurrent use.
+type Index struct {
+	ids    []string
+	docs   map[string]int
+	length []float64
+	terms  map[string]map[int]*occurrences
+	// total is the sum of the weighted lengths of the documents.
+	total float64
+}
+
+// occurrences are the positions of a term in each field of a document.
+type occurrences [numFields][]int
+
+// New returns an empty index.
+func New() *Index {
+	return &Index{
+		docs:  map[string]int{},
+		terms: map[string]map[int]*occurrences{},
+	}
+}
+
+// Len returns the number of postings in the index.
+func (ix *Index) Len() int {
+	return len(ix.docs)
+}
+
+// Add indexes the text of a posting by its MatchedObjectID, replacing any
+// posting already indexed with the same id.
+func (ix *Index) Add(item usajobs.SearchResultItem) {
+	d := item.MatchedObjectDescriptor
+	details := d.UserArea.Details
+
+	var requirements []string
+	for _, r := range details.KeyRequirements {
+		requirements = append(requirements, fmt.Sprint(r))
+	}
+	requirements = append(requirements, details.Requirements)
+
+	ix.AddText(item.MatchedObjectID, map[Field]string{
+		Title:          d.PositionTitle,
+		Summary:        details.JobSummary,
+		Duties:         strings.Join(details.MajorDuties, "\n"),
+		Qualifications: d.QualificationSummary,
+		Requirements:   strings.Join(requirements, "\n"),
+	})
+}
+
+// AddText indexes the text of each field of a document, replacing any
+// document already indexed with the same id.
+func (ix *Index) AddText(id string, fields map[Field]string) {
+	if old, ok := ix.docs[id]; ok {
+		ix.remove(old)
+	}
+
+	doc := len(ix.ids)
+	ix.ids = append(ix.ids, id)
+	ix.docs[id] = doc
+
+	var length float64
+	for f, text := range fields {
+		if f < 0 || f >= numFields {
+			continue
+		}
+
+		words := Tokenize(text)
+		for pos, w := range words {
+			docs := ix.terms[w]
+			if docs == nil {
+				docs = map[int]*occurrences{}
+				ix.terms[w] = docs
+			}
+			o := docs[doc]
+			if o == nil {
+				o = &occurrences{}
+				docs[doc] = o
+			}
+			o[f] = append(o[f], pos)
+		}
+		length += FieldWeights[f] * float64(len(words))
+	}
+	ix.length = append(ix.length, length)
+	ix.total += length
+}
+
+func (ix *Index) remove(doc int) {
+	for term, docs := range ix.terms {
+		delete(docs, doc)
+		if len(docs) == 0 {
+			delete(ix.terms, term)
+		}
+	}
+	ix.total -= ix.length[doc]
+	delete(ix.docs, ix.ids[doc])
+}
+
+// Search returns the postings matching a query, best match first. Postings
+// that match equally well are ordered by id.
+func (ix *Index) Search(query string) ([]Hit, error) {
+	q, err := Parse(query)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := q.match(ix)
+	terms := q.terms(ix)
+
+	hits := make([]Hit, 0, len(matched))
+	for doc := range matched {
+		hits = append(hits, Hit{ID: ix.ids[doc], Score: ix.score(doc, terms)})
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+	return hits, nil
+}
+
+// score ranks a document by the BM25 score of the terms it contains, with the
+// frequency of each term weighted by the field it is in.
+func (ix *Index) score(doc int, terms []string) float64 {
+	n := float64(len(ix.docs))
+	if n == 0 || ix.total == 0 {
+		return 0
+	}
+	avg := ix.total / n
+
+	var score float64
+	for _, t := range terms {
+		docs := ix.terms[t]
+		o := docs[doc]
+		if o == nil {
+			continue
+		}
+
+		var tf float64
+		for f, positions := range o {
+			tf += FieldWeights[f] * float64(len(positions))
+		}
+
+		df := float64(len(docs))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		score += idf * tf * (k1 + 1) / (tf + k1*(1-b+b*ix.length[doc]/avg))
+	}
+	return math.Round(score*1000) / 1000
+}
+
+// all returns every document in the index.
+func (ix *Index) all() docSet {
+	docs := make(docSet, len(ix.docs))
+	for _, doc := range ix.docs {
+		docs[doc] = true
+	}
+	return docs
+}
+
+// Tokenize splits text into the lower case words that are indexed, ignoring
+// html markup.
+func Tokenize(text string) []string {
+	text = html.UnescapeString(htmlTag.ReplaceAllString(text, " "))
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
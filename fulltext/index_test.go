@@ -0,0 +1,167 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package fulltext_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+	"github.com/JeffRDay/go-usajobs/fulltext"
+)
+
+func loadIndex(t *testing.T) *fulltext.Index {
+	t.Helper()
+
+	data, err := os.ReadFile("../testdata/search-testdata.json")
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	var resp usajobs.SearchResponse
+	err = json.Unmarshal(data, &resp)
+	if err != nil {
+		t.Fatalf("could not unmarshal test data: %v", err)
+	}
+
+	ix := fulltext.New()
+	for _, item := range resp.SearchResult.SearchResultItems {
+		ix.Add(item)
+	}
+	return ix
+}
+
+func hitIDs(hits []fulltext.Hit) []string {
+	ids := []string{}
+	for _, h := range hits {
+		ids = append(ids, h.ID)
+	}
+	return ids
+}
+
+func TestSearch(t *testing.T) {
+	ix := loadIndex(t)
+	if ix.Len() != 3 {
+		t.Fatalf("expected 3 indexed postings, got %d", ix.Len())
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"software", []string{"800000002"}},
+		{"SOFTWARE engineer", []string{"800000002"}},
+		{"nurse OR infosec", []string{"800000003", "800000001"}},
+		{"stakeholders AND NOT nurse", []string{"800000002", "800000001"}},
+		{"stakeholders -nurse -software", []string{"800000001"}},
+		{"NOT (nurse OR software)", []string{"800000001"}},
+		{`"software factory"`, []string{"800000002"}},
+		{`"factory software"`, []string{}},
+		{`"security clearance" engin*`, []string{"800000002"}},
+		{"infosec OR software AND nurse", []string{"800000001"}},
+		{"u.s.", []string{"800000003", "800000002", "800000001"}},
+		{"kubernetes", []string{}},
+	}
+	for _, tt := range tests {
+		hits, err := ix.Search(tt.query)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.query, err)
+			continue
+		}
+		if got := hitIDs(hits); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSearchRanking(t *testing.T) {
+	ix := fulltext.New()
+	ix.AddText("summary", map[fulltext.Field]string{
+		fulltext.Title:   "Program Analyst",
+		fulltext.Summary: "Supports the kubernetes platform team.",
+	})
+	ix.AddText("title", map[fulltext.Field]string{
+		fulltext.Title:   "Kubernetes Engineer",
+		fulltext.Summary: "Supports the platform team.",
+	})
+	ix.AddText("twice", map[fulltext.Field]string{
+		fulltext.Title:   "Platform Engineer",
+		fulltext.Summary: "Runs <b>Kubernetes</b> clusters and kubernetes operators.",
+	})
+	ix.AddText("none", map[fulltext.Field]string{
+		fulltext.Title: "Registered Nurse",
+	})
+
+	hits, err := ix.Search("kubernetes")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := hitIDs(hits), []string{"title", "twice", "summary"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	for i := 1; i < len(hits); i++ {
+		if hits[i].Score >= hits[i-1].Score {
+			t.Errorf("expected decreasing scores, got %+v", hits)
+		}
+	}
+
+	// re-adding a document replaces it
+	ix.AddText("title", map[fulltext.Field]string{fulltext.Title: "Program Manager"})
+	hits, err = ix.Search("kubernetes")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := hitIDs(hits), []string{"twice", "summary"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after replacing: got %v, want %v", got, want)
+	}
+	if ix.Len() != 4 {
+		t.Errorf("expected 4 indexed documents, got %d", ix.Len())
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"kubernetes AND NOT contractor", "(kubernetes AND NOT contractor)"},
+		{"a b OR c", "((a AND b) OR c)"},
+		{"a (b OR c)", "(a AND (b OR c))"},
+		{`-"full time" dev*`, `(NOT "full time" AND dev*)`},
+		{"full-time", `"full time"`},
+		{"and or not", "((and AND or) AND not)"},
+	}
+	for _, tt := range tests {
+		q, err := fulltext.Parse(tt.query)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.query, err)
+			continue
+		}
+		if q.String() != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.query, q, tt.want)
+		}
+	}
+
+	for _, query := range []string{"", "a AND", "(a OR b", "a)", `"unterminated`, "OR a", "!!!", `""`} {
+		_, err := fulltext.Parse(query)
+		if !errors.Is(err, fulltext.ErrInvalidQuery) {
+			t.Errorf("%q: expected ErrInvalidQuery, got %v", query, err)
+		}
+	}
+}
@@ -0,0 +1,425 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package fulltext
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidQuery is returned when a query cannot be parsed.
+var ErrInvalidQuery = errors.New("invalid query")
+
+// Query is a parsed search query.
+type Query struct {
+	root node
+}
+
+// String returns the query with its operators and grouping made explicit.
+func (q Query) String() string {
+	return q.root.String()
+}
+
+// Parse parses a query. Words must all appear in a posting unless joined by
+// OR, NOT or a leading - excludes postings with a word, a "quoted phrase"
+// matches words next to each other in the same field, a word ending in *
+// matches every word starting with it, and parentheses group. AND, OR and NOT
+// are only operators in upper case, and AND binds tighter than OR.
+func Parse(query string) (Query, error) {
+	tokens, err := lex(query)
+	if err != nil {
+		return Query{}, err
+	}
+	if len(tokens) == 0 {
+		return Query{}, fmt.Errorf("%w: empty query", ErrInvalidQuery)
+	}
+
+	p := parser{tokens: tokens}
+	root, err := p.or()
+	if err != nil {
+		return Query{}, err
+	}
+	if p.pos < len(p.tokens) {
+		return Query{}, fmt.Errorf("%w: unexpected %s", ErrInvalidQuery, p.tokens[p.pos])
+	}
+	return Query{root: root}, nil
+}
+
+func (q Query) match(ix *Index) docSet {
+	return q.root.match(ix)
+}
+
+// terms returns the indexed terms a matching document is ranked by, which
+// excludes the terms of negated parts of the query.
+func (q Query) terms(ix *Index) []string {
+	seen := map[string]bool{}
+	var terms []string
+	for _, t := range q.root.terms(ix) {
+		if !seen[t] {
+			seen[t] = true
+			terms = append(terms, t)
+		}
+	}
+	return terms
+}
+
+type tokenKind int
+
+const (
+	wordToken tokenKind = iota
+	phraseToken
+	andToken
+	orToken
+	notToken
+	openToken
+	closeToken
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case openToken:
+		return `"("`
+	case closeToken:
+		return `")"`
+	case phraseToken:
+		return fmt.Sprintf("%q", `"`+t.value+`"`)
+	}
+	return fmt.Sprintf("%q", t.value)
+}
+
+func lex(query string) ([]token, error) {
+	var tokens []token
+	r := []rune(query)
+	for i := 0; i < len(r); {
+		switch c := r[i]; {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: openToken})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: closeToken})
+			i++
+		case c == '"':
+			end := strings.IndexRune(string(r[i+1:]), '"')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated phrase", ErrInvalidQuery)
+			}
+			phrase := []rune(string(r[i+1:])[:end])
+			tokens = append(tokens, token{kind: phraseToken, value: string(phrase)})
+			i += len(phrase) + 2
+		case c == '-' && (i == 0 || unicode.IsSpace(r[i-1]) || r[i-1] == '('):
+			tokens = append(tokens, token{kind: notToken, value: "-"})
+			i++
+		default:
+			start := i
+			for i < len(r) && !unicode.IsSpace(r[i]) && r[i] != '(' && r[i] != ')' && r[i] != '"' {
+				i++
+			}
+
+			word := string(r[start:i])
+			switch word {
+			case "AND":
+				tokens = append(tokens, token{kind: andToken, value: word})
+			case "OR":
+				tokens = append(tokens, token{kind: orToken, value: word})
+			case "NOT":
+				tokens = append(tokens, token{kind: notToken, value: word})
+			default:
+				tokens = append(tokens, token{kind: wordToken, value: word})
+			}
+		}
+	}
+	return tokens, nil
+}
+
+// parser is a recursive descent parser of the grammar
+//
+//	or    = and { "OR" and }
+//	and   = unary { [ "AND" ] unary }
+//	unary = ( "NOT" | "-" ) unary | "(" or ")" | phrase | word
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *parser) or() (node, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t, ok := p.peek()
+		if !ok || t.kind != orToken {
+			return left, nil
+		}
+		p.pos++
+
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+}
+
+func (p *parser) and() (node, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t, ok := p.peek()
+		if !ok || t.kind == orToken || t.kind == closeToken {
+			return left, nil
+		}
+		if t.kind == andToken {
+			p.pos++
+		}
+
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+}
+
+func (p *parser) unary() (node, error) {
+	t, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("%w: unexpected end of query", ErrInvalidQuery)
+	}
+	p.pos++
+
+	switch t.kind {
+	case notToken:
+		n, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{n}, nil
+	case openToken:
+		n, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		t, ok := p.peek()
+		if !ok || t.kind != closeToken {
+			return nil, fmt.Errorf("%w: missing \")\"", ErrInvalidQuery)
+		}
+		p.pos++
+		return n, nil
+	case phraseToken:
+		words := Tokenize(t.value)
+		if len(words) == 0 {
+			return nil, fmt.Errorf("%w: no words to search for in %s", ErrInvalidQuery, t)
+		}
+		return phraseNode{words}, nil
+	case wordToken:
+		prefix := strings.HasSuffix(t.value, "*")
+		words := Tokenize(strings.TrimSuffix(t.value, "*"))
+		switch {
+		case len(words) == 0:
+			return nil, fmt.Errorf("%w: no words to search for in %s", ErrInvalidQuery, t)
+		case len(words) == 1:
+			return termNode{word: words[0], prefix: prefix}, nil
+		}
+		// words such as full-time are indexed as a phrase
+		return phraseNode{words}, nil
+	}
+	return nil, fmt.Errorf("%w: unexpected %s", ErrInvalidQuery, t)
+}
+
+// docSet is a set of documents.
+type docSet map[int]bool
+
+type node interface {
+	match(ix *Index) docSet
+	terms(ix *Index) []string
+	String() string
+}
+
+type termNode struct {
+	word   string
+	prefix bool
+}
+
+func (n termNode) match(ix *Index) docSet {
+	docs := docSet{}
+	for _, t := range n.terms(ix) {
+		for doc := range ix.terms[t] {
+			docs[doc] = true
+		}
+	}
+	return docs
+}
+
+func (n termNode) terms(ix *Index) []string {
+	if !n.prefix {
+		return []string{n.word}
+	}
+
+	var terms []string
+	for t := range ix.terms {
+		if strings.HasPrefix(t, n.word) {
+			terms = append(terms, t)
+		}
+	}
+	sort.Strings(terms)
+	return terms
+}
+
+func (n termNode) String() string {
+	if n.prefix {
+		return n.word + "*"
+	}
+	return n.word
+}
+
+type phraseNode struct {
+	words []string
+}
+
+func (n phraseNode) match(ix *Index) docSet {
+	docs := docSet{}
+	for doc, first := range ix.terms[n.words[0]] {
+		if n.matchDoc(ix, doc, first) {
+			docs[doc] = true
+		}
+	}
+	return docs
+}
+
+// matchDoc reports whether the words of the phrase follow each other in any
+// field of a document.
+func (n phraseNode) matchDoc(ix *Index, doc int, first *occurrences) bool {
+	rest := make([]*occurrences, 0, len(n.words)-1)
+	for _, w := range n.words[1:] {
+		o := ix.terms[w][doc]
+		if o == nil {
+			return false
+		}
+		rest = append(rest, o)
+	}
+
+	for f := range first {
+	positions:
+		for _, pos := range first[f] {
+			for i, o := range rest {
+				if !containsInt(o[f], pos+i+1) {
+					continue positions
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func (n phraseNode) terms(*Index) []string {
+	return n.words
+}
+
+func (n phraseNode) String() string {
+	return `"` + strings.Join(n.words, " ") + `"`
+}
+
+type notNode struct {
+	n node
+}
+
+func (n notNode) match(ix *Index) docSet {
+	docs := ix.all()
+	for doc := range n.n.match(ix) {
+		delete(docs, doc)
+	}
+	return docs
+}
+
+func (notNode) terms(*Index) []string {
+	return nil
+}
+
+func (n notNode) String() string {
+	return "NOT " + n.n.String()
+}
+
+type andNode struct {
+	left, right node
+}
+
+func (n andNode) match(ix *Index) docSet {
+	left := n.left.match(ix)
+	docs := docSet{}
+	for doc := range n.right.match(ix) {
+		if left[doc] {
+			docs[doc] = true
+		}
+	}
+	return docs
+}
+
+func (n andNode) terms(ix *Index) []string {
+	return append(n.left.terms(ix), n.right.terms(ix)...)
+}
+
+func (n andNode) String() string {
+	return "(" + n.left.String() + " AND " + n.right.String() + ")"
+}
+
+type orNode struct {
+	left, right node
+}
+
+func (n orNode) match(ix *Index) docSet {
+	docs := n.left.match(ix)
+	for doc := range n.right.match(ix) {
+		docs[doc] = true
+	}
+	return docs
+}
+
+func (n orNode) terms(ix *Index) []string {
+	return append(n.left.terms(ix), n.right.terms(ix)...)
+}
+
+func (n orNode) String() string {
+	return "(" + n.left.String() + " OR " + n.right.String() + ")"
+}
+
+// containsInt reports whether the sorted positions contain pos.
+func containsInt(positions []int, pos int) bool {
+	i := sort.SearchInts(positions, pos)
+	return i < len(positions) && positions[i] == pos
+}
@@ -0,0 +1,175 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package savedsearch
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+)
+
+// closeDateLayouts are the formats usajobs uses for ApplicationCloseDate.
+var closeDateLayouts = []string{"2006-01-02T15:04:05.9999999", "2006-01-02"}
+
+// Changes is the difference between two runs of a saved search.
+type Changes struct {
+	Search string `json:"search"`
+	// FirstRun is true when there was no previous run to compare against,
+	// in which case every posting is reported as new.
+	FirstRun bool `json:"firstRun"`
+	// New postings were not returned by the previous run.
+	New []Posting `json:"new"`
+	// Closed postings had their close date pass since the previous run.
+	Closed []Posting `json:"closed"`
+	// Removed postings were returned by the previous run but not this one
+	// and had not reached their close date (ex., cancelled announcements).
+	Removed []Posting `json:"removed"`
+	// Updated postings had their close date or salary change.
+	Updated []PostingUpdate `json:"updated"`
+}
+
+// PostingUpdate is a posting returned by both runs whose tracked fields
+// changed.
+type PostingUpdate struct {
+	Before Posting  `json:"before"`
+	After  Posting  `json:"after"`
+	Fields []string `json:"fields"`
+}
+
+// Empty reports whether nothing changed between the runs.
+func (c Changes) Empty() bool {
+	return len(c.New) == 0 && len(c.Closed) == 0 && len(c.Removed) == 0 && len(c.Updated) == 0
+}
+
+// Compare returns the changes from prev to curr. Postings in each list are
+// sorted by MatchedObjectID.
+func Compare(prev, curr Snapshot) Changes {
+	var c Changes
+
+	for id, p := range curr.Postings {
+		before, ok := prev.Postings[id]
+		if !ok {
+			c.New = append(c.New, p)
+			continue
+		}
+
+		if closedBetween(p, prev.RunAt, curr.RunAt) {
+			c.Closed = append(c.Closed, p)
+		}
+
+		var fields []string
+		if before.CloseDate != p.CloseDate {
+			fields = append(fields, "CloseDate")
+		}
+		if before.MinimumSalary != p.MinimumSalary {
+			fields = append(fields, "MinimumSalary")
+		}
+		if before.MaximumSalary != p.MaximumSalary {
+			fields = append(fields, "MaximumSalary")
+		}
+		if fields != nil {
+			c.Updated = append(c.Updated, PostingUpdate{Before: before, After: p, Fields: fields})
+		}
+	}
+
+	for id, p := range prev.Postings {
+		if _, ok := curr.Postings[id]; ok {
+			continue
+		}
+
+		if closedBy(p, curr.RunAt) {
+			if !closedBy(p, prev.RunAt) {
+				c.Closed = append(c.Closed, p)
+			}
+			continue
+		}
+		c.Removed = append(c.Removed, p)
+	}
+
+	sortPostings(c.New)
+	sortPostings(c.Closed)
+	sortPostings(c.Removed)
+	sort.Slice(c.Updated, func(i, j int) bool {
+		return c.Updated[i].After.MatchedObjectID < c.Updated[j].After.MatchedObjectID
+	})
+
+	return c
+}
+
+// runResultsPerPage is the page size a saved search is run with when its
+// options do not set one, the most usajobs returns.
+const runResultsPerPage = 500
+
+// Run executes a saved search and compares the results with its previous
+// run. Every page of results is fetched, so postings past the first page are
+// not reported as removed. The snapshot of the results is returned rather
+// than recorded, so callers can record it with Store.SaveRun once the changes
+// have been delivered and a failed notification is repeated on the next run.
+func Run(ctx context.Context, client *usajobs.Client, store *Store, search SavedSearch, now time.Time) (Changes, Snapshot, error) {
+	opt := search.Options
+	if opt.ResultsPerPage == 0 {
+		opt.ResultsPerPage = runResultsPerPage
+	}
+
+	var items []usajobs.SearchResultItem
+	err := client.Search.Pages(ctx, &opt, func(page int, r *usajobs.SearchResponse) error {
+		items = append(items, r.SearchResult.SearchResultItems...)
+		return nil
+	})
+	if err != nil {
+		return Changes{}, Snapshot{}, err
+	}
+
+	curr := NewSnapshot(now, items)
+
+	prev, err := store.LastRun(search.Name)
+	firstRun := errors.Is(err, ErrNotFound)
+	if err != nil && !firstRun {
+		return Changes{}, Snapshot{}, err
+	}
+
+	changes := Compare(prev, curr)
+	changes.Search = search.Name
+	changes.FirstRun = firstRun
+	return changes, curr, nil
+}
+
+// closedBetween reports whether the posting's close date passed after from
+// and on or before to.
+func closedBetween(p Posting, from, to time.Time) bool {
+	return closedBy(p, to) && !closedBy(p, from)
+}
+
+// closedBy reports whether the posting's close date is before t. Postings
+// without a readable close date are never considered closed.
+func closedBy(p Posting, t time.Time) bool {
+	for _, layout := range closeDateLayouts {
+		closeDate, err := time.Parse(layout, p.CloseDate)
+		if err == nil {
+			return closeDate.Before(t)
+		}
+	}
+	return false
+}
+
+func sortPostings(p []Posting) {
+	sort.Slice(p, func(i, j int) bool {
+		return p[i].MatchedObjectID < p[j].MatchedObjectID
+	})
+}
@@ -0,0 +1,240 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package savedsearch_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+	"github.com/JeffRDay/go-usajobs/savedsearch"
+)
+
+var searchTestDataPath = "../testdata/search-testdata.json"
+
+func TestCompare(t *testing.T) {
+	day1 := time.Date(2024, 7, 10, 12, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+
+	prev := savedsearch.Snapshot{RunAt: day1, Postings: map[string]savedsearch.Posting{
+		"unchanged": {MatchedObjectID: "unchanged", CloseDate: "2024-08-01T23:59:59.9970", MinimumSalary: "100"},
+		"extended":  {MatchedObjectID: "extended", CloseDate: "2024-07-20T23:59:59.9970", MinimumSalary: "100"},
+		"closing":   {MatchedObjectID: "closing", CloseDate: "2024-07-10T23:59:59.9970"},
+		"expired":   {MatchedObjectID: "expired", CloseDate: "2024-07-10T23:59:59.9970"},
+		"cancelled": {MatchedObjectID: "cancelled", CloseDate: "2024-08-01T23:59:59.9970"},
+	}}
+
+	curr := savedsearch.Snapshot{RunAt: day2, Postings: map[string]savedsearch.Posting{
+		"unchanged": {MatchedObjectID: "unchanged", CloseDate: "2024-08-01T23:59:59.9970", MinimumSalary: "100"},
+		"extended":  {MatchedObjectID: "extended", CloseDate: "2024-07-27T23:59:59.9970", MinimumSalary: "110"},
+		"closing":   {MatchedObjectID: "closing", CloseDate: "2024-07-10T23:59:59.9970"},
+		"new":       {MatchedObjectID: "new", CloseDate: "2024-08-01T23:59:59.9970"},
+	}}
+
+	c := savedsearch.Compare(prev, curr)
+
+	if len(c.New) != 1 || c.New[0].MatchedObjectID != "new" {
+		t.Errorf("expected new posting, got %+v", c.New)
+	}
+
+	if len(c.Closed) != 2 || c.Closed[0].MatchedObjectID != "closing" || c.Closed[1].MatchedObjectID != "expired" {
+		t.Errorf("expected closing and expired postings to be closed, got %+v", c.Closed)
+	}
+
+	if len(c.Removed) != 1 || c.Removed[0].MatchedObjectID != "cancelled" {
+		t.Errorf("expected cancelled posting to be removed, got %+v", c.Removed)
+	}
+
+	if len(c.Updated) != 1 || len(c.Updated[0].Fields) != 2 {
+		t.Fatalf("expected extended posting to be updated, got %+v", c.Updated)
+	}
+
+	if c.Updated[0].Fields[0] != "CloseDate" || c.Updated[0].Fields[1] != "MinimumSalary" {
+		t.Errorf("expected CloseDate and MinimumSalary changes, got %v", c.Updated[0].Fields)
+	}
+
+	if !savedsearch.Compare(curr, curr).Empty() {
+		t.Error("expected no changes comparing a snapshot with itself")
+	}
+}
+
+func TestRun(t *testing.T) {
+	data, err := os.ReadFile(searchTestDataPath)
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	var full usajobs.SearchResponse
+	err = json.Unmarshal(data, &full)
+	if err != nil {
+		t.Fatalf("could not decode test data: %v", err)
+	}
+
+	// the first run returns every posting, later runs drop the last one
+	runs := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Log(r.URL.String())
+		sr := full
+		if runs > 0 {
+			sr.SearchResult.SearchResultItems = full.SearchResult.SearchResultItems[:2]
+		}
+		runs++
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(sr)
+	}))
+	defer mockServer.Close()
+
+	c, err := usajobs.NewClient("test", "test")
+	if err != nil {
+		t.Fatalf("could not create new usajobs client: %v", err)
+	}
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+
+	c.BaseURL = u
+
+	store, err := savedsearch.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("could not create store: %v", err)
+	}
+
+	search := savedsearch.SavedSearch{Name: "all", Options: usajobs.SearchOptions{Keyword: "federal"}}
+	now := time.Date(2024, 7, 10, 0, 0, 0, 0, time.UTC)
+
+	changes, snapshot, err := savedsearch.Run(context.Background(), c, store, search, now)
+	if err != nil {
+		t.Fatalf("failed to run saved search: %v", err)
+	}
+
+	// the run is only recorded once the caller saves it
+	_, err = store.LastRun(search.Name)
+	if !errors.Is(err, savedsearch.ErrNotFound) {
+		t.Errorf("expected the run not to be recorded yet, got %v", err)
+	}
+	err = store.SaveRun(search.Name, snapshot)
+	if err != nil {
+		t.Fatalf("failed to save run: %v", err)
+	}
+
+	if !changes.FirstRun || len(changes.New) != 3 {
+		t.Fatalf("expected first run with 3 new postings, got %+v", changes)
+	}
+
+	changes, snapshot, err = savedsearch.Run(context.Background(), c, store, search, now.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("failed to run saved search: %v", err)
+	}
+	err = store.SaveRun(search.Name, snapshot)
+	if err != nil {
+		t.Fatalf("failed to save run: %v", err)
+	}
+
+	if changes.FirstRun || len(changes.New) != 0 {
+		t.Errorf("expected no new postings on the second run, got %+v", changes.New)
+	}
+
+	if len(changes.Removed) != 1 || changes.Removed[0].MatchedObjectID != "800000003" {
+		t.Errorf("expected 800000003 to be removed, got %+v", changes.Removed)
+	}
+}
+
+func TestRunPages(t *testing.T) {
+	data, err := os.ReadFile(searchTestDataPath)
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	var full usajobs.SearchResponse
+	err = json.Unmarshal(data, &full)
+	if err != nil {
+		t.Fatalf("could not decode test data: %v", err)
+	}
+	items := full.SearchResult.SearchResultItems
+
+	// the first page has two postings and the second page the third
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("ResultsPerPage") != "500" {
+			t.Errorf("expected 500 results per page, got %s", r.URL.String())
+		}
+
+		sr := full
+		sr.SearchResult.UserArea.NumberOfPages = "2"
+		sr.SearchResult.SearchResultItems = items[:2]
+		if r.URL.Query().Get("Page") == "2" {
+			sr.SearchResult.SearchResultItems = items[2:]
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(sr)
+	}))
+	defer mockServer.Close()
+
+	c, err := usajobs.NewClient("test", "test")
+	if err != nil {
+		t.Fatalf("could not create new usajobs client: %v", err)
+	}
+
+	u, err := url.Parse(mockServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+	c.BaseURL = u
+
+	store, err := savedsearch.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("could not create store: %v", err)
+	}
+
+	search := savedsearch.SavedSearch{Name: "pages", Options: usajobs.SearchOptions{Keyword: "federal"}}
+	now := time.Date(2024, 7, 10, 0, 0, 0, 0, time.UTC)
+
+	changes, snapshot, err := savedsearch.Run(context.Background(), c, store, search, now)
+	if err != nil {
+		t.Fatalf("failed to run saved search: %v", err)
+	}
+	err = store.SaveRun(search.Name, snapshot)
+	if err != nil {
+		t.Fatalf("failed to save run: %v", err)
+	}
+	if len(changes.New) != 3 {
+		t.Fatalf("expected the postings of both pages to be new, got %+v", changes.New)
+	}
+
+	changes, snapshot, err = savedsearch.Run(context.Background(), c, store, search, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("failed to run saved search: %v", err)
+	}
+	err = store.SaveRun(search.Name, snapshot)
+	if err != nil {
+		t.Fatalf("failed to save run: %v", err)
+	}
+	if !changes.Empty() {
+		t.Errorf("expected no changes between runs, got %+v", changes)
+	}
+}
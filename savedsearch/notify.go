@@ -0,0 +1,305 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package savedsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Notifier types supported by NotifierConfig.
+const (
+	NotifierWebhook = "webhook"
+	NotifierSlack   = "slack"
+	NotifierTeams   = "teams"
+	NotifierSMTP    = "smtp"
+)
+
+// Notifier delivers the changes found by a run of a saved search.
+type Notifier interface {
+	Notify(ctx context.Context, changes Changes) error
+}
+
+// NotifierConfig configures one notifier for a saved search. URL is used by
+// the webhook, slack, and teams types and SMTP by the smtp type.
+type NotifierConfig struct {
+	Type string      `json:"type"`
+	URL  string      `json:"url,omitempty"`
+	SMTP *SMTPConfig `json:"smtp,omitempty"`
+}
+
+// Notifier returns the Notifier described by the config.
+func (c NotifierConfig) Notifier() (Notifier, error) {
+	switch c.Type {
+	case NotifierWebhook, NotifierSlack, NotifierTeams:
+		if c.URL == "" {
+			return nil, fmt.Errorf("%s notifier requires a url", c.Type)
+		}
+
+		format := JSONMessage
+		if c.Type == NotifierSlack {
+			format = SlackMessage
+		} else if c.Type == NotifierTeams {
+			format = TeamsMessage
+		}
+		return &WebhookNotifier{URL: c.URL, Format: format}, nil
+	case NotifierSMTP:
+		if c.SMTP == nil {
+			return nil, fmt.Errorf("%s notifier requires smtp settings", c.Type)
+		}
+		return &SMTPNotifier{Config: *c.SMTP}, nil
+	}
+	return nil, fmt.Errorf("unknown notifier type %q", c.Type)
+}
+
+// WebhookNotifier posts the changes to a URL as JSON. Format builds the
+// request body and defaults to JSONMessage.
+type WebhookNotifier struct {
+	URL    string
+	Format func(Changes) ([]byte, error)
+	Client *http.Client
+}
+
+// Notify posts the changes to the webhook URL.
+func (w *WebhookNotifier) Notify(ctx context.Context, changes Changes) error {
+	format := w.Format
+	if format == nil {
+		format = JSONMessage
+	}
+
+	body, err := format(changes)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	r, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+	_, _ = io.Copy(io.Discard, r.Body)
+
+	if r.StatusCode < 200 || r.StatusCode > 299 {
+		return fmt.Errorf("webhook %s returned %s", w.URL, r.Status)
+	}
+	return nil
+}
+
+// Summary returns a plain text description of the changes, one line per
+// posting, for use in email and chat messages.
+func Summary(c Changes) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %d new, %d closed, %d removed, %d updated\n",
+		c.Search, len(c.New), len(c.Closed), len(c.Removed), len(c.Updated))
+
+	line := func(change string, p Posting, detail string) {
+		fmt.Fprintf(&b, "\n[%s] %s - %s (%s)\n%s\n", change, p.PositionTitle, p.DepartmentName, detail, p.PositionURI)
+	}
+
+	for _, p := range c.New {
+		line("new", p, "closes "+p.CloseDate)
+	}
+	for _, p := range c.Closed {
+		line("closed", p, "closed "+p.CloseDate)
+	}
+	for _, p := range c.Removed {
+		line("removed", p, "no longer returned by the search")
+	}
+	for _, u := range c.Updated {
+		line("updated", u.After, "changed "+strings.Join(u.Fields, ", "))
+	}
+
+	return b.String()
+}
+
+// JSONMessage formats the changes as a generic JSON document.
+func JSONMessage(c Changes) ([]byte, error) {
+	return json.Marshal(c)
+}
+
+// firstRunText describes the first run of a search, which reports every
+// posting as new, in place of listing the postings.
+func firstRunText(c Changes) string {
+	return fmt.Sprintf("First run of the search, %d postings matched. Changes will be reported from the next run.", len(c.New))
+}
+
+// slackMaxBlocks keeps Slack messages under the 50 block limit Slack
+// enforces, leaving room for the header and the "and N more" section.
+const slackMaxBlocks = 45
+
+// SlackMessage formats the changes as a Slack incoming webhook payload.
+// Postings beyond what fits in one message are counted in a final section,
+// and the first run of a search, which reports every posting as new, is
+// sent as a summary only.
+func SlackMessage(c Changes) ([]byte, error) {
+	type text struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	type block struct {
+		Type string `json:"type"`
+		Text *text  `json:"text,omitempty"`
+	}
+
+	summary := Summary(c)
+	title, _, _ := strings.Cut(summary, "\n")
+
+	var sections []string
+	if c.FirstRun {
+		sections = append(sections, firstRunText(c))
+		summary = title
+	} else {
+		for _, p := range c.New {
+			sections = append(sections, fmt.Sprintf("*New:* <%s|%s>\n%s, closes %s", p.PositionURI, p.PositionTitle, p.DepartmentName, p.CloseDate))
+		}
+		for _, p := range c.Closed {
+			sections = append(sections, fmt.Sprintf("*Closed:* <%s|%s>\n%s", p.PositionURI, p.PositionTitle, p.DepartmentName))
+		}
+		for _, p := range c.Removed {
+			sections = append(sections, fmt.Sprintf("*Removed:* <%s|%s>\n%s", p.PositionURI, p.PositionTitle, p.DepartmentName))
+		}
+		for _, u := range c.Updated {
+			sections = append(sections, fmt.Sprintf("*Updated:* <%s|%s>\n%s, changed %s", u.After.PositionURI, u.After.PositionTitle, u.After.DepartmentName, strings.Join(u.Fields, ", ")))
+		}
+	}
+
+	// one block for the header and one for the remaining count
+	if len(sections) > slackMaxBlocks-1 {
+		more := len(sections) - (slackMaxBlocks - 2)
+		sections = append(sections[:slackMaxBlocks-2], fmt.Sprintf("…and %d more", more))
+	}
+
+	blocks := []block{{Type: "header", Text: &text{Type: "plain_text", Text: "usajobs: " + title}}}
+	for _, s := range sections {
+		blocks = append(blocks, block{Type: "section", Text: &text{Type: "mrkdwn", Text: s}})
+	}
+
+	return json.Marshal(struct {
+		Text   string  `json:"text"`
+		Blocks []block `json:"blocks"`
+	}{Text: summary, Blocks: blocks})
+}
+
+// teamsMaxSections keeps Teams message cards well under the 28 KB payload
+// limit of Teams incoming webhooks.
+const teamsMaxSections = 25
+
+// TeamsMessage formats the changes as a Microsoft Teams incoming webhook
+// message card. Like SlackMessage, postings beyond teamsMaxSections are
+// counted in a final section and first runs are sent as a summary only.
+func TeamsMessage(c Changes) ([]byte, error) {
+	type fact struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+	type section struct {
+		ActivityTitle string `json:"activityTitle"`
+		Facts         []fact `json:"facts,omitempty"`
+	}
+
+	summary := Summary(c)
+	title, _, _ := strings.Cut(summary, "\n")
+
+	var sections []section
+	add := func(change string, p Posting, detail string) {
+		sections = append(sections, section{
+			ActivityTitle: fmt.Sprintf("%s: [%s](%s)", change, p.PositionTitle, p.PositionURI),
+			Facts: []fact{
+				{Name: "Department", Value: p.DepartmentName},
+				{Name: "Detail", Value: detail},
+			},
+		})
+	}
+
+	if c.FirstRun {
+		sections = append(sections, section{ActivityTitle: firstRunText(c)})
+	} else {
+		for _, p := range c.New {
+			add("New", p, "closes "+p.CloseDate)
+		}
+		for _, p := range c.Closed {
+			add("Closed", p, "closed "+p.CloseDate)
+		}
+		for _, p := range c.Removed {
+			add("Removed", p, "no longer returned by the search")
+		}
+		for _, u := range c.Updated {
+			add("Updated", u.After, "changed "+strings.Join(u.Fields, ", "))
+		}
+	}
+
+	// the last section counts the postings left out
+	if len(sections) > teamsMaxSections {
+		more := len(sections) - (teamsMaxSections - 1)
+		sections = append(sections[:teamsMaxSections-1], section{ActivityTitle: fmt.Sprintf("…and %d more", more)})
+	}
+
+	return json.Marshal(struct {
+		Type     string    `json:"@type"`
+		Context  string    `json:"@context"`
+		Summary  string    `json:"summary"`
+		Title    string    `json:"title"`
+		Sections []section `json:"sections"`
+	}{
+		Type:     "MessageCard",
+		Context:  "https://schema.org/extensions",
+		Summary:  title,
+		Title:    "usajobs: " + title,
+		Sections: sections,
+	})
+}
+
+// NotifyAll sends the changes to every notifier configured for the saved
+// search, and returns the errors of the notifiers that failed joined
+// together. A failing notifier does not stop the ones after it. Nothing is
+// sent when there are no changes.
+func NotifyAll(ctx context.Context, search SavedSearch, changes Changes) error {
+	if changes.Empty() {
+		return nil
+	}
+
+	var errs []error
+	for _, config := range search.Notify {
+		n, err := config.Notifier()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		err = n.Notify(ctx, changes)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s notifier: %w", config.Type, err))
+		}
+	}
+	return errors.Join(errs...)
+}
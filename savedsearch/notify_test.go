@@ -0,0 +1,374 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package savedsearch_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/JeffRDay/go-usajobs/savedsearch"
+)
+
+var testChanges = savedsearch.Changes{
+	Search: "it-austin",
+	New: []savedsearch.Posting{{
+		MatchedObjectID: "800000002",
+		PositionTitle:   "Software Engineer",
+		DepartmentName:  "Department of the Army",
+		CloseDate:       "2024-08-01T23:59:59.9970",
+		PositionURI:     "https://www.usajobs.gov:443/GetJob/ViewDetails/800000002",
+	}},
+	Updated: []savedsearch.PostingUpdate{{
+		Before: savedsearch.Posting{MatchedObjectID: "800000001", PositionTitle: "IT Specialist", MinimumSalary: "100"},
+		After:  savedsearch.Posting{MatchedObjectID: "800000001", PositionTitle: "IT Specialist", MinimumSalary: "110"},
+		Fields: []string{"MinimumSalary"},
+	}},
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	tests := []struct {
+		notifier string
+		check    func(t *testing.T, body map[string]any)
+	}{
+		{savedsearch.NotifierWebhook, func(t *testing.T, body map[string]any) {
+			if body["search"] != "it-austin" || len(body["new"].([]any)) != 1 || len(body["updated"].([]any)) != 1 {
+				t.Errorf("unexpected webhook body: %v", body)
+			}
+		}},
+		{savedsearch.NotifierSlack, func(t *testing.T, body map[string]any) {
+			blocks, _ := body["blocks"].([]any)
+			if !strings.HasPrefix(body["text"].(string), "it-austin: 1 new, 0 closed, 0 removed, 1 updated") || len(blocks) != 3 {
+				t.Errorf("unexpected slack body: %v", body)
+			}
+		}},
+		{savedsearch.NotifierTeams, func(t *testing.T, body map[string]any) {
+			sections, _ := body["sections"].([]any)
+			if body["@type"] != "MessageCard" || len(sections) != 2 {
+				t.Errorf("unexpected teams body: %v", body)
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notifier, func(t *testing.T) {
+			var body map[string]any
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Content-Type") != "application/json" {
+					t.Errorf("expected json content type, got %q", r.Header.Get("Content-Type"))
+				}
+				err := json.NewDecoder(r.Body).Decode(&body)
+				if err != nil {
+					t.Errorf("could not decode request body: %v", err)
+				}
+			}))
+			defer srv.Close()
+
+			search := savedsearch.SavedSearch{Name: "it-austin", Notify: []savedsearch.NotifierConfig{{Type: tt.notifier, URL: srv.URL}}}
+			err := savedsearch.NotifyAll(context.Background(), search, testChanges)
+			if err != nil {
+				t.Fatalf("could not notify: %v", err)
+			}
+
+			tt.check(t, body)
+		})
+	}
+}
+
+func TestSlackMessage(t *testing.T) {
+	var many savedsearch.Changes
+	many.Search = "it-austin"
+	for i := 0; i < 60; i++ {
+		many.New = append(many.New, savedsearch.Posting{MatchedObjectID: strconv.Itoa(i), PositionTitle: "Software Engineer"})
+	}
+
+	decode := func(c savedsearch.Changes) (string, []string) {
+		b, err := savedsearch.SlackMessage(c)
+		if err != nil {
+			t.Fatalf("could not format slack message: %v", err)
+		}
+		var body struct {
+			Text   string `json:"text"`
+			Blocks []struct {
+				Text struct {
+					Text string `json:"text"`
+				} `json:"text"`
+			} `json:"blocks"`
+		}
+		err = json.Unmarshal(b, &body)
+		if err != nil {
+			t.Fatalf("could not decode slack message: %v", err)
+		}
+		var blocks []string
+		for _, b := range body.Blocks {
+			blocks = append(blocks, b.Text.Text)
+		}
+		return body.Text, blocks
+	}
+
+	// slack rejects messages with more than 50 blocks
+	_, blocks := decode(many)
+	if len(blocks) > 50 {
+		t.Fatalf("expected at most 50 blocks, got %d", len(blocks))
+	}
+	if last := blocks[len(blocks)-1]; last != fmt.Sprintf("…and %d more", 60-(len(blocks)-2)) {
+		t.Errorf("expected a final count of the remaining postings, got %q", last)
+	}
+
+	// the first run reports every posting as new, so only the count is sent
+	many.FirstRun = true
+	text, blocks := decode(many)
+	if len(blocks) != 2 || !strings.Contains(blocks[1], "60 postings") || strings.Contains(text, "Software Engineer") {
+		t.Errorf("expected a summary of the first run, got %q %v", text, blocks)
+	}
+}
+
+func TestTeamsMessage(t *testing.T) {
+	var many savedsearch.Changes
+	many.Search = "it-austin"
+	for i := 0; i < 60; i++ {
+		many.New = append(many.New, savedsearch.Posting{
+			MatchedObjectID: strconv.Itoa(i),
+			PositionTitle:   "Software Engineer",
+			DepartmentName:  "Department of the Army",
+			PositionURI:     "https://www.usajobs.gov:443/GetJob/ViewDetails/" + strconv.Itoa(i),
+		})
+	}
+
+	decode := func(c savedsearch.Changes) []string {
+		b, err := savedsearch.TeamsMessage(c)
+		if err != nil {
+			t.Fatalf("could not format teams message: %v", err)
+		}
+		// teams rejects incoming webhook payloads over 28 KB
+		if len(b) > 28*1024 {
+			t.Errorf("expected a payload under 28 KB, got %d bytes", len(b))
+		}
+		var body struct {
+			Sections []struct {
+				ActivityTitle string `json:"activityTitle"`
+			} `json:"sections"`
+		}
+		err = json.Unmarshal(b, &body)
+		if err != nil {
+			t.Fatalf("could not decode teams message: %v", err)
+		}
+		var sections []string
+		for _, s := range body.Sections {
+			sections = append(sections, s.ActivityTitle)
+		}
+		return sections
+	}
+
+	sections := decode(many)
+	if len(sections) >= 60 {
+		t.Fatalf("expected the sections to be capped, got %d", len(sections))
+	}
+	if last := sections[len(sections)-1]; last != fmt.Sprintf("…and %d more", 60-(len(sections)-1)) {
+		t.Errorf("expected a final count of the remaining postings, got %q", last)
+	}
+
+	many.FirstRun = true
+	sections = decode(many)
+	if len(sections) != 1 || !strings.Contains(sections[0], "60 postings") {
+		t.Errorf("expected a summary of the first run, got %v", sections)
+	}
+}
+
+func TestWebhookNotifierError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	n := &savedsearch.WebhookNotifier{URL: srv.URL}
+	err := n.Notify(context.Background(), testChanges)
+	if err == nil || !strings.Contains(err.Error(), "400") {
+		t.Errorf("expected bad request error, got %v", err)
+	}
+}
+
+func TestNotifyAllContinuesAfterError(t *testing.T) {
+	var posts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts = append(posts, r.URL.Path)
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	search := savedsearch.SavedSearch{Name: "it-austin", Notify: []savedsearch.NotifierConfig{
+		{Type: savedsearch.NotifierWebhook, URL: srv.URL + "/first"},
+		{Type: savedsearch.NotifierWebhook, URL: srv.URL + "/fail"},
+		{Type: savedsearch.NotifierSlack},
+		{Type: savedsearch.NotifierWebhook, URL: srv.URL + "/last"},
+	}}
+	err := savedsearch.NotifyAll(context.Background(), search, testChanges)
+	if err == nil || !strings.Contains(err.Error(), "500") || !strings.Contains(err.Error(), "url") {
+		t.Errorf("expected the errors of both failing notifiers, got %v", err)
+	}
+
+	if strings.Join(posts, ",") != "/first,/fail,/last" {
+		t.Errorf("expected every notifier to be called, got %v", posts)
+	}
+}
+
+func TestNotifyAllSkipsEmpty(t *testing.T) {
+	search := savedsearch.SavedSearch{Name: "empty", Notify: []savedsearch.NotifierConfig{{Type: savedsearch.NotifierWebhook, URL: "http://127.0.0.1:1"}}}
+	err := savedsearch.NotifyAll(context.Background(), search, savedsearch.Changes{Search: "empty"})
+	if err != nil {
+		t.Errorf("expected no notification for empty changes, got %v", err)
+	}
+}
+
+func TestNotifierConfig(t *testing.T) {
+	for _, c := range []savedsearch.NotifierConfig{
+		{Type: savedsearch.NotifierSlack},
+		{Type: savedsearch.NotifierSMTP},
+		{Type: "pager"},
+	} {
+		_, err := c.Notifier()
+		if err == nil {
+			t.Errorf("expected error for %+v", c)
+		}
+	}
+}
+
+// smtpServer is a minimal SMTP server that records the messages it receives.
+type smtpServer struct {
+	listener net.Listener
+	mu       sync.Mutex
+	rcpts    []string
+	messages []string
+}
+
+func newSMTPServer(t *testing.T) *smtpServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not listen: %v", err)
+	}
+
+	s := &smtpServer{listener: l}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { l.Close() })
+	return s
+}
+
+func (s *smtpServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			s.mu.Lock()
+			s.rcpts = append(s.rcpts, strings.Trim(strings.TrimSpace(line)[len("RCPT TO:"):], "<>"))
+			s.mu.Unlock()
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 end data with <CR><LF>.<CR><LF>")
+			var msg strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				msg.WriteString(l)
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, msg.String())
+			s.mu.Unlock()
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	srv := newSMTPServer(t)
+	host, port, _ := net.SplitHostPort(srv.listener.Addr().String())
+	p, _ := strconv.Atoi(port)
+
+	n := &savedsearch.SMTPNotifier{Config: savedsearch.SMTPConfig{
+		Host: host,
+		Port: p,
+		From: "usajobs@example.com",
+		To:   []string{"one@example.com", "two@example.com"},
+	}}
+
+	err := n.Notify(context.Background(), testChanges)
+	if err != nil {
+		t.Fatalf("could not send email: %v", err)
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	if len(srv.rcpts) != 2 || srv.rcpts[1] != "two@example.com" {
+		t.Errorf("expected two recipients, got %v", srv.rcpts)
+	}
+
+	if len(srv.messages) != 1 {
+		t.Fatalf("expected one message, got %d", len(srv.messages))
+	}
+
+	msg := srv.messages[0]
+	for _, want := range []string{
+		"Subject: usajobs: it-austin: 1 new, 0 closed, 0 removed, 1 updated",
+		"To: one@example.com, two@example.com",
+		"[new] Software Engineer - Department of the Army",
+		"[updated] IT Specialist",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected message to contain %q, got:\n%s", want, msg)
+		}
+	}
+}
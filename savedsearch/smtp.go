@@ -0,0 +1,142 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package savedsearch
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultSMTPPort is the mail submission port used when SMTPConfig.Port is
+// not set.
+const defaultSMTPPort = 587
+
+// SMTPConfig configures email notifications. The password is read from the
+// environment variable named by PasswordEnv so it is never written to the
+// saved search file.
+type SMTPConfig struct {
+	Host        string   `json:"host"`
+	Port        int      `json:"port,omitempty"`
+	Username    string   `json:"username,omitempty"`
+	PasswordEnv string   `json:"passwordEnv,omitempty"`
+	From        string   `json:"from"`
+	To          []string `json:"to"`
+}
+
+// SMTPNotifier emails a plain text summary of the changes. STARTTLS is used
+// when the server offers it.
+type SMTPNotifier struct {
+	Config SMTPConfig
+}
+
+// Notify sends the changes to every recipient in the config.
+func (n *SMTPNotifier) Notify(ctx context.Context, changes Changes) error {
+	c := n.Config
+	if c.Host == "" || c.From == "" || len(c.To) == 0 {
+		return errors.New("smtp notifier requires a host, from address, and at least one recipient")
+	}
+
+	port := c.Port
+	if port == 0 {
+		port = defaultSMTPPort
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(c.Host, strconv.Itoa(port)))
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, c.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		err = client.StartTLS(&tls.Config{ServerName: c.Host})
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.Username != "" {
+		err = client.Auth(smtp.PlainAuth("", c.Username, os.Getenv(c.PasswordEnv), c.Host))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = client.Mail(c.From)
+	if err != nil {
+		return err
+	}
+
+	for _, to := range c.To {
+		err = client.Rcpt(to)
+		if err != nil {
+			return fmt.Errorf("%s: %w", to, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(emailMessage(c, changes, time.Now()))
+	if err != nil {
+		return err
+	}
+
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// emailMessage returns the headers and body of the notification email.
+func emailMessage(c SMTPConfig, changes Changes, now time.Time) []byte {
+	summary := Summary(changes)
+	title, _, _ := strings.Cut(summary, "\n")
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", c.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(c.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", "usajobs: "+title))
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(summary, "\n", "\r\n"))
+
+	return []byte(b.String())
+}
@@ -0,0 +1,263 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package savedsearch stores usajobs search options under a name, records the
+// postings each run returned, and reports what changed between runs.
+package savedsearch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+)
+
+// ErrNotFound is returned when a saved search or its last run does not exist.
+var ErrNotFound = errors.New("saved search not found")
+
+// validName limits saved search names to characters that are safe to use as
+// file names on every platform.
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// SavedSearch is a named set of search options and the notifiers that are
+// sent its changes.
+type SavedSearch struct {
+	Name    string                `json:"name"`
+	Options usajobs.SearchOptions `json:"options"`
+	Notify  []NotifierConfig      `json:"notify,omitempty"`
+}
+
+// Posting is the part of a search result a saved search keeps between runs
+// to detect changes.
+type Posting struct {
+	MatchedObjectID string `json:"matchedObjectId"`
+	PositionID      string `json:"positionId,omitempty"`
+	PositionTitle   string `json:"positionTitle,omitempty"`
+	DepartmentName  string `json:"departmentName,omitempty"`
+	CloseDate       string `json:"closeDate,omitempty"`
+	MinimumSalary   string `json:"minimumSalary,omitempty"`
+	MaximumSalary   string `json:"maximumSalary,omitempty"`
+	PositionURI     string `json:"positionUri,omitempty"`
+}
+
+// NewPosting returns the Posting for a search result.
+func NewPosting(item usajobs.SearchResultItem) Posting {
+	d := item.MatchedObjectDescriptor
+	p := Posting{
+		MatchedObjectID: item.MatchedObjectID,
+		PositionID:      d.PositionID,
+		PositionTitle:   d.PositionTitle,
+		DepartmentName:  d.DepartmentName,
+		CloseDate:       d.ApplicationCloseDate,
+		PositionURI:     d.PositionURI,
+	}
+
+	if len(d.PositionRemuneration) > 0 {
+		p.MinimumSalary = d.PositionRemuneration[0].MinimumRange
+		p.MaximumSalary = d.PositionRemuneration[0].MaximumRange
+	}
+
+	return p
+}
+
+// Snapshot is the set of postings a saved search returned on one run, keyed
+// by MatchedObjectID.
+type Snapshot struct {
+	RunAt    time.Time          `json:"runAt"`
+	Postings map[string]Posting `json:"postings"`
+}
+
+// NewSnapshot returns a Snapshot of the provided search results.
+func NewSnapshot(runAt time.Time, items []usajobs.SearchResultItem) Snapshot {
+	s := Snapshot{RunAt: runAt, Postings: make(map[string]Posting, len(items))}
+	for _, item := range items {
+		s.Postings[item.MatchedObjectID] = NewPosting(item)
+	}
+	return s
+}
+
+// Store keeps saved searches and their last run as JSON files in a
+// directory: searches/<name>.json and runs/<name>.json.
+type Store struct {
+	Dir string
+}
+
+// DefaultDir returns the directory saved searches are kept in when one is
+// not provided, usually ~/.config/usajobs.
+func DefaultDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "usajobs"), nil
+}
+
+// NewStore returns a Store rooted at dir, creating the directory if needed.
+func NewStore(dir string) (*Store, error) {
+	for _, sub := range []string{"searches", "runs"} {
+		err := os.MkdirAll(filepath.Join(dir, sub), 0o700)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &Store{Dir: dir}, nil
+}
+
+// Save creates or replaces a saved search.
+func (s *Store) Save(search SavedSearch) error {
+	err := checkName(search.Name)
+	if err != nil {
+		return err
+	}
+	return writeJSON(s.searchPath(search.Name), search)
+}
+
+// Get returns the saved search with the provided name.
+func (s *Store) Get(name string) (SavedSearch, error) {
+	var search SavedSearch
+	err := checkName(name)
+	if err != nil {
+		return search, err
+	}
+	err = readJSON(s.searchPath(name), &search)
+	return search, err
+}
+
+// List returns every saved search sorted by name.
+func (s *Store) List() ([]SavedSearch, error) {
+	files, err := filepath.Glob(filepath.Join(s.Dir, "searches", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var searches []SavedSearch
+	for _, f := range files {
+		var search SavedSearch
+		err := readJSON(f, &search)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f, err)
+		}
+		searches = append(searches, search)
+	}
+	return searches, nil
+}
+
+// Delete removes a saved search and its last run.
+func (s *Store) Delete(name string) error {
+	err := checkName(name)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(s.searchPath(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(s.runPath(name))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// LastRun returns the snapshot recorded by the previous run of a saved
+// search, or ErrNotFound if it has never been run.
+func (s *Store) LastRun(name string) (Snapshot, error) {
+	var snapshot Snapshot
+	err := checkName(name)
+	if err != nil {
+		return snapshot, err
+	}
+	err = readJSON(s.runPath(name), &snapshot)
+	return snapshot, err
+}
+
+// SaveRun records the snapshot of the latest run of a saved search.
+func (s *Store) SaveRun(name string, snapshot Snapshot) error {
+	err := checkName(name)
+	if err != nil {
+		return err
+	}
+	return writeJSON(s.runPath(name), snapshot)
+}
+
+// checkName keeps names that are not safe file names, such as "../x", from
+// being joined into a path under the store directory.
+func checkName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid saved search name %q, use letters, numbers, '.', '-', and '_'", name)
+	}
+	return nil
+}
+
+func (s *Store) searchPath(name string) string {
+	return filepath.Join(s.Dir, "searches", name+".json")
+}
+
+func (s *Store) runPath(name string) string {
+	return filepath.Join(s.Dir, "runs", name+".json")
+}
+
+func readJSON(path string, v any) error {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// writeJSON writes to a temporary file first so an interrupted write never
+// leaves a truncated file behind.
+func writeJSON(path string, v any) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+strings.TrimSuffix(filepath.Base(path), ".json")+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(b)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
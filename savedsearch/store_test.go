@@ -0,0 +1,154 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package savedsearch_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+	"github.com/JeffRDay/go-usajobs/savedsearch"
+)
+
+func TestStore(t *testing.T) {
+	store, err := savedsearch.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("could not create store: %v", err)
+	}
+
+	err = store.Save(savedsearch.SavedSearch{
+		Name:    "it-austin",
+		Options: usajobs.SearchOptions{Keyword: "software", JobCategoryCode: []string{"2210"}},
+	})
+	if err != nil {
+		t.Fatalf("could not save search: %v", err)
+	}
+
+	err = store.Save(savedsearch.SavedSearch{Name: "cyber", Options: usajobs.SearchOptions{Keyword: "cyber"}})
+	if err != nil {
+		t.Fatalf("could not save search: %v", err)
+	}
+
+	err = store.Save(savedsearch.SavedSearch{Name: "../escape"})
+	if err == nil {
+		t.Fatal("expected error for invalid name, got nil")
+	}
+
+	search, err := store.Get("it-austin")
+	if err != nil {
+		t.Fatalf("could not get search: %v", err)
+	}
+
+	if search.Options.JobCategoryCode[0] != "2210" {
+		t.Errorf("expected saved options to round trip, got %+v", search.Options)
+	}
+
+	searches, err := store.List()
+	if err != nil {
+		t.Fatalf("could not list searches: %v", err)
+	}
+
+	if len(searches) != 2 || searches[0].Name != "cyber" {
+		t.Fatalf("expected 2 searches sorted by name, got %+v", searches)
+	}
+
+	_, err = store.LastRun("cyber")
+	if !errors.Is(err, savedsearch.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound before the first run, got %v", err)
+	}
+
+	run := savedsearch.Snapshot{
+		RunAt:    time.Date(2024, 7, 10, 0, 0, 0, 0, time.UTC),
+		Postings: map[string]savedsearch.Posting{"1": {MatchedObjectID: "1"}},
+	}
+	err = store.SaveRun("cyber", run)
+	if err != nil {
+		t.Fatalf("could not save run: %v", err)
+	}
+
+	last, err := store.LastRun("cyber")
+	if err != nil {
+		t.Fatalf("could not read last run: %v", err)
+	}
+
+	if !last.RunAt.Equal(run.RunAt) || len(last.Postings) != 1 {
+		t.Errorf("expected last run to round trip, got %+v", last)
+	}
+
+	err = store.Delete("cyber")
+	if err != nil {
+		t.Fatalf("could not delete search: %v", err)
+	}
+
+	_, err = store.Get("cyber")
+	if !errors.Is(err, savedsearch.ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
+	}
+
+	_, err = store.LastRun("cyber")
+	if !errors.Is(err, savedsearch.ErrNotFound) {
+		t.Errorf("expected last run to be deleted, got %v", err)
+	}
+
+	err = store.Delete("cyber")
+	if !errors.Is(err, savedsearch.ErrNotFound) {
+		t.Errorf("expected ErrNotFound deleting a missing search, got %v", err)
+	}
+}
+
+func TestStoreInvalidName(t *testing.T) {
+	dir := t.TempDir()
+	store, err := savedsearch.NewStore(filepath.Join(dir, "store"))
+	if err != nil {
+		t.Fatalf("could not create store: %v", err)
+	}
+
+	// a file outside the searches directory that "../x" would otherwise reach
+	err = os.WriteFile(filepath.Join(dir, "store", "x.json"), []byte(`{"name": "x"}`), 0o600)
+	if err != nil {
+		t.Fatalf("could not write file: %v", err)
+	}
+
+	for _, name := range []string{"../x", "", "a/b", ".hidden"} {
+		_, err = store.Get(name)
+		if err == nil {
+			t.Errorf("Get(%q): expected error for invalid name, got nil", name)
+		}
+
+		err = store.Delete(name)
+		if err == nil {
+			t.Errorf("Delete(%q): expected error for invalid name, got nil", name)
+		}
+
+		_, err = store.LastRun(name)
+		if err == nil {
+			t.Errorf("LastRun(%q): expected error for invalid name, got nil", name)
+		}
+
+		err = store.SaveRun(name, savedsearch.Snapshot{})
+		if err == nil {
+			t.Errorf("SaveRun(%q): expected error for invalid name, got nil", name)
+		}
+	}
+
+	_, err = os.Stat(filepath.Join(dir, "store", "x.json"))
+	if err != nil {
+		t.Errorf("expected file outside the store to be left alone, got %v", err)
+	}
+}
@@ -0,0 +1,279 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package server is a local HTTP JSON API in front of a usajobs Client, so
+// many applications can share one api token and, with a ResponseCache and
+// RateLimiter installed on the Client, one cache and one rate limit.
+//
+// /search and /codelist/{name} mirror the usajobs api, passing the query
+// through and the response back unchanged. The typed endpoints flatten
+// postings to the search item fields:
+//
+//	GET /v1/postings          search, with the /search parameters
+//	GET /v1/postings/{id}     a posting by MatchedObjectID or PositionID
+//	GET /v1/codelists         the names of the codelists
+//	GET /v1/codelists/{name}  the values of a codelist
+//	GET /healthz              status and cache statistics
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+)
+
+// Server serves the usajobs api through a Client.
+type Server struct {
+	client *usajobs.Client
+	mux    *http.ServeMux
+
+	// Cache is reported by /healthz when it is not nil.
+	Cache *usajobs.ResponseCache
+}
+
+// Postings is the response of /v1/postings.
+type Postings struct {
+	Count    int                 `json:"count"`
+	CountAll int                 `json:"countAll"`
+	Page     int                 `json:"page"`
+	Pages    int                 `json:"pages"`
+	Items    []map[string]string `json:"items"`
+}
+
+// New returns a Server that requests the usajobs api with c.
+func New(c *usajobs.Client) *Server {
+	s := &Server{client: c, mux: http.NewServeMux()}
+
+	s.mux.HandleFunc("GET /search", s.proxy)
+	s.mux.HandleFunc("GET /codelist/{name}", s.proxyCodeList)
+	s.mux.HandleFunc("GET /v1/postings", s.postings)
+	s.mux.HandleFunc("GET /v1/postings/{id}", s.posting)
+	s.mux.HandleFunc("GET /v1/codelists", s.codeLists)
+	s.mux.HandleFunc("GET /v1/codelists/{name}", s.codeList)
+	s.mux.HandleFunc("GET /healthz", s.health)
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no endpoint %s %s", r.Method, r.URL.Path))
+	})
+	return s
+}
+
+// ServeHTTP serves a request.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// proxy passes a request through to the same path of the usajobs api.
+func (s *Server) proxy(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.EscapedPath()
+	if r.URL.RawQuery != "" {
+		path += "?" + r.URL.RawQuery
+	}
+
+	req, err := s.client.NewRequestWithContext(r.Context(), http.MethodGet, path)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp, err := s.client.Client.Do(req)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	for _, h := range []string{"Content-Type", usajobs.CacheHeader} {
+		if v := resp.Header.Get(h); v != "" {
+			w.Header().Set(h, v)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	io.Copy(w, resp.Body)
+}
+
+func (s *Server) proxyCodeList(w http.ResponseWriter, r *http.Request) {
+	if !knownCodeList(r.PathValue("name")) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no codelist %s", r.PathValue("name")))
+		return
+	}
+	s.proxy(w, r)
+}
+
+func (s *Server) postings(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	fields, err := searchItemFields(query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	opt, err := usajobs.ParseSearchOptions(query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	resp, data, err := s.client.Search.WithOptionsContext(r.Context(), &opt)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	if resp.StatusCode != http.StatusOK {
+		writeError(w, http.StatusBadGateway, errors.New("bad response from usajobs: "+resp.Status))
+		return
+	}
+
+	page := max(opt.Page, 1)
+	p := Postings{
+		Count:    data.SearchResult.SearchResultCount,
+		CountAll: data.SearchResult.SearchResultCountAll,
+		Page:     page,
+		Pages:    data.PageCount(),
+		Items:    []map[string]string{},
+	}
+	for _, item := range data.SearchResult.SearchResultItems {
+		p.Items = append(p.Items, flatten(item, fields))
+	}
+	writeJSON(w, resp.Header.Get(usajobs.CacheHeader), p)
+}
+
+func (s *Server) posting(w http.ResponseWriter, r *http.Request) {
+	fields, err := searchItemFields(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	item, err := s.client.Search.ByID(r.Context(), r.PathValue("id"))
+	if errors.Is(err, usajobs.ErrPostingNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeJSON(w, "", flatten(*item, fields))
+}
+
+func (s *Server) codeLists(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, "", usajobs.CodeListNames)
+}
+
+// codeList returns the enabled values of a codelist, or every value with
+// disabled=true. contains and parent filter the values as CodeListFilter
+// does.
+func (s *Server) codeList(w http.ResponseWriter, r *http.Request) {
+	name := strings.ToLower(r.PathValue("name"))
+	if !knownCodeList(name) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no codelist %s", name))
+		return
+	}
+
+	query := r.URL.Query()
+	filter := usajobs.CodeListFilter{
+		Contains:        query.Get("contains"),
+		Parent:          query.Get("parent"),
+		IncludeDisabled: query.Get("disabled") == "true",
+	}
+
+	resp, data, err := s.client.CodeListContext(r.Context(), name)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	if resp.StatusCode != http.StatusOK {
+		writeError(w, http.StatusBadGateway, errors.New("bad response from usajobs: "+resp.Status))
+		return
+	}
+
+	values := data.Filter(filter).Values()
+	if values == nil {
+		values = []usajobs.CodeListValue{}
+	}
+	writeJSON(w, resp.Header.Get(usajobs.CacheHeader), values)
+}
+
+func (s *Server) health(w http.ResponseWriter, r *http.Request) {
+	status := struct {
+		Status string              `json:"status"`
+		Cache  *usajobs.CacheStats `json:"cache,omitempty"`
+	}{Status: "ok"}
+
+	if s.Cache != nil {
+		stats := s.Cache.Stats()
+		status.Cache = &stats
+	}
+	writeJSON(w, "", status)
+}
+
+// searchItemFields returns the fields listed by the columns parameter,
+// removing it from query, or every field without one.
+func searchItemFields(query url.Values) ([]usajobs.SearchItemField, error) {
+	var names []string
+	for k, v := range query {
+		if strings.EqualFold(k, "columns") {
+			for _, c := range v {
+				names = append(names, strings.Split(c, ",")...)
+			}
+			query.Del(k)
+		}
+	}
+
+	if len(names) == 0 {
+		return usajobs.SearchItemFields, nil
+	}
+	return usajobs.LookupSearchItemFields(names)
+}
+
+// flatten returns the fields of a posting keyed by their lower case names
+// (ex., job_title).
+func flatten(item usajobs.SearchResultItem, fields []usajobs.SearchItemField) map[string]string {
+	m := make(map[string]string, len(fields))
+	for _, f := range fields {
+		m[strings.ToLower(f.Name)] = f.Value(item)
+	}
+	return m
+}
+
+func knownCodeList(name string) bool {
+	return slices.Contains(usajobs.CodeListNames, strings.ToLower(name))
+}
+
+// writeJSON writes v as the response, with the cache header of the usajobs
+// response it came from when there is one.
+func writeJSON(w http.ResponseWriter, cache string, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if cache != "" {
+		w.Header().Set(usajobs.CacheHeader, cache)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,260 @@
+/*
+Copyright © 2024 Jeff Day jeffrey.day33@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package server_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	usajobs "github.com/JeffRDay/go-usajobs/client"
+	"github.com/JeffRDay/go-usajobs/server"
+)
+
+// newServer returns a server in front of a mock usajobs api serving the test
+// data, and the number of requests the mock api received.
+func newServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("Authorization-Key") != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		name := "search"
+		if r.URL.Path != "/search" {
+			var ok bool
+			name, ok = strings.CutPrefix(r.URL.Path, "/codelist/")
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+		}
+		if r.URL.Query().Get("Keyword") == "unavailable" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		data, err := os.ReadFile("../testdata/" + name + "-testdata.json")
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	}))
+	t.Cleanup(upstream.Close)
+
+	c, err := usajobs.NewClient("test", "token")
+	if err != nil {
+		t.Fatalf("could not create new usajobs client: %v", err)
+	}
+
+	u, err := url.Parse(upstream.URL)
+	if err != nil {
+		t.Fatalf("failed to parse mock server url: %v", err)
+	}
+	c.BaseURL = u
+
+	cache := usajobs.NewResponseCache(usajobs.NewRateLimiter(c.Client.Transport, 100, 10), time.Minute, 100)
+	c.Client.Transport = cache
+
+	s := server.New(c)
+	s.Cache = cache
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+// get requests path from the server, decoding the json response into v
+// when it is not nil, and returns the response.
+func get(t *testing.T, srv *httptest.Server, path string, v any) *http.Response {
+	t.Helper()
+
+	r, err := http.Get(srv.URL + path)
+	if err != nil {
+		t.Fatalf("failed to get %s: %v", path, err)
+	}
+	defer r.Body.Close()
+
+	if v != nil {
+		err = json.NewDecoder(r.Body).Decode(v)
+		if err != nil {
+			t.Fatalf("could not decode %s: %v", path, err)
+		}
+	}
+	return r
+}
+
+func TestServerProxy(t *testing.T) {
+	srv, requests := newServer(t)
+
+	want, err := os.ReadFile("../testdata/search-testdata.json")
+	if err != nil {
+		t.Fatalf("could not read test data: %v", err)
+	}
+
+	for i, cache := range []string{"MISS", "HIT"} {
+		r, err := http.Get(srv.URL + "/search?Keyword=nurse&ResultsPerPage=10")
+		if err != nil {
+			t.Fatalf("failed to search: %v", err)
+		}
+		body, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			t.Fatalf("failed to read search: %v", err)
+		}
+
+		if r.StatusCode != http.StatusOK || string(body) != string(want) {
+			t.Errorf("search %d: expected the usajobs response unchanged, got %s", i+1, r.Status)
+		}
+		if got := r.Header.Get(usajobs.CacheHeader); got != cache {
+			t.Errorf("search %d: got %s %s, want %s", i+1, usajobs.CacheHeader, got, cache)
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("expected 1 request to usajobs, got %d", n)
+	}
+
+	var codes usajobs.CodeListResponse
+	r := get(t, srv, "/codelist/securityclearances", &codes)
+	if r.StatusCode != http.StatusOK || len(codes.Values()) == 0 {
+		t.Errorf("expected the securityclearances codelist, got %s with %d values", r.Status, len(codes.Values()))
+	}
+
+	var e map[string]string
+	r = get(t, srv, "/codelist/passwords", &e)
+	if r.StatusCode != http.StatusNotFound || e["error"] == "" {
+		t.Errorf("expected a not found error, got %s %v", r.Status, e)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("expected unknown codelists not to be requested, got %d requests", n)
+	}
+}
+
+func TestServerPostings(t *testing.T) {
+	srv, requests := newServer(t)
+
+	// consumers searching at once share one request to usajobs
+	var wg sync.WaitGroup
+	results := make([]server.Postings, 5)
+	errs := make([]error, len(results))
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r, err := http.Get(srv.URL + "/v1/postings?keyword=engineer&JobCategoryCode=2210;0854&columns=job_title,department")
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			defer r.Body.Close()
+			errs[i] = json.NewDecoder(r.Body).Decode(&results[i])
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("failed to search: %v", err)
+		}
+	}
+
+	for _, p := range results {
+		if p.Count != 3 || p.Page != 1 || len(p.Items) != 3 {
+			t.Fatalf("unexpected postings: %+v", p)
+		}
+		want := map[string]string{"job_title": "IT Specialist (INFOSEC)", "department": "Department of Homeland Security"}
+		if got := p.Items[0]; len(got) != 2 || got["job_title"] != want["job_title"] || got["department"] != want["department"] {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("expected 1 request to usajobs, got %d", n)
+	}
+
+	var posting map[string]string
+	r := get(t, srv, "/v1/postings/ASF-24-0042", &posting)
+	if r.StatusCode != http.StatusOK || posting["matched_object_id"] != "800000002" || posting["job_title"] != "Software Engineer" {
+		t.Errorf("unexpected posting: %s %v", r.Status, posting)
+	}
+
+	for path, status := range map[string]int{
+		"/v1/postings/123":                   http.StatusNotFound,
+		"/v1/postings?Salary=100000":         http.StatusBadRequest,
+		"/v1/postings?columns=shoe_size":     http.StatusBadRequest,
+		"/v1/postings?Keyword=unavailable":   http.StatusBadGateway,
+		"/v1/postings/ASF-24-0042?columns=x": http.StatusBadRequest,
+		"/v2/postings":                       http.StatusNotFound,
+	} {
+		var e map[string]string
+		r := get(t, srv, path, &e)
+		if r.StatusCode != status || e["error"] == "" {
+			t.Errorf("%s: expected %d with an error, got %s %v", path, status, r.Status, e)
+		}
+	}
+}
+
+func TestServerCodeLists(t *testing.T) {
+	srv, _ := newServer(t)
+
+	var names []string
+	get(t, srv, "/v1/codelists", &names)
+	if len(names) != len(usajobs.CodeListNames) {
+		t.Errorf("expected %d codelists, got %d", len(usajobs.CodeListNames), len(names))
+	}
+
+	var values []usajobs.CodeListValue
+	r := get(t, srv, "/v1/codelists/SecurityClearances?contains=secret", &values)
+	if r.StatusCode != http.StatusOK {
+		t.Fatalf("failed to get codelist: %s", r.Status)
+	}
+	var got []string
+	for _, v := range values {
+		got = append(got, v.Value)
+	}
+	if strings.Join(got, ",") != "Secret,Top Secret" {
+		t.Errorf("expected the secret clearances, got %v", got)
+	}
+
+	var e map[string]string
+	r = get(t, srv, "/v1/codelists/passwords", &e)
+	if r.StatusCode != http.StatusNotFound {
+		t.Errorf("expected not found, got %s", r.Status)
+	}
+
+	get(t, srv, "/v1/codelists/securityclearances", &values)
+	var health struct {
+		Status string             `json:"status"`
+		Cache  usajobs.CacheStats `json:"cache"`
+	}
+	get(t, srv, "/healthz", &health)
+	if health.Status != "ok" || health.Cache != (usajobs.CacheStats{Hits: 1, Misses: 1, Entries: 1}) {
+		t.Errorf("unexpected health: %+v", health)
+	}
+}